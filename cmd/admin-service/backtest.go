@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/clients"
+	"github.com/kodra-pay/admin-service/internal/config"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/services"
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+const backtestUsage = `usage: admin-service [flags] backtest -from TIME [-to TIME] (-rule ID | -expression EXPR)
+
+Backtests a fraud rule over the transactions created in [from, to) and prints
+the outcome as JSON. Times are RFC 3339 timestamps or dates such as 2024-03-01
+(midnight UTC); to defaults to now.`
+
+// errUsage reports that the backtest arguments were invalid; the problem has
+// already been printed with the usage.
+var errUsage = errors.New("invalid usage")
+
+// runBacktest implements the backtest subcommand.
+func runBacktest(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), backtestUsage) }
+	ruleID := fs.Int64("rule", 0, "ID of a saved rule to backtest at its current version")
+	expression := fs.String("expression", "", "unsaved rule expression to backtest")
+	from := fs.String("from", "", "start of the range, inclusive")
+	to := fs.String("to", "", "end of the range, exclusive")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if (*ruleID == 0) == (*expression == "") {
+		fmt.Fprintln(fs.Output(), "exactly one of -rule and -expression is required")
+		fs.Usage()
+		return errUsage
+	}
+
+	var (
+		req dto.BacktestRequest
+		err error
+	)
+	req.Expression = *expression
+	if req.From, err = parseBacktestTime(*from); err != nil {
+		return fmt.Errorf("-from: %w", err)
+	}
+	if req.To, err = parseBacktestTime(*to); err != nil {
+		return fmt.Errorf("-to: %w", err)
+	}
+
+	repo, err := repositories.NewAdminRepository(cfg.PostgresDSN)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+	settingsStore, err := settings.NewStore(cfg.SettingsFile)
+	if err != nil {
+		return err
+	}
+	txClient := clients.NewHTTPTransactionClient(cfg.TransactionServiceURL)
+	defer txClient.Close()
+	svc := services.NewAdminService(repo, cfg.MerchantServiceURL, cfg.ComplianceServiceURL, txClient, settingsStore)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if err := waitAvailable(ctx, repo, time.Minute); err != nil {
+		return err
+	}
+
+	var resp dto.BacktestResponse
+	if *ruleID != 0 {
+		resp, err = svc.BacktestRule(ctx, *ruleID, req)
+	} else {
+		resp, err = svc.BacktestExpression(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(resp)
+}
+
+// parseBacktestTime parses an RFC 3339 timestamp or a date; empty is the zero time.
+func parseBacktestTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
+
+// waitAvailable waits up to timeout for the repository's first successful
+// connection.
+func waitAvailable(ctx context.Context, repo *repositories.AdminRepository, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for !repo.Available() {
+		select {
+		case <-ctx.Done():
+			return errors.New("failed to connect to database")
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -1,22 +1,83 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/kodra-pay/admin-service/internal/config"
+	"github.com/kodra-pay/admin-service/internal/handlers"
+	"github.com/kodra-pay/admin-service/internal/logging"
+	"github.com/kodra-pay/admin-service/internal/metrics"
 	"github.com/kodra-pay/admin-service/internal/middleware"
 	"github.com/kodra-pay/admin-service/internal/routes"
+	"github.com/kodra-pay/admin-service/internal/tracing"
 )
 
 func main() {
-	cfg := config.Load("admin-service", "7003")
+	cfg, err := config.Load("admin-service", "7003", os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	level, _ := logging.ParseLevel(cfg.LogLevel) // validated by config.Load
+	slog.SetDefault(logging.New(os.Stdout, level, cfg.LogFormat).With("service", cfg.ServiceName))
+
+	if len(cfg.Args) > 0 {
+		switch cfg.Args[0] {
+		case "migrate":
+			if len(cfg.Args) < 2 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				os.Exit(2)
+			}
+			if err := runMigrate(cfg, cfg.Args[1:]); err != nil {
+				fatal("migration failed", err)
+			}
+		case "backtest":
+			err := runBacktest(cfg, cfg.Args[1:])
+			switch {
+			case errors.Is(err, flag.ErrHelp):
+				return
+			case errors.Is(err, errUsage):
+				os.Exit(2)
+			case err != nil:
+				fatal("backtest failed", err)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", cfg.Args[0])
+			os.Exit(2)
+		}
+		return
+	}
 
-	app := fiber.New()
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.ServiceName, cfg.TracingExporter, cfg.TracingEndpoint, cfg.TracingSampleRatio)
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+
+	// c.IP() reads X-Forwarded-For only from trusted proxies, so clients
+	// can't choose the address they are rate limited by
+	app := fiber.New(fiber.Config{
+		ErrorHandler:            handlers.ErrorHandler,
+		DisableStartupMessage:   true,
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.TrustedProxies,
+		EnableIPValidation:      true,
+	})
 	app.Use(recover.New())
-	app.Use(logger.New())
 
 	// Enable CORS for frontend access
 	// app.Use(cors.New(cors.Config{
@@ -27,11 +88,59 @@ func main() {
 	// }))
 
 	app.Use(middleware.RequestID())
+	app.Use(tracing.Middleware())
+	app.Use(middleware.AccessLog())
+	app.Use(metrics.Middleware())
+
+	resources, err := routes.Register(app, cfg)
+	if err != nil {
+		fatal("failed to register routes", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	routes.Register(app, cfg.ServiceName, cfg.MerchantServiceURL)
+	listenErr := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "port", cfg.Port)
+		listenErr <- app.Listen(":" + cfg.Port)
+	}()
 
-	log.Printf("%s listening on :%s", cfg.ServiceName, cfg.Port)
-	if err := app.Listen(":" + cfg.Port); err != nil {
-		log.Fatal(err)
+	select {
+	case err := <-listenErr:
+		if err != nil {
+			fatal("server stopped", err)
+		}
+	case <-ctx.Done():
+		stop()
+		shutdown(app, resources, cfg.ShutdownDelay, cfg.ShutdownTimeout)
 	}
+
+	// Flush buffered spans
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
+	}
+}
+
+// shutdown fails readiness, waits for load balancers to notice, drains
+// in-flight requests and then releases the database and clients.
+func shutdown(app *fiber.App, resources *routes.Resources, delay, timeout time.Duration) {
+	slog.Info("shutdown signal received, draining", "delay", delay.String(), "timeout", timeout.String())
+	resources.Drain()
+	time.Sleep(delay)
+
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		slog.Warn("in-flight requests did not finish before the deadline", "error", err)
+	}
+	if err := resources.Close(); err != nil {
+		slog.Warn("failed to close resources", "error", err)
+	}
+	slog.Info("shutdown complete")
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
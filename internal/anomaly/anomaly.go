@@ -0,0 +1,197 @@
+// Package anomaly detects sudden changes in merchants' payments. Each
+// merchant's latest period is compared with an exponentially weighted moving
+// average (EWMA) of the periods before it, and an anomaly is reported when it
+// lies Threshold or more standard deviations from that baseline: a rise in
+// successful volume in one currency or a fall in success rate.
+package anomaly
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// Period is the length of the periods compared, and BaselinePeriods how many
+// periods before the latest make up the baseline.
+const (
+	Period          = 24 * time.Hour
+	BaselinePeriods = 28
+)
+
+// Threshold is how many standard deviations from the baseline make an
+// anomaly.
+const Threshold = 3.0
+
+// alpha weights each period in the EWMA; older periods decay by 1-alpha per
+// period.
+const alpha = 0.2
+
+const (
+	// minActivePeriods is how many baseline periods need payments before a
+	// merchant's baseline is trusted.
+	minActivePeriods = 7
+	// minRateSample is how many payments a period needs for its success rate
+	// to count.
+	minRateSample = 10
+	// Deviations are floored so a merchant with a very steady baseline is not
+	// alerted on ordinary noise: volume by this share of its mean, success
+	// rate by this many percentage points.
+	minVolumeDeviation = 0.1
+	minRateDeviation   = 0.02
+)
+
+// Anomaly is a merchant's latest period deviating from its baseline. Success
+// rates are fractions from 0 to 1; volumes are in minor units of Currency,
+// which is empty for success rates.
+type Anomaly struct {
+	MerchantID int
+	Type       string
+	Currency   string
+	Observed   float64
+	Expected   float64
+	Deviation  float64
+}
+
+// Query returns the metrics query Detect expects, ending at now.
+func Query(now time.Time) models.MetricsQuery {
+	return models.MetricsQuery{End: now, Period: Period, Periods: BaselinePeriods + 1}
+}
+
+// Detect returns the anomalies in metrics from Query, ordered by merchant.
+// Volume is compared currency by currency, reporting the one that deviates
+// most, and success rate over all of a merchant's payments. Periods without
+// payments may be left out.
+func Detect(metrics []models.MerchantMetrics) []Anomaly {
+	type series = [BaselinePeriods + 1]models.MerchantMetrics
+	type currencyKey struct {
+		merchant int
+		currency string
+	}
+	volumes := map[currencyKey]*series{}
+	totals := map[int]*series{}
+	for _, m := range metrics {
+		if m.Period < 0 || m.Period > BaselinePeriods {
+			continue
+		}
+		k := currencyKey{m.MerchantID, m.Currency}
+		if volumes[k] == nil {
+			volumes[k] = new(series)
+		}
+		volumes[k][m.Period] = m
+		if totals[m.MerchantID] == nil {
+			totals[m.MerchantID] = new(series)
+		}
+		t := &totals[m.MerchantID][m.Period]
+		t.Transactions += m.Transactions
+		t.Successful += m.Successful
+	}
+
+	spikes := map[int]Anomaly{}
+	for k, s := range volumes {
+		if a, ok := volumeSpike(s[:]); ok && a.Deviation > spikes[k.merchant].Deviation {
+			a.MerchantID, a.Currency = k.merchant, k.currency
+			spikes[k.merchant] = a
+		}
+	}
+	var anomalies []Anomaly
+	for _, a := range spikes {
+		anomalies = append(anomalies, a)
+	}
+	for id, s := range totals {
+		if a, ok := successRateDrop(s[:]); ok {
+			a.MerchantID = id
+			anomalies = append(anomalies, a)
+		}
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].MerchantID != anomalies[j].MerchantID {
+			return anomalies[i].MerchantID < anomalies[j].MerchantID
+		}
+		return anomalies[i].Type < anomalies[j].Type
+	})
+	return anomalies
+}
+
+// volumeSpike compares the latest period's volume in one currency with
+// every baseline period's, counting those without payments as zero.
+func volumeSpike(s []models.MerchantMetrics) (Anomaly, bool) {
+	var (
+		e      ewma
+		active int
+	)
+	for p := BaselinePeriods; p >= 1; p-- {
+		if s[p].Transactions > 0 {
+			active++
+		}
+		e.add(float64(s[p].Volume))
+	}
+	if active < minActivePeriods {
+		return Anomaly{}, false
+	}
+	spread := math.Max(e.stddev(), e.mean*minVolumeDeviation)
+	if spread == 0 {
+		return Anomaly{}, false
+	}
+	observed := float64(s[0].Volume)
+	deviation := (observed - e.mean) / spread
+	if deviation < Threshold {
+		return Anomaly{}, false
+	}
+	return Anomaly{Type: models.AlertVolumeSpike, Observed: observed, Expected: round(e.mean), Deviation: round(deviation)}, true
+}
+
+// successRateDrop compares the latest period's success rate with the
+// baseline periods that had enough payments to measure one.
+func successRateDrop(s []models.MerchantMetrics) (Anomaly, bool) {
+	if s[0].Transactions < minRateSample {
+		return Anomaly{}, false
+	}
+	var e ewma
+	for p := BaselinePeriods; p >= 1; p-- {
+		if s[p].Transactions >= minRateSample {
+			e.add(rate(s[p]))
+		}
+	}
+	if e.n < minActivePeriods {
+		return Anomaly{}, false
+	}
+	observed := rate(s[0])
+	deviation := (observed - e.mean) / math.Max(e.stddev(), minRateDeviation)
+	if deviation > -Threshold {
+		return Anomaly{}, false
+	}
+	return Anomaly{Type: models.AlertSuccessRateDrop, Observed: round(observed), Expected: round(e.mean), Deviation: round(deviation)}, true
+}
+
+// ewma is an exponentially weighted moving mean and variance, starting from
+// the first value added.
+type ewma struct {
+	n        int
+	mean     float64
+	variance float64
+}
+
+func (e *ewma) add(x float64) {
+	e.n++
+	if e.n == 1 {
+		e.mean = x
+		return
+	}
+	diff := x - e.mean
+	e.mean += alpha * diff
+	e.variance = (1 - alpha) * (e.variance + alpha*diff*diff)
+}
+
+func (e *ewma) stddev() float64 {
+	return math.Sqrt(e.variance)
+}
+
+func rate(m models.MerchantMetrics) float64 {
+	return float64(m.Successful) / float64(m.Transactions)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
@@ -0,0 +1,78 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// history returns a merchant's baseline with the given volume and success
+// count out of 100 payments in every period, and latest as period 0.
+func history(id int, volume int64, successful int, latest models.MerchantMetrics) []models.MerchantMetrics {
+	metrics := []models.MerchantMetrics{latest}
+	metrics[0].MerchantID = id
+	for p := 1; p <= BaselinePeriods; p++ {
+		// small wobble so the baseline has some variance
+		v := volume + int64(p%3)*volume/50
+		metrics = append(metrics, models.MerchantMetrics{MerchantID: id, Period: p, Transactions: 100, Successful: successful, Volume: v})
+	}
+	return metrics
+}
+
+func TestDetect(t *testing.T) {
+	var metrics []models.MerchantMetrics
+	// steady
+	metrics = append(metrics, history(1, 10000, 95, models.MerchantMetrics{Transactions: 100, Successful: 94, Volume: 10500})...)
+	// volume spike
+	metrics = append(metrics, history(2, 10000, 95, models.MerchantMetrics{Transactions: 300, Successful: 285, Volume: 40000})...)
+	// success rate collapse
+	metrics = append(metrics, history(3, 10000, 95, models.MerchantMetrics{Transactions: 100, Successful: 60, Volume: 6000})...)
+	// too little history to judge
+	metrics = append(metrics,
+		models.MerchantMetrics{MerchantID: 4, Period: 0, Transactions: 500, Successful: 10, Volume: 1000000},
+		models.MerchantMetrics{MerchantID: 4, Period: 1, Transactions: 20, Successful: 19, Volume: 1000},
+	)
+	// too few payments for the latest success rate to count
+	metrics = append(metrics, history(5, 10000, 95, models.MerchantMetrics{Transactions: 5, Successful: 0, Volume: 0})...)
+
+	got := Detect(metrics)
+	if len(got) != 2 {
+		t.Fatalf("got %d anomalies %+v, want 2", len(got), got)
+	}
+	if a := got[0]; a.MerchantID != 2 || a.Type != models.AlertVolumeSpike || a.Observed != 40000 || a.Expected < 10000 || a.Deviation < Threshold {
+		t.Errorf("volume spike = %+v", a)
+	}
+	if a := got[1]; a.MerchantID != 3 || a.Type != models.AlertSuccessRateDrop || a.Observed != 0.6 || a.Expected != 0.95 || a.Deviation > -Threshold {
+		t.Errorf("success rate drop = %+v", a)
+	}
+}
+
+func TestDetectComparesVolumePerCurrency(t *testing.T) {
+	inCurrency := func(currency string, metrics []models.MerchantMetrics) []models.MerchantMetrics {
+		for i := range metrics {
+			metrics[i].Currency = currency
+		}
+		return metrics
+	}
+	// a tenfold USD spike that would vanish in the NGN volume if they were added up
+	metrics := inCurrency("NGN", history(1, 1000000, 95, models.MerchantMetrics{Transactions: 100, Successful: 95, Volume: 1000000}))
+	metrics = append(metrics, inCurrency("USD", history(1, 100, 95, models.MerchantMetrics{Transactions: 100, Successful: 95, Volume: 1000}))...)
+
+	got := Detect(metrics)
+	if len(got) != 1 {
+		t.Fatalf("got %d anomalies %+v, want 1", len(got), got)
+	}
+	if a := got[0]; a.MerchantID != 1 || a.Type != models.AlertVolumeSpike || a.Currency != "USD" || a.Observed != 1000 {
+		t.Errorf("volume spike = %+v", a)
+	}
+}
+
+func TestEWMAWeightsRecentPeriods(t *testing.T) {
+	var e ewma
+	for _, x := range []float64{10, 10, 10, 20} {
+		e.add(x)
+	}
+	if e.mean != 12 || e.stddev() == 0 {
+		t.Errorf("mean %v stddev %v, want mean 12 and some spread", e.mean, e.stddev())
+	}
+}
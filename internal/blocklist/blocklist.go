@@ -0,0 +1,232 @@
+// Package blocklist normalises blocked customer identifiers and matches
+// payments against them. Entries are exact values except for two types:
+// card BINs block every card number they prefix, and IPs are CIDR prefixes
+// that block every address inside them.
+package blocklist
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// MaxValueLength bounds a blocked value.
+const MaxValueLength = 256
+
+// Minimum prefix lengths for blocked IP ranges, so a typo cannot block a
+// large part of the internet.
+const (
+	minIPv4Bits = 16
+	minIPv6Bits = 32
+)
+
+// Types lists the entry types in the order they are documented.
+var Types = []string{models.BlockEmail, models.BlockCardFingerprint, models.BlockCardBIN, models.BlockIP, models.BlockDevice}
+
+// ValidType reports whether t is an entry type.
+func ValidType(t string) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize validates a value of the given type and returns the form it is
+// stored and matched in.
+func Normalize(typ, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.New("value is required")
+	}
+	if len(value) > MaxValueLength {
+		return "", fmt.Errorf("value must be at most %d characters", MaxValueLength)
+	}
+	switch typ {
+	case models.BlockEmail:
+		return normalizeEmail(value)
+	case models.BlockCardFingerprint, models.BlockDevice:
+		if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+			return "", fmt.Errorf("%s must not contain spaces", typ)
+		}
+		return value, nil
+	case models.BlockCardBIN:
+		if !isBIN(value) {
+			return "", errors.New("card BIN must be 6 to 8 digits")
+		}
+		return value, nil
+	case models.BlockIP:
+		p, err := parsePrefix(value)
+		if err != nil {
+			return "", err
+		}
+		minBits := minIPv6Bits
+		if p.Addr().Is4() {
+			minBits = minIPv4Bits
+		}
+		if p.Bits() < minBits {
+			return "", fmt.Errorf("IP range %s is too broad, use at least /%d for IPv4 or /%d for IPv6", p, minIPv4Bits, minIPv6Bits)
+		}
+		return p.String(), nil
+	}
+	return "", fmt.Errorf("unknown blocklist type %q", typ)
+}
+
+func normalizeEmail(v string) (string, error) {
+	local, domain, ok := strings.Cut(strings.ToLower(v), "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") || strings.IndexFunc(v, unicode.IsSpace) >= 0 {
+		return "", fmt.Errorf("invalid email address %q", v)
+	}
+	return local + "@" + domain, nil
+}
+
+func isBIN(v string) bool {
+	if len(v) < 6 || len(v) > 8 {
+		return false
+	}
+	for _, c := range v {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePrefix parses a CIDR range or a single address, which becomes a
+// prefix covering only itself.
+func parsePrefix(v string) (netip.Prefix, error) {
+	if strings.Contains(v, "/") {
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid IP range %q", v)
+		}
+		if p.Addr().Is4In6() {
+			p = netip.PrefixFrom(p.Addr().Unmap(), max(p.Bits()-96, 0))
+		}
+		return p.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(v)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q", v)
+	}
+	addr = addr.Unmap().WithZone("")
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Query holds the identifiers of a payment to check; empty fields are not
+// checked.
+type Query struct {
+	Email           string
+	CardFingerprint string
+	// CardBIN is the first 6 to 8 digits of the card number
+	CardBIN  string
+	IP       string
+	DeviceID string
+}
+
+// Validate normalises the query's identifiers, failing when none is set or
+// one is malformed.
+func (q *Query) Validate() error {
+	if *q == (Query{}) {
+		return errors.New("at least one identifier is required")
+	}
+	q.CardFingerprint = strings.TrimSpace(q.CardFingerprint)
+	q.DeviceID = strings.TrimSpace(q.DeviceID)
+	if q.Email != "" {
+		email, err := normalizeEmail(strings.TrimSpace(q.Email))
+		if err != nil {
+			return err
+		}
+		q.Email = email
+	}
+	if q.CardBIN != "" && !isBIN(q.CardBIN) {
+		return errors.New("card_bin must be 6 to 8 digits")
+	}
+	if q.IP != "" {
+		addr, err := netip.ParseAddr(strings.TrimSpace(q.IP))
+		if err != nil {
+			return fmt.Errorf("invalid IP address %q", q.IP)
+		}
+		q.IP = addr.Unmap().WithZone("").String()
+	}
+	return nil
+}
+
+type key struct{ typ, value string }
+
+type ipRange struct {
+	prefix netip.Prefix
+	entry  models.BlocklistEntry
+}
+
+// Index answers lookups against a snapshot of the blocklist. It is
+// immutable once built, so it may be shared between goroutines.
+type Index struct {
+	exact  map[key]models.BlocklistEntry
+	ranges []ipRange
+}
+
+// NewIndex indexes entries, skipping any whose value does not normalise.
+func NewIndex(entries []models.BlocklistEntry) *Index {
+	ix := &Index{exact: make(map[key]models.BlocklistEntry, len(entries))}
+	for _, e := range entries {
+		if e.Type != models.BlockIP {
+			ix.exact[key{e.Type, e.Value}] = e
+			continue
+		}
+		p, err := parsePrefix(e.Value)
+		if err != nil {
+			continue
+		}
+		if p.IsSingleIP() {
+			ix.exact[key{models.BlockIP, p.Addr().String()}] = e
+		} else {
+			ix.ranges = append(ix.ranges, ipRange{p, e})
+		}
+	}
+	return ix
+}
+
+// Len returns how many entries the index holds.
+func (ix *Index) Len() int {
+	return len(ix.exact) + len(ix.ranges)
+}
+
+// Match returns the entries active at t that block any of the query's
+// identifiers. The query must have been validated.
+func (ix *Index) Match(q Query, t time.Time) []models.BlocklistEntry {
+	var matches []models.BlocklistEntry
+	add := func(typ, value string) {
+		if e, ok := ix.exact[key{typ, value}]; ok && e.Active(t) {
+			matches = append(matches, e)
+		}
+	}
+	if q.Email != "" {
+		add(models.BlockEmail, q.Email)
+	}
+	if q.CardFingerprint != "" {
+		add(models.BlockCardFingerprint, q.CardFingerprint)
+	}
+	for n := 6; n <= len(q.CardBIN); n++ {
+		add(models.BlockCardBIN, q.CardBIN[:n])
+	}
+	if q.DeviceID != "" {
+		add(models.BlockDevice, q.DeviceID)
+	}
+	if q.IP != "" {
+		add(models.BlockIP, q.IP)
+		addr := netip.MustParseAddr(q.IP)
+		for _, r := range ix.ranges {
+			if r.prefix.Contains(addr) && r.entry.Active(t) {
+				matches = append(matches, r.entry)
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,94 @@
+package blocklist
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		typ, value, want string
+		wantErr          bool
+	}{
+		{models.BlockEmail, "  Eve@Example.COM ", "eve@example.com", false},
+		{models.BlockEmail, "eve", "", true},
+		{models.BlockEmail, "eve@a@b", "", true},
+		{models.BlockCardFingerprint, "fp_123", "fp_123", false},
+		{models.BlockCardFingerprint, "fp 123", "", true},
+		{models.BlockCardBIN, "424242", "424242", false},
+		{models.BlockCardBIN, "42424", "", true},
+		{models.BlockCardBIN, "4242424242", "", true},
+		{models.BlockCardBIN, "42x242", "", true},
+		{models.BlockIP, "203.0.113.7", "203.0.113.7/32", false},
+		{models.BlockIP, "203.0.113.7/24", "203.0.113.0/24", false},
+		{models.BlockIP, "::ffff:203.0.113.7", "203.0.113.7/32", false},
+		{models.BlockIP, "2001:db8::1/48", "2001:db8::/48", false},
+		{models.BlockIP, "10.0.0.0/8", "", true},
+		{models.BlockIP, "2001:db8::/16", "", true},
+		{models.BlockIP, "not-an-ip", "", true},
+		{models.BlockDevice, "dev-1", "dev-1", false},
+		{"phone", "123", "", true},
+		{models.BlockDevice, " ", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.typ, tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, %v; want %q, error %v", tt.typ, tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestIndexMatch(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	entries := []models.BlocklistEntry{
+		{ID: 1, Type: models.BlockEmail, Value: "eve@example.com"},
+		{ID: 2, Type: models.BlockCardBIN, Value: "424242"},
+		{ID: 3, Type: models.BlockIP, Value: "203.0.113.0/24"},
+		{ID: 4, Type: models.BlockIP, Value: "198.51.100.9/32"},
+		{ID: 5, Type: models.BlockDevice, Value: "dev-1", ExpiresAt: &past},
+		{ID: 6, Type: models.BlockCardFingerprint, Value: "fp-1"},
+	}
+	ix := NewIndex(entries)
+
+	tests := []struct {
+		name string
+		q    Query
+		want []int64
+	}{
+		{"email is case-insensitive", Query{Email: "EVE@example.com"}, []int64{1}},
+		{"bin prefixes longer bins", Query{CardBIN: "42424299"}, []int64{2}},
+		{"other bin", Query{CardBIN: "555555"}, nil},
+		{"ip in range", Query{IP: "203.0.113.200"}, []int64{3}},
+		{"single ip", Query{IP: "198.51.100.9"}, []int64{4}},
+		{"mapped ip", Query{IP: "::ffff:198.51.100.9"}, []int64{4}},
+		{"expired entries do not match", Query{DeviceID: "dev-1"}, nil},
+		{"several identifiers", Query{Email: "eve@example.com", CardFingerprint: "fp-1", IP: "192.0.2.1"}, []int64{1, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.q
+			if err := q.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, e := range ix.Match(q, now) {
+				got = append(got, e.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	for _, q := range []Query{{}, {Email: "nope"}, {CardBIN: "4242424242424242"}, {IP: "999.1.1.1"}} {
+		if err := q.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", q)
+		}
+	}
+}
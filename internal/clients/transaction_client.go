@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,11 +11,46 @@ import (
 	"strconv"
 
 	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/tracing"
 )
 
 // TransactionClient defines the interface for interacting with the Transaction Service.
 type TransactionClient interface {
 	ListFraudulentTransactions(ctx context.Context, limit int) (dto.TransactionListResponse, error)
+	// ResolveReview ends the fraud review hold on a transaction, either
+	// releasing it to settle or reversing it.
+	ResolveReview(ctx context.Context, id int, resolution ReviewResolution, reason string) error
+	// UpdatePayout holds, releases, cancels or retries a payout.
+	UpdatePayout(ctx context.Context, id int, action PayoutAction, reason string) error
+}
+
+// ReviewResolution is how a transaction held for fraud review is resolved.
+type ReviewResolution string
+
+const (
+	ReviewRelease ReviewResolution = "release"
+	ReviewReverse ReviewResolution = "reverse"
+)
+
+// PayoutAction is a change an admin makes to a payout.
+type PayoutAction string
+
+const (
+	PayoutHold    PayoutAction = "hold"
+	PayoutRelease PayoutAction = "release"
+	PayoutCancel  PayoutAction = "cancel"
+	PayoutRetry   PayoutAction = "retry"
+)
+
+// StatusError is returned when the transaction service answers with a non-OK status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("transaction service returned non-ok status: %d, body: %s", e.StatusCode, e.Body)
 }
 
 // HTTPTransactionClient is an HTTP implementation of the TransactionClient interface.
@@ -27,10 +63,15 @@ type HTTPTransactionClient struct {
 func NewHTTPTransactionClient(baseURL string) *HTTPTransactionClient {
 	return &HTTPTransactionClient{
 		baseURL: baseURL,
-		client:  &http.Client{},
+		client:  &http.Client{Transport: metrics.InstrumentTransport("transaction", tracing.Transport("transaction-service", nil))},
 	}
 }
 
+// Close releases idle connections held by the client.
+func (c *HTTPTransactionClient) Close() {
+	c.client.CloseIdleConnections()
+}
+
 // ListFraudulentTransactions calls the transaction service to get a list of transactions marked as fraudulent or pending review.
 func (c *HTTPTransactionClient) ListFraudulentTransactions(ctx context.Context, limit int) (dto.TransactionListResponse, error) {
 	// Construct URL with query parameters for status
@@ -53,7 +94,7 @@ func (c *HTTPTransactionClient) ListFraudulentTransactions(ctx context.Context,
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return dto.TransactionListResponse{}, fmt.Errorf("transaction service returned non-ok status: %d, body: %s", resp.StatusCode, respBody)
+		return dto.TransactionListResponse{}, &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var transactionList dto.TransactionListResponse
@@ -63,3 +104,57 @@ func (c *HTTPTransactionClient) ListFraudulentTransactions(ctx context.Context,
 
 	return transactionList, nil
 }
+
+// ResolveReview calls the transaction service to release or reverse a transaction held for review.
+func (c *HTTPTransactionClient) ResolveReview(ctx context.Context, id int, resolution ReviewResolution, reason string) error {
+	body, err := json.Marshal(map[string]string{"action": string(resolution), "reason": reason})
+	if err != nil {
+		return fmt.Errorf("failed to encode review resolution: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/transactions/%d/review", c.baseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create http request for review resolution: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return nil
+}
+
+// UpdatePayout calls the transaction service to hold, release, cancel or retry a payout.
+func (c *HTTPTransactionClient) UpdatePayout(ctx context.Context, id int, action PayoutAction, reason string) error {
+	body, err := json.Marshal(map[string]string{"reason": reason})
+	if err != nil {
+		return fmt.Errorf("failed to encode payout %s: %w", action, err)
+	}
+
+	url := fmt.Sprintf("%s/payouts/%d/%s", c.baseURL, id, action)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create http request for payout %s: %w", action, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return nil
+}
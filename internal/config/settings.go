@@ -0,0 +1,116 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describes one configurable value and how it is named in each source.
+type setting struct {
+	key   string // YAML key; the flag name is the same in kebab-case
+	env   string
+	usage string
+	set   func(string) error
+}
+
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.key, "_", "-")
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"port", "PORT", "HTTP listen port", stringVar(&c.Port)},
+		{"postgres_url", "POSTGRES_URL", "Postgres connection URL", stringVar(&c.PostgresDSN)},
+		{"redis_addr", "REDIS_ADDR", "Redis host:port", stringVar(&c.RedisAddr)},
+		{"merchant_service_url", "MERCHANT_SERVICE_URL", "merchant service base URL", stringVar(&c.MerchantServiceURL)},
+		{"compliance_service_url", "COMPLIANCE_SERVICE_URL", "compliance service base URL", stringVar(&c.ComplianceServiceURL)},
+		{"transaction_service_url", "TRANSACTION_SERVICE_URL", "transaction service base URL", stringVar(&c.TransactionServiceURL)},
+		{"idempotency_ttl", "IDEMPOTENCY_TTL", "how long idempotency keys are retained", durationVar(&c.IdempotencyTTL)},
+		{"readiness_timeout", "READINESS_TIMEOUT", "timeout for each readiness dependency check", durationVar(&c.ReadinessTimeout)},
+		{"readiness_critical", "READINESS_CRITICAL", "comma separated dependencies that must be up for readiness", listVar(&c.ReadinessCritical)},
+		{"trusted_proxies", "TRUSTED_PROXIES", "comma separated proxy addresses or CIDR ranges trusted to set X-Forwarded-For", listVar(&c.TrustedProxies)},
+		{"shutdown_delay", "SHUTDOWN_DELAY", "time readiness fails before draining starts", durationVar(&c.ShutdownDelay)},
+		{"shutdown_timeout", "SHUTDOWN_TIMEOUT", "deadline for in-flight requests to drain", durationVar(&c.ShutdownTimeout)},
+		{"tracing_exporter", "TRACING_EXPORTER", "trace exporter: none, otlp or stdout", stringVar(&c.TracingExporter)},
+		{"tracing_otlp_endpoint", "TRACING_OTLP_ENDPOINT", "OTLP/HTTP traces URL", stringVar(&c.TracingEndpoint)},
+		{"tracing_sample_ratio", "TRACING_SAMPLE_RATIO", "fraction of traces sampled, 0 to 1", floatVar(&c.TracingSampleRatio)},
+		{"log_level", "LOG_LEVEL", "log level: debug, info, warn or error", stringVar(&c.LogLevel)},
+		{"log_format", "LOG_FORMAT", "log format: json or text", stringVar(&c.LogFormat)},
+		{"settings_file", "SETTINGS_FILE", "YAML or JSON file with runtime settings", stringVar(&c.SettingsFile)},
+		{"settings_poll_interval", "SETTINGS_POLL_INTERVAL", "how often the settings file is checked for changes", durationVar(&c.SettingsPollInterval)},
+		{"auto_migrate", "AUTO_MIGRATE", "apply pending schema migrations at startup", boolVar(&c.AutoMigrate)},
+		{"fraud_case_sync_interval", "FRAUD_CASE_SYNC_INTERVAL", "how often flagged transactions are grouped into cases, 0 to disable", durationVar(&c.FraudCaseSyncInterval)},
+		{"blocklist_refresh_interval", "BLOCKLIST_REFRESH_INTERVAL", "how often the blocklist lookup cache is reloaded, 0 to disable", durationVar(&c.BlocklistRefreshInterval)},
+		{"risk_score_interval", "RISK_SCORE_INTERVAL", "how often merchant risk scores are recalculated, 0 to disable", durationVar(&c.RiskScoreInterval)},
+		{"anomaly_detection_interval", "ANOMALY_DETECTION_INTERVAL", "how often merchant payments are checked for anomalies, 0 to disable", durationVar(&c.AnomalyDetectionInterval)},
+		{"suspension_policy_interval", "SUSPENSION_POLICY_INTERVAL", "how often suspension policies are evaluated, 0 to disable", durationVar(&c.SuspensionPolicyInterval)},
+		{"payout_review_interval", "PAYOUT_REVIEW_INTERVAL", "how often pending payouts are screened for manual review, 0 to disable", durationVar(&c.PayoutReviewInterval)},
+		{"legacy_api_deprecated_at", "LEGACY_API_DEPRECATED_AT", "date the unversioned /admin routes were deprecated (YYYY-MM-DD)", dateVar(&c.LegacyAPIDeprecatedAt)},
+		{"legacy_api_sunset", "LEGACY_API_SUNSET", "date the unversioned /admin routes will be removed (YYYY-MM-DD)", dateVar(&c.LegacyAPISunset)},
+	}
+}
+
+func stringVar(p *string) func(string) error {
+	return func(v string) error {
+		*p = v
+		return nil
+	}
+}
+
+func durationVar(p *time.Duration) func(string) error {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*p = d
+		return nil
+	}
+}
+
+func boolVar(p *bool) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*p = b
+		return nil
+	}
+}
+
+func dateVar(p *time.Time) func(string) error {
+	return func(v string) error {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return err
+		}
+		*p = t
+		return nil
+	}
+}
+
+func floatVar(p *float64) func(string) error {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*p = f
+		return nil
+	}
+}
+
+func listVar(p *[]string) func(string) error {
+	return func(v string) error {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*p = items
+		return nil
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dependencies are the names accepted in ReadinessCritical.
+var Dependencies = []string{"postgres", "redis", "merchant-service", "compliance-service", "transaction-service"}
+
+// Validate reports every invalid or missing value at once.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		fail("port", "must be a number between 1 and 65535, got %q", c.Port)
+	}
+
+	if c.PostgresDSN == "" {
+		fail("postgres_url", "is required (set POSTGRES_URL, POSTGRES_URL_FILE or postgres_url in the config file)")
+	} else if u, err := url.Parse(c.PostgresDSN); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+		// Don't echo the DSN, it usually contains a password.
+		fail("postgres_url", "must be a postgres:// URL with a host")
+	}
+
+	if _, _, err := net.SplitHostPort(c.RedisAddr); err != nil {
+		fail("redis_addr", "must be host:port: %v", err)
+	}
+
+	if err := validateHTTPURL(c.MerchantServiceURL); err != nil {
+		fail("merchant_service_url", "%v", err)
+	}
+	if err := validateHTTPURL(c.ComplianceServiceURL); err != nil {
+		fail("compliance_service_url", "%v", err)
+	}
+	if err := validateHTTPURL(c.TransactionServiceURL); err != nil {
+		fail("transaction_service_url", "%v", err)
+	}
+
+	positive := func(key string, d time.Duration) {
+		if d <= 0 {
+			fail(key, "must be positive, got %s", d)
+		}
+	}
+	positive("idempotency_ttl", c.IdempotencyTTL)
+	positive("readiness_timeout", c.ReadinessTimeout)
+	positive("shutdown_timeout", c.ShutdownTimeout)
+	positive("settings_poll_interval", c.SettingsPollInterval)
+	if c.FraudCaseSyncInterval < 0 {
+		fail("fraud_case_sync_interval", "must not be negative, got %s", c.FraudCaseSyncInterval)
+	}
+	if c.BlocklistRefreshInterval < 0 {
+		fail("blocklist_refresh_interval", "must not be negative, got %s", c.BlocklistRefreshInterval)
+	}
+	if c.RiskScoreInterval < 0 {
+		fail("risk_score_interval", "must not be negative, got %s", c.RiskScoreInterval)
+	}
+	if c.AnomalyDetectionInterval < 0 {
+		fail("anomaly_detection_interval", "must not be negative, got %s", c.AnomalyDetectionInterval)
+	}
+	if c.SuspensionPolicyInterval < 0 {
+		fail("suspension_policy_interval", "must not be negative, got %s", c.SuspensionPolicyInterval)
+	}
+	if c.PayoutReviewInterval < 0 {
+		fail("payout_review_interval", "must not be negative, got %s", c.PayoutReviewInterval)
+	}
+	if c.ShutdownDelay < 0 {
+		fail("shutdown_delay", "must not be negative, got %s", c.ShutdownDelay)
+	}
+
+	if c.LegacyAPIDeprecatedAt.IsZero() {
+		fail("legacy_api_deprecated_at", "is required")
+	}
+	if !c.LegacyAPISunset.IsZero() && !c.LegacyAPISunset.After(c.LegacyAPIDeprecatedAt) {
+		fail("legacy_api_sunset", "must be after legacy_api_deprecated_at")
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				fail("trusted_proxies", "must be IP addresses or CIDR ranges, got %q", proxy)
+			}
+		}
+	}
+
+	for _, name := range c.ReadinessCritical {
+		if !contains(Dependencies, name) {
+			fail("readiness_critical", "unknown dependency %q, expected one of %s", name, strings.Join(Dependencies, ", "))
+		}
+	}
+
+	switch c.TracingExporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.TracingEndpoint != "" {
+			if err := validateHTTPURL(c.TracingEndpoint); err != nil {
+				fail("tracing_otlp_endpoint", "%v", err)
+			}
+		}
+	default:
+		fail("tracing_exporter", "must be none, otlp or stdout, got %q", c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		fail("tracing_sample_ratio", "must be between 0 and 1, got %v", c.TracingSampleRatio)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		fail("log_level", "must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		fail("log_format", "must be json or text, got %q", c.LogFormat)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
+
+func validateHTTPURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %v", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute http(s) URL, got %q", raw)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
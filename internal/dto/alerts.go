@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// AlertListQuery DTO for filtering merchant alerts
+type AlertListQuery struct {
+	Status     string `query:"status"`
+	Type       string `query:"type"`
+	MerchantID int    `query:"merchant_id"`
+	Limit      int    `query:"limit"`
+}
+
+// ResolveAlertRequest DTO for resolving a merchant alert
+type ResolveAlertRequest struct {
+	Resolution string `json:"resolution"`
+}
+
+// AlertResponse DTO for returning a merchant alert. Success rates are
+// fractions from 0 to 1 and volumes are in minor units of Currency.
+type AlertResponse struct {
+	ID             int64      `json:"id"`
+	MerchantID     int        `json:"merchant_id"`
+	Type           string     `json:"type"`
+	Status         string     `json:"status"`
+	Currency       string     `json:"currency,omitempty"`
+	Observed       float64    `json:"observed"`
+	Expected       float64    `json:"expected"`
+	Deviation      float64    `json:"deviation"`
+	PeriodStart    time.Time  `json:"period_start"`
+	PeriodEnd      time.Time  `json:"period_end"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	ResolvedBy     string     `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	Resolution     string     `json:"resolution,omitempty"`
+}
+
+// NewAlertResponse converts a merchant alert to its response DTO
+func NewAlertResponse(a models.MerchantAlert) AlertResponse {
+	return AlertResponse{
+		ID:             a.ID,
+		MerchantID:     a.MerchantID,
+		Type:           a.Type,
+		Status:         a.Status,
+		Currency:       a.Currency,
+		Observed:       a.Observed,
+		Expected:       a.Expected,
+		Deviation:      a.Deviation,
+		PeriodStart:    a.PeriodStart,
+		PeriodEnd:      a.PeriodEnd,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+		AcknowledgedBy: a.AcknowledgedBy,
+		AcknowledgedAt: a.AcknowledgedAt,
+		ResolvedBy:     a.ResolvedBy,
+		ResolvedAt:     a.ResolvedAt,
+		Resolution:     a.Resolution,
+	}
+}
+
+// AlertListResponse DTO for returning a list of merchant alerts
+type AlertListResponse struct {
+	Alerts []AlertResponse `json:"alerts"`
+	Total  int             `json:"total"`
+}
+
+// AnomalyDetectionResponse DTO for returning the outcome of a detection run:
+// how many merchants had payments to check, how many anomalies were found and
+// how many of those raised new alerts rather than matching unresolved ones
+type AnomalyDetectionResponse struct {
+	Merchants    int `json:"merchants"`
+	Anomalies    int `json:"anomalies"`
+	AlertsRaised int `json:"alerts_raised"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// CreateBlocklistEntryRequest DTO for blocking a customer identifier. A nil
+// ExpiresAt blocks it until the entry is expired.
+type CreateBlocklistEntryRequest struct {
+	Type      string     `json:"type"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ExpireBlocklistEntryRequest DTO for ending a block. A nil ExpiresAt ends it now.
+type ExpireBlocklistEntryRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// BlocklistQuery DTO for searching the blocklist
+type BlocklistQuery struct {
+	Type           string `query:"type"`
+	Search         string `query:"q"`
+	IncludeExpired bool   `query:"include_expired"`
+	Limit          int    `query:"limit"`
+}
+
+// BlocklistEntryResponse DTO for returning a blocklist entry
+type BlocklistEntryResponse struct {
+	ID        int64      `json:"id"`
+	Type      string     `json:"type"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Active    bool       `json:"active"`
+}
+
+// NewBlocklistEntryResponse converts a blocklist entry to its response DTO,
+// reporting whether it is active at now
+func NewBlocklistEntryResponse(e models.BlocklistEntry, now time.Time) BlocklistEntryResponse {
+	return BlocklistEntryResponse{
+		ID:        e.ID,
+		Type:      e.Type,
+		Value:     e.Value,
+		Reason:    e.Reason,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+		ExpiresAt: e.ExpiresAt,
+		Active:    e.Active(now),
+	}
+}
+
+// BlocklistListResponse DTO for returning a list of blocklist entries
+type BlocklistListResponse struct {
+	Entries []BlocklistEntryResponse `json:"entries"`
+	Total   int                      `json:"total"`
+}
+
+// BlocklistImportResponse DTO for the outcome of a CSV import. Rows counts
+// the data rows, Duplicates the rows repeating an earlier row's value and
+// AlreadyBlocked the values an active entry already blocked.
+type BlocklistImportResponse struct {
+	Rows           int `json:"rows"`
+	Imported       int `json:"imported"`
+	Duplicates     int `json:"duplicates"`
+	AlreadyBlocked int `json:"already_blocked"`
+}
+
+// BlocklistCheckQuery DTO for checking a payment's identifiers against the
+// blocklist. CardBIN is the first 6 to 8 digits of the card number.
+type BlocklistCheckQuery struct {
+	Email           string `query:"email"`
+	CardFingerprint string `query:"card_fingerprint"`
+	CardBIN         string `query:"card_bin"`
+	IP              string `query:"ip"`
+	DeviceID        string `query:"device_id"`
+}
+
+// BlocklistMatchResponse DTO for an entry blocking a checked identifier
+type BlocklistMatchResponse struct {
+	ID     int64  `json:"id"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// BlocklistCheckResponse DTO for the outcome of a blocklist check
+type BlocklistCheckResponse struct {
+	Blocked bool                     `json:"blocked"`
+	Matches []BlocklistMatchResponse `json:"matches"`
+}
@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// CaseListQuery DTO for filtering fraud cases
+type CaseListQuery struct {
+	Status   string `query:"status"`
+	Assignee string `query:"assignee"`
+	// Breached selects unresolved cases past their review SLA
+	Breached bool `query:"breached"`
+	Limit    int  `query:"limit"`
+}
+
+// AssignCaseRequest DTO for assigning a fraud case; an empty assignee unassigns it
+type AssignCaseRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+// CaseStatusRequest DTO for moving a fraud case to a new status. Resolution
+// is required when resolving.
+type CaseStatusRequest struct {
+	Status     string `json:"status"`
+	Resolution string `json:"resolution"`
+}
+
+// CaseCommentRequest DTO for commenting on a fraud case
+type CaseCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// CaseResponse DTO for returning a fraud case. TotalAmount is in minor
+// currency units.
+type CaseResponse struct {
+	ID               int64      `json:"id"`
+	GroupType        string     `json:"group_type"`
+	GroupKey         string     `json:"group_key"`
+	Status           string     `json:"status"`
+	Assignee         string     `json:"assignee,omitempty"`
+	Resolution       string     `json:"resolution,omitempty"`
+	TransactionCount int        `json:"transaction_count"`
+	TotalAmount      int64      `json:"total_amount"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	SLADueAt         time.Time  `json:"sla_due_at"`
+	// SLABreached is set for unresolved cases past SLADueAt and for cases
+	// that were resolved after it
+	SLABreached bool `json:"sla_breached"`
+}
+
+// NewCaseResponse converts a fraud case to its response DTO, judging its SLA
+// as of now
+func NewCaseResponse(c models.FraudCase, sla time.Duration, now time.Time) CaseResponse {
+	due := c.CreatedAt.Add(sla)
+	breached := now.After(due)
+	if c.ResolvedAt != nil {
+		breached = c.ResolvedAt.After(due)
+	}
+	return CaseResponse{
+		ID:               c.ID,
+		GroupType:        c.GroupType,
+		GroupKey:         c.GroupKey,
+		Status:           c.Status,
+		Assignee:         c.Assignee,
+		Resolution:       c.Resolution,
+		TransactionCount: c.TransactionCount,
+		TotalAmount:      c.TotalAmount,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		ResolvedAt:       c.ResolvedAt,
+		SLADueAt:         due,
+		SLABreached:      breached,
+	}
+}
+
+// CaseListResponse DTO for returning a list of fraud cases
+type CaseListResponse struct {
+	Cases []CaseResponse `json:"cases"`
+	Total int            `json:"total"`
+}
+
+// CaseTransactionResponse DTO for a flagged transaction in a fraud case
+type CaseTransactionResponse struct {
+	TransactionID   int       `json:"transaction_id"`
+	MerchantID      int       `json:"merchant_id"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	CustomerEmail   string    `json:"customer_email,omitempty"`
+	CardFingerprint string    `json:"card_fingerprint,omitempty"`
+	FlaggedAt       time.Time `json:"flagged_at"`
+	AddedAt         time.Time `json:"added_at"`
+}
+
+// CaseCommentResponse DTO for a comment on a fraud case
+type CaseCommentResponse struct {
+	ID        int64     `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewCaseCommentResponse converts a case comment to its response DTO
+func NewCaseCommentResponse(c models.CaseComment) CaseCommentResponse {
+	return CaseCommentResponse{ID: c.ID, Author: c.Author, Body: c.Body, CreatedAt: c.CreatedAt}
+}
+
+// CaseAttachmentResponse DTO for the metadata of a file attached to a fraud case
+type CaseAttachmentResponse struct {
+	ID          int64     `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewCaseAttachmentResponse converts a case attachment to its response DTO
+func NewCaseAttachmentResponse(a models.CaseAttachment) CaseAttachmentResponse {
+	return CaseAttachmentResponse{
+		ID:          a.ID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		UploadedBy:  a.UploadedBy,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// CaseDetailResponse DTO for returning a fraud case with its transactions,
+// comments and attachments
+type CaseDetailResponse struct {
+	CaseResponse
+	Transactions []CaseTransactionResponse `json:"transactions"`
+	Comments     []CaseCommentResponse     `json:"comments"`
+	Attachments  []CaseAttachmentResponse  `json:"attachments"`
+}
+
+// CaseSyncResponse DTO for the outcome of grouping flagged transactions into cases
+type CaseSyncResponse struct {
+	Flagged           int `json:"flagged"`
+	CasesOpened       int `json:"cases_opened"`
+	TransactionsAdded int `json:"transactions_added"`
+}
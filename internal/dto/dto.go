@@ -1,3 +1,73 @@
 package dto
 
-// TODO: define request/response DTOs for this microservice
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// MerchantResponse DTO for returning a merchant in admin listings
+type MerchantResponse struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	BusinessName string    `json:"business_name"`
+	Status       string    `json:"status"`
+	KYCStatus    string    `json:"kyc_status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	TotalVolume  int64     `json:"total_volume"`
+	Currency     string    `json:"currency"`
+	// Risk is omitted until the merchant has been scored
+	Risk *MerchantRiskResponse `json:"risk,omitempty"`
+}
+
+// NewMerchantResponse converts a merchant model to its response DTO
+func NewMerchantResponse(m models.Merchant) MerchantResponse {
+	return MerchantResponse{
+		ID:           m.ID,
+		Name:         m.Name,
+		Email:        m.Email,
+		BusinessName: m.BusinessName,
+		Status:       m.Status,
+		KYCStatus:    m.KYCStatus,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+		TotalVolume:  m.TotalVolume,
+		Currency:     m.Currency,
+		Risk:         newMerchantRiskResponse(m.Risk),
+	}
+}
+
+// MerchantStatusResponse DTO for returning the outcome of a merchant or KYC
+// status change
+type MerchantStatusResponse struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// StatsResponse DTO for returning platform statistics
+type StatsResponse struct {
+	TotalMerchants    int     `json:"total_merchants"`
+	ActiveMerchants   int     `json:"active_merchants"`
+	PendingKYC        int     `json:"pending_kyc"`
+	TotalTransactions int     `json:"total_transactions"`
+	TotalVolume       int64   `json:"total_volume"`
+	MonthlyVolume     int64   `json:"monthly_volume"`
+	SuccessRate       float64 `json:"success_rate"`
+	Timestamp         string  `json:"timestamp"`
+}
+
+// NewStatsResponse converts platform statistics to their response DTO
+func NewStatsResponse(s models.PlatformStats) StatsResponse {
+	return StatsResponse{
+		TotalMerchants:    s.TotalMerchants,
+		ActiveMerchants:   s.ActiveMerchants,
+		PendingKYC:        s.PendingKYC,
+		TotalTransactions: s.TotalTransactions,
+		TotalVolume:       s.TotalVolume,
+		MonthlyVolume:     s.MonthlyVolume,
+		SuccessRate:       s.SuccessRate,
+		Timestamp:         s.GeneratedAt.UTC().Format(time.RFC3339),
+	}
+}
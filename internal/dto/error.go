@@ -0,0 +1,13 @@
+package dto
+
+// ErrorResponse is the JSON envelope returned for every failed request
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes a single failure
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// FraudDecisionRequest DTO for approving, declining or escalating a
+// transaction held for fraud review
+type FraudDecisionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// FraudDecisionResponse DTO for returning a fraud review decision
+type FraudDecisionResponse struct {
+	ID            int64     `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Decision      string    `json:"decision"`
+	Reason        string    `json:"reason"`
+	Reviewer      string    `json:"reviewer"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewFraudDecisionResponse converts a fraud decision to its response DTO
+func NewFraudDecisionResponse(d models.FraudDecision) FraudDecisionResponse {
+	return FraudDecisionResponse{
+		ID:            d.ID,
+		TransactionID: d.TransactionID,
+		Decision:      d.Decision,
+		Reason:        d.Reason,
+		Reviewer:      d.Reviewer,
+		CreatedAt:     d.CreatedAt,
+	}
+}
+
+// FraudDecisionListResponse DTO for returning the decisions on a transaction
+type FraudDecisionListResponse struct {
+	Decisions []FraudDecisionResponse `json:"decisions"`
+	Total     int                     `json:"total"`
+}
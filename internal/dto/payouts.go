@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// PayoutListQuery DTO for filtering payouts. MinAmount is in major currency
+// units; Since and Until are RFC 3339 times bounding when payouts were
+// created, Until exclusive. Held only lists the manual review queue.
+type PayoutListQuery struct {
+	MerchantID int     `query:"merchant_id"`
+	Status     string  `query:"status"`
+	Currency   string  `query:"currency"`
+	MinAmount  float64 `query:"min_amount"`
+	Since      string  `query:"since"`
+	Until      string  `query:"until"`
+	Held       bool    `query:"held"`
+	Limit      int     `query:"limit"`
+}
+
+// PayoutActionRequest DTO for holding, releasing, cancelling or retrying a payout
+type PayoutActionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PayoutHoldResponse DTO for why a payout is in the manual review queue
+type PayoutHoldResponse struct {
+	Rule      string    `json:"rule"`
+	Reason    string    `json:"reason"`
+	HeldBy    string    `json:"held_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PayoutResponse DTO for returning a payout, with its hold while it is in
+// the manual review queue
+type PayoutResponse struct {
+	ID           int                 `json:"id"`
+	Reference    string              `json:"reference"`
+	MerchantID   int                 `json:"merchant_id"`
+	MerchantName string              `json:"merchant_name"`
+	Amount       float64             `json:"amount"`
+	Currency     string              `json:"currency"`
+	Status       string              `json:"status"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Hold         *PayoutHoldResponse `json:"hold,omitempty"`
+}
+
+// NewPayoutResponse converts a payout to its response DTO
+func NewPayoutResponse(p models.Payout) PayoutResponse {
+	resp := PayoutResponse{
+		ID:           p.ID,
+		Reference:    p.Reference,
+		MerchantID:   p.MerchantID,
+		MerchantName: p.MerchantName,
+		Amount:       majorUnits(p.Amount),
+		Currency:     p.Currency,
+		Status:       p.Status,
+		CreatedAt:    p.CreatedAt,
+	}
+	if h := p.Hold; h != nil {
+		resp.Hold = &PayoutHoldResponse{Rule: h.Rule, Reason: h.Reason, HeldBy: h.HeldBy, CreatedAt: h.CreatedAt}
+	}
+	return resp
+}
+
+// PayoutListResponse DTO for returning a list of payouts
+type PayoutListResponse struct {
+	Payouts []PayoutResponse `json:"payouts"`
+	Total   int              `json:"total"`
+}
+
+// PayoutActionResponse DTO for returning an action taken on a payout
+type PayoutActionResponse struct {
+	ID        int64     `json:"id"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PayoutDetailResponse DTO for returning a payout with the actions taken on
+// it, newest first
+type PayoutDetailResponse struct {
+	PayoutResponse
+	Actions []PayoutActionResponse `json:"actions"`
+}
+
+// NewPayoutDetailResponse converts a payout and its actions to the detail DTO
+func NewPayoutDetailResponse(p models.Payout, actions []models.PayoutAction) PayoutDetailResponse {
+	resp := PayoutDetailResponse{PayoutResponse: NewPayoutResponse(p), Actions: []PayoutActionResponse{}}
+	for _, a := range actions {
+		resp.Actions = append(resp.Actions, PayoutActionResponse{
+			ID: a.ID, Action: a.Action, Reason: a.Reason, Actor: a.Actor, CreatedAt: a.CreatedAt,
+		})
+	}
+	return resp
+}
+
+// PayoutScreeningResponse DTO for the outcome of screening pending payouts
+// against the review rules: how many were checked and how many were held
+type PayoutScreeningResponse struct {
+	Screened int `json:"screened"`
+	Held     int `json:"held"`
+}
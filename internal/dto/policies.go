@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// PolicyRequest DTO for defining or replacing a suspension policy. Ratio
+// thresholds are fractions from 0 to 1; DryRun defaults to true so a new
+// policy only records what it would do until it is switched off.
+type PolicyRequest struct {
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	Condition       string  `json:"condition"`
+	Threshold       float64 `json:"threshold"`
+	WindowDays      int     `json:"window_days"`
+	MinTransactions int     `json:"min_transactions"`
+	Action          string  `json:"action"`
+	DryRun          *bool   `json:"dry_run"`
+	Enabled         bool    `json:"enabled"`
+}
+
+// PolicyResponse DTO for returning a suspension policy
+type PolicyResponse struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Condition       string    `json:"condition"`
+	Threshold       float64   `json:"threshold"`
+	WindowDays      int       `json:"window_days"`
+	MinTransactions int       `json:"min_transactions"`
+	Action          string    `json:"action"`
+	DryRun          bool      `json:"dry_run"`
+	Enabled         bool      `json:"enabled"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewPolicyResponse converts a suspension policy to its response DTO
+func NewPolicyResponse(p models.SuspensionPolicy) PolicyResponse {
+	return PolicyResponse{
+		ID:              p.ID,
+		Name:            p.Name,
+		Description:     p.Description,
+		Condition:       p.Condition,
+		Threshold:       p.Threshold,
+		WindowDays:      p.WindowDays,
+		MinTransactions: p.MinTransactions,
+		Action:          p.Action,
+		DryRun:          p.DryRun,
+		Enabled:         p.Enabled,
+		CreatedBy:       p.CreatedBy,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+// PolicyListResponse DTO for returning a list of suspension policies
+type PolicyListResponse struct {
+	Policies []PolicyResponse `json:"policies"`
+	Total    int              `json:"total"`
+}
+
+// PolicyMatchListQuery DTO for filtering suspension policy matches
+type PolicyMatchListQuery struct {
+	PolicyID   int64  `query:"policy_id"`
+	MerchantID int    `query:"merchant_id"`
+	Status     string `query:"status"`
+	Limit      int    `query:"limit"`
+}
+
+// ReviewPolicyMatchRequest DTO for approving or dismissing a suspension
+// recommendation
+type ReviewPolicyMatchRequest struct {
+	Note string `json:"note"`
+}
+
+// PolicyMatchResponse DTO for returning a suspension policy match
+type PolicyMatchResponse struct {
+	ID         int64      `json:"id"`
+	PolicyID   int64      `json:"policy_id"`
+	PolicyName string     `json:"policy_name"`
+	MerchantID int        `json:"merchant_id"`
+	Value      float64    `json:"value"`
+	Action     string     `json:"action"`
+	Status     string     `json:"status"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewPolicyMatchResponse converts a suspension policy match to its response DTO
+func NewPolicyMatchResponse(m models.PolicyMatch) PolicyMatchResponse {
+	return PolicyMatchResponse{
+		ID:         m.ID,
+		PolicyID:   m.PolicyID,
+		PolicyName: m.PolicyName,
+		MerchantID: m.MerchantID,
+		Value:      m.Value,
+		Action:     m.Action,
+		Status:     m.Status,
+		ReviewedBy: m.ReviewedBy,
+		ReviewedAt: m.ReviewedAt,
+		ReviewNote: m.ReviewNote,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// PolicyMatchListResponse DTO for returning a list of suspension policy matches
+type PolicyMatchListResponse struct {
+	Matches []PolicyMatchResponse `json:"matches"`
+	Total   int                   `json:"total"`
+}
+
+// PolicyEvaluationResponse DTO for returning the outcome of evaluating the
+// enabled suspension policies: how many were evaluated, and how many merchants
+// they newly suspended, recommended for suspension or matched in dry runs
+type PolicyEvaluationResponse struct {
+	Policies    int `json:"policies"`
+	Suspended   int `json:"suspended"`
+	Recommended int `json:"recommended"`
+	DryRun      int `json:"dry_run"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// MerchantListQuery DTO for filtering and sorting merchants. Sort is
+// "newest" (the default) or "risk" for the riskiest first.
+type MerchantListQuery struct {
+	Sort         string  `query:"sort"`
+	RiskLevel    string  `query:"risk_level"`
+	MinRiskScore float64 `query:"min_risk_score"`
+	Limit        int     `query:"limit"`
+}
+
+// MerchantRiskResponse DTO for returning a merchant's risk score. Factors are
+// only included in merchant detail.
+type MerchantRiskResponse struct {
+	Score      float64              `json:"score"`
+	Level      string               `json:"level"`
+	ComputedAt time.Time            `json:"computed_at"`
+	Factors    []RiskFactorResponse `json:"factors,omitempty"`
+}
+
+// RiskFactorResponse DTO for returning one factor of a risk score
+type RiskFactorResponse struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+}
+
+func newMerchantRiskResponse(r *models.RiskScore) *MerchantRiskResponse {
+	if r == nil {
+		return nil
+	}
+	resp := &MerchantRiskResponse{Score: r.Score, Level: r.Level, ComputedAt: r.ComputedAt}
+	for _, f := range r.Factors {
+		resp.Factors = append(resp.Factors, RiskFactorResponse(f))
+	}
+	return resp
+}
+
+// RiskRecalculationResponse DTO for returning the outcome of recalculating
+// merchant risk scores, with how many merchants are at each level
+type RiskRecalculationResponse struct {
+	Merchants  int            `json:"merchants"`
+	Levels     map[string]int `json:"levels"`
+	ComputedAt time.Time      `json:"computed_at"`
+}
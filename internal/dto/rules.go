@@ -0,0 +1,168 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// CreateRuleRequest DTO for defining a fraud rule
+type CreateRuleRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// ReviseRuleRequest DTO for saving a new version of a fraud rule
+type ReviseRuleRequest struct {
+	Expression string `json:"expression"`
+}
+
+// RuleRangeRequest DTO selecting the transactions created in [from, to) to
+// evaluate rules against. To defaults to now.
+type RuleRangeRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// DryRunRequest DTO for trying a rule against past transactions without
+// raising flags. Expression is only read when dry-running an unsaved rule.
+type DryRunRequest struct {
+	RuleRangeRequest
+	Expression string `json:"expression"`
+	// Limit caps how many matches are listed; all are counted
+	Limit int `json:"limit"`
+}
+
+// RuleResponse DTO for returning a fraud rule at its current version
+type RuleResponse struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Enabled     bool      `json:"enabled"`
+	Version     int       `json:"version"`
+	Expression  string    `json:"expression"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewRuleResponse converts a fraud rule to its response DTO
+func NewRuleResponse(r models.FraudRule) RuleResponse {
+	return RuleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Enabled:     r.Enabled,
+		Version:     r.Version,
+		Expression:  r.Expression,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// RuleListResponse DTO for returning a list of fraud rules
+type RuleListResponse struct {
+	Rules []RuleResponse `json:"rules"`
+	Total int            `json:"total"`
+}
+
+// RuleVersionResponse DTO for one revision of a fraud rule
+type RuleVersionResponse struct {
+	Version    int       `json:"version"`
+	Expression string    `json:"expression"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RuleDetailResponse DTO for returning a fraud rule with its versions, newest first
+type RuleDetailResponse struct {
+	RuleResponse
+	Versions []RuleVersionResponse `json:"versions"`
+}
+
+// RuleMatchResponse DTO for a transaction a rule matched. Amount is in minor
+// currency units.
+type RuleMatchResponse struct {
+	TransactionID int       `json:"transaction_id"`
+	MerchantID    int       `json:"merchant_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DryRunResponse DTO for the outcome of a dry run. Errors counts
+// transactions the rule could not be evaluated on, such as by dividing by zero.
+type DryRunResponse struct {
+	Evaluated int                 `json:"evaluated"`
+	Matched   int                 `json:"matched"`
+	Errors    int                 `json:"errors"`
+	Matches   []RuleMatchResponse `json:"matches"`
+	// Truncated is set when more transactions matched than are listed
+	Truncated bool `json:"truncated"`
+}
+
+// BacktestRequest DTO for measuring how a rule would have performed over past
+// transactions. Expression is only read when backtesting an unsaved rule.
+type BacktestRequest struct {
+	RuleRangeRequest
+	Expression string `json:"expression"`
+}
+
+// BacktestResponse DTO for a rule's hit rate over past transactions, compared
+// with fraud review outcomes. Confirmed fraud is a transaction whose latest
+// final review declined it; confirmed legitimate, one it approved.
+type BacktestResponse struct {
+	Evaluated int `json:"evaluated"`
+	Matched   int `json:"matched"`
+	Errors    int `json:"errors"`
+	// MatchedVolume sums matched amounts per currency, in minor units
+	MatchedVolume       map[string]int64 `json:"matched_volume"`
+	ConfirmedFraud      int              `json:"confirmed_fraud"`
+	FraudMatched        int              `json:"fraud_matched"`
+	ConfirmedLegitimate int              `json:"confirmed_legitimate"`
+	LegitimateMatched   int              `json:"legitimate_matched"`
+	// HitRate is the share of evaluated transactions the rule matched
+	HitRate float64 `json:"hit_rate"`
+	// FraudCaught is the share of confirmed fraud the rule matched; nil
+	// without confirmed fraud in the range
+	FraudCaught *float64 `json:"fraud_caught"`
+	// EstimatedFalsePositiveRate is the share of reviewed matches that were
+	// legitimate; nil when no match was reviewed
+	EstimatedFalsePositiveRate *float64 `json:"estimated_false_positive_rate"`
+}
+
+// EvaluateRulesResponse DTO for the outcome of evaluating the enabled rules
+type EvaluateRulesResponse struct {
+	Rules     int `json:"rules"`
+	Evaluated int `json:"evaluated"`
+	Matched   int `json:"matched"`
+	Errors    int `json:"errors"`
+	// FlagsCreated excludes matches flagged by an earlier evaluation
+	FlagsCreated int `json:"flags_created"`
+}
+
+// FlagListQuery DTO for filtering rule flags
+type FlagListQuery struct {
+	RuleID        int64 `query:"rule_id"`
+	TransactionID int   `query:"transaction_id"`
+	Limit         int   `query:"limit"`
+}
+
+// RuleFlagResponse DTO for a transaction flagged by a rule
+type RuleFlagResponse struct {
+	ID            int64     `json:"id"`
+	RuleID        int64     `json:"rule_id"`
+	RuleName      string    `json:"rule_name"`
+	RuleVersion   int       `json:"rule_version"`
+	TransactionID int       `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RuleFlagListResponse DTO for returning a list of rule flags
+type RuleFlagListResponse struct {
+	Flags []RuleFlagResponse `json:"flags"`
+	Total int                `json:"total"`
+}
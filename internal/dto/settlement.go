@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// SettlementHoldRequest DTO for putting a merchant's settlement on hold.
+// ReservePercent and ReleaseDays apply to rolling reserves; ReserveAmount, in
+// major units of Currency, applies to fixed reserves.
+type SettlementHoldRequest struct {
+	Type           string  `json:"type"`
+	ReservePercent float64 `json:"reserve_percent"`
+	ReleaseDays    int     `json:"release_days"`
+	ReserveAmount  float64 `json:"reserve_amount"`
+	Currency       string  `json:"currency"`
+	Reason         string  `json:"reason"`
+}
+
+// ReleaseSettlementHoldRequest DTO for ending a merchant's settlement hold
+type ReleaseSettlementHoldRequest struct {
+	Reason string `json:"reason"`
+}
+
+// SettlementHoldListQuery DTO for filtering settlement holds. Only holds in
+// effect are listed unless IncludeReleased is set.
+type SettlementHoldListQuery struct {
+	MerchantID      int    `query:"merchant_id"`
+	Type            string `query:"type"`
+	IncludeReleased bool   `query:"include_released"`
+	Limit           int    `query:"limit"`
+}
+
+// SettlementHoldResponse DTO for returning a settlement hold
+type SettlementHoldResponse struct {
+	ID             int64      `json:"id"`
+	MerchantID     int        `json:"merchant_id"`
+	Type           string     `json:"type"`
+	ReservePercent float64    `json:"reserve_percent,omitempty"`
+	ReleaseDays    int        `json:"release_days,omitempty"`
+	ReserveAmount  float64    `json:"reserve_amount,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
+	Reason         string     `json:"reason"`
+	Active         bool       `json:"active"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReleasedBy     string     `json:"released_by,omitempty"`
+	ReleasedAt     *time.Time `json:"released_at,omitempty"`
+	ReleaseReason  string     `json:"release_reason,omitempty"`
+}
+
+// NewSettlementHoldResponse converts a settlement hold to its response DTO
+func NewSettlementHoldResponse(h models.SettlementHold) SettlementHoldResponse {
+	return SettlementHoldResponse{
+		ID:             h.ID,
+		MerchantID:     h.MerchantID,
+		Type:           h.Type,
+		ReservePercent: h.ReservePercent,
+		ReleaseDays:    h.ReleaseDays,
+		ReserveAmount:  majorUnits(h.ReserveAmount),
+		Currency:       h.Currency,
+		Reason:         h.Reason,
+		Active:         h.ReleasedAt == nil,
+		CreatedBy:      h.CreatedBy,
+		CreatedAt:      h.CreatedAt,
+		ReleasedBy:     h.ReleasedBy,
+		ReleasedAt:     h.ReleasedAt,
+		ReleaseReason:  h.ReleaseReason,
+	}
+}
+
+// SettlementHoldListResponse DTO for returning a list of settlement holds
+type SettlementHoldListResponse struct {
+	Holds []SettlementHoldResponse `json:"holds"`
+	Total int                      `json:"total"`
+}
+
+// MerchantSettlementResponse DTO for returning a merchant's settlement hold
+// in effect, null when their settlement is not held, and every hold they
+// have had, newest first
+type MerchantSettlementResponse struct {
+	MerchantID int                      `json:"merchant_id"`
+	Active     *SettlementHoldResponse  `json:"active"`
+	History    []SettlementHoldResponse `json:"history"`
+}
@@ -1,24 +1,89 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
 
 // TransactionResponse DTO for returning transaction information
 type TransactionResponse struct {
+	ID            int    `json:"id"`
+	Reference     string `json:"reference"`
+	MerchantID    int    `json:"merchant_id"`
+	CustomerEmail string `json:"customer_email"`
+	CustomerID    int    `json:"customer_id"`
+	CustomerName  string `json:"customer_name,omitempty"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Status        string `json:"status"`
+	Description   string `json:"description,omitempty"`
+	// CardFingerprint identifies the card across transactions without exposing its number
+	CardFingerprint string    `json:"card_fingerprint,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TransactionListResponse DTO for returning a list of transactions
+type TransactionListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	Total        int                   `json:"total"`
+}
+
+// ActivityResponse DTO for one entry in the admin transactions feed, which
+// lists payments and payouts together. Amounts are in major currency units.
+type ActivityResponse struct {
 	ID            int       `json:"id"`
 	Reference     string    `json:"reference"`
 	MerchantID    int       `json:"merchant_id"`
+	MerchantName  string    `json:"merchant_name"`
 	CustomerEmail string    `json:"customer_email"`
-	CustomerID    int       `json:"customer_id"`
-	CustomerName  string    `json:"customer_name,omitempty"`
-	Amount        int64     `json:"amount"`
+	CustomerName  *string   `json:"customer_name,omitempty"`
+	Amount        float64   `json:"amount"`
 	Currency      string    `json:"currency"`
 	Status        string    `json:"status"`
-	Description   string    `json:"description,omitempty"`
+	PaymentMethod *string   `json:"payment_method,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
+	// Type is "payment" or "payout"
+	Type string `json:"type"`
 }
 
-// TransactionListResponse DTO for returning a list of transactions
-type TransactionListResponse struct {
-	Transactions []TransactionResponse `json:"transactions"`
-	Total        int                   `json:"total"`
-}
\ No newline at end of file
+// NewPaymentActivity converts a transaction to a feed entry
+func NewPaymentActivity(t models.Transaction) ActivityResponse {
+	return ActivityResponse{
+		ID:            t.ID,
+		Reference:     t.Reference,
+		MerchantID:    t.MerchantID,
+		MerchantName:  t.MerchantName,
+		CustomerEmail: t.CustomerEmail,
+		CustomerName:  t.CustomerName,
+		Amount:        majorUnits(t.Amount),
+		Currency:      t.Currency,
+		Status:        t.Status,
+		PaymentMethod: t.PaymentMethod,
+		CreatedAt:     t.CreatedAt,
+		Type:          "payment",
+	}
+}
+
+// NewPayoutActivity converts a payout to a feed entry. Payouts have no
+// customer, and report "payout" as their payment method.
+func NewPayoutActivity(p models.Payout) ActivityResponse {
+	empty, method := "", "payout"
+	return ActivityResponse{
+		ID:            p.ID,
+		Reference:     p.Reference,
+		MerchantID:    p.MerchantID,
+		MerchantName:  p.MerchantName,
+		CustomerName:  &empty,
+		Amount:        majorUnits(p.Amount),
+		Currency:      p.Currency,
+		Status:        p.Status,
+		PaymentMethod: &method,
+		CreatedAt:     p.CreatedAt,
+		Type:          "payout",
+	}
+}
+
+func majorUnits(minor int64) float64 {
+	return float64(minor) / 100
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+)
+
+func alertID(c *fiber.Ctx) (int64, error) {
+	return int64Param(c, "id", "alert")
+}
+
+func (h *AdminHandler) ListAlerts(c *fiber.Ctx) error {
+	var q dto.AlertListQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	q.Status, q.Type = utils.CopyString(q.Status), utils.CopyString(q.Type)
+	result, err := h.svc.ListAlerts(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) DetectAnomalies(c *fiber.Ctx) error {
+	result, err := h.svc.DetectAnomalies(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) GetAlert(c *fiber.Ctx) error {
+	id, err := alertID(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.GetAlert(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) AcknowledgeAlert(c *fiber.Ctx) error {
+	id, err := alertID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.AcknowledgeAlert(requestContext(c), id, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) ResolveAlert(c *fiber.Ctx) error {
+	id, err := alertID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.ResolveAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.ResolveAlert(requestContext(c), id, req, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/services"
+)
+
+func (h *AdminHandler) ListBlocklist(c *fiber.Ctx) error {
+	var q dto.BlocklistQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	q.Type = utils.CopyString(q.Type)
+	q.Search = utils.CopyString(q.Search)
+	result, err := h.svc.ListBlocklist(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) CreateBlocklistEntry(c *fiber.Ctx) error {
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.CreateBlocklistEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.CreateBlocklistEntry(requestContext(c), req, actor)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// ExpireBlocklistEntry accepts an optional body; without one the entry
+// expires now.
+func (h *AdminHandler) ExpireBlocklistEntry(c *fiber.Ctx) error {
+	id, err := int64Param(c, "id", "blocklist entry")
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.ExpireBlocklistEntryRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	result, err := h.svc.ExpireBlocklistEntry(requestContext(c), id, req, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// ImportBlocklist accepts a multipart upload with the CSV in the "file"
+// field and an optional default reason in the "reason" field.
+func (h *AdminHandler) ImportBlocklist(c *fiber.Ctx) error {
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "A multipart file field named file is required")
+	}
+	if header.Size > services.MaxBlocklistImportSize {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, "Import file is too large")
+	}
+	f, err := header.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Unreadable import file")
+	}
+	defer f.Close()
+
+	result, err := h.svc.ImportBlocklist(requestContext(c), io.LimitReader(f, services.MaxBlocklistImportSize),
+		utils.CopyString(c.FormValue("reason")), actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// CheckBlocklist is the cheap lookup other services call before accepting
+// a payment.
+func (h *AdminHandler) CheckBlocklist(c *fiber.Ctx) error {
+	var q dto.BlocklistCheckQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	result, err := h.svc.CheckBlocklist(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/services"
+)
+
+// int64Param parses a positive ID from the named path parameter, describing
+// it as what in the error.
+func int64Param(c *fiber.Ctx, name, what string) (int64, error) {
+	id, err := strconv.ParseInt(c.Params(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid "+what+" ID")
+	}
+	return id, nil
+}
+
+func caseID(c *fiber.Ctx) (int64, error) {
+	return int64Param(c, "id", "case")
+}
+
+func (h *AdminHandler) ListCases(c *fiber.Ctx) error {
+	var q dto.CaseListQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	q.Status = utils.CopyString(q.Status)
+	q.Assignee = utils.CopyString(q.Assignee)
+	result, err := h.svc.ListCases(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) SyncFraudCases(c *fiber.Ctx) error {
+	result, err := h.svc.SyncFraudCases(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) GetCase(c *fiber.Ctx) error {
+	id, err := caseID(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.GetCase(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) AssignCase(c *fiber.Ctx) error {
+	id, err := caseID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.AssignCaseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.AssignCase(requestContext(c), id, req.Assignee, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) SetCaseStatus(c *fiber.Ctx) error {
+	id, err := caseID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.CaseStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.SetCaseStatus(requestContext(c), id, req, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) AddCaseComment(c *fiber.Ctx) error {
+	id, err := caseID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.CaseCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.AddCaseComment(requestContext(c), id, req.Body, actor)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// AddCaseAttachment accepts a multipart upload with the file in the "file" field.
+func (h *AdminHandler) AddCaseAttachment(c *fiber.Ctx) error {
+	id, err := caseID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "A multipart file field named file is required")
+	}
+	if header.Size > services.MaxAttachmentSize {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, "Attachment is too large")
+	}
+	f, err := header.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Unreadable attachment")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, services.MaxAttachmentSize+1))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Unreadable attachment")
+	}
+
+	result, err := h.svc.AddCaseAttachment(requestContext(c), id, header.Filename,
+		header.Header.Get(fiber.HeaderContentType), data, actor)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// GetCaseAttachment downloads an attachment. It is always served as a
+// download so uploaded HTML or scripts never render in the admin origin.
+func (h *AdminHandler) GetCaseAttachment(c *fiber.Ctx) error {
+	id, err := caseID(c)
+	if err != nil {
+		return err
+	}
+	attachmentID, err := int64Param(c, "attachmentId", "attachment")
+	if err != nil {
+		return err
+	}
+	a, err := h.svc.GetCaseAttachment(requestContext(c), id, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, a.ContentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	return c.Send(a.Data)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"io/fs"
+	"path"
+
+	"github.com/gofiber/fiber/v2"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"github.com/kodra-pay/admin-service/internal/openapi"
+)
+
+// OpenAPI returns the OpenAPI document for the admin API
+func (h *AdminHandler) OpenAPI(c *fiber.Ctx) error {
+	c.Type("json")
+	return c.Send(openapi.Spec)
+}
+
+// Docs returns the Swagger UI page
+func (h *AdminHandler) Docs(c *fiber.Ctx) error {
+	c.Type("html")
+	return c.Send(openapi.SwaggerUI)
+}
+
+// DocsAsset returns one of the embedded Swagger UI scripts, stylesheets or icons
+func (h *AdminHandler) DocsAsset(c *fiber.Ctx) error {
+	name := c.Params("file")
+	data, err := fs.ReadFile(swaggerFiles.FS, name)
+	if err != nil || !fs.ValidPath(name) {
+		return fiber.ErrNotFound
+	}
+	c.Type(path.Ext(name))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return c.Send(data)
+}
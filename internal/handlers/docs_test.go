@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/openapi"
+)
+
+var (
+	pathParam = regexp.MustCompile(`:(\w+)`)
+	versioned = regexp.MustCompile(`^/admin/v\d+/`)
+)
+
+// TestSpecMatchesRoutes fails when a route is registered without being
+// described in the OpenAPI document, or the document describes a route that
+// no longer exists.
+func TestSpecMatchesRoutes(t *testing.T) {
+	app := fiber.New()
+	NewAdminHandler(nil, nil).Register(app, nil)
+
+	registered := map[string]bool{}
+	for _, r := range app.GetRoutes(true) {
+		if r.Method == fiber.MethodHead || !strings.HasPrefix(r.Path, "/admin") {
+			continue
+		}
+		registered[strings.ToLower(r.Method)+" "+pathParam.ReplaceAllString(r.Path, "{$1}")] = true
+	}
+	// Legacy unversioned routes are documented through the version they alias.
+	for key := range registered {
+		method, path, _ := strings.Cut(key, " ")
+		if versioned.MatchString(path) {
+			continue
+		}
+		if alias := method + " /admin/" + legacyVersion + strings.TrimPrefix(path, "/admin"); registered[alias] {
+			delete(registered, key)
+		}
+	}
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	documented := map[string]bool{}
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented[method+" "+path] = true
+		}
+	}
+
+	if missing := difference(registered, documented); len(missing) > 0 {
+		t.Errorf("routes missing from openapi.json: %v", missing)
+	}
+	if stale := difference(documented, registered); len(stale) > 0 {
+		t.Errorf("openapi.json describes routes that are not registered: %v", stale)
+	}
+}
+
+// TestSpecReferencesResolve fails when a $ref names a component that is not
+// defined.
+func TestSpecReferencesResolve(t *testing.T) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		t.Fatal(err)
+	}
+	components := doc["components"].(map[string]interface{})
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				parts := strings.Split(strings.TrimPrefix(ref, "#/components/"), "/")
+				section, _ := components[parts[0]].(map[string]interface{})
+				if len(parts) != 2 || section[parts[1]] == nil {
+					t.Errorf("unresolved $ref %s", ref)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+}
+
+func TestDocsServed(t *testing.T) {
+	app := fiber.New()
+	NewAdminHandler(nil, nil).Register(app, nil)
+
+	for path, contentType := range map[string]string{
+		"/admin/openapi.json":              "application/json",
+		"/admin/docs":                      "text/html",
+		"/admin/docs/swagger-ui-bundle.js": "javascript",
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), contentType) {
+			t.Errorf("GET %s: %d %s", path, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/docs/missing.js", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("missing asset: got %d, want 404", resp.StatusCode)
+	}
+}
+
+func difference(a, b map[string]bool) []string {
+	var out []string
+	for k := range a {
+		if !b[k] {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
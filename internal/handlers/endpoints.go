@@ -1,25 +1,77 @@
 package handlers
 
 import (
-	"log"
+	"context"
+	"strconv"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/logging"
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/models"
 	"github.com/kodra-pay/admin-service/internal/services"
+	"github.com/kodra-pay/admin-service/internal/settings"
 )
 
 type AdminHandler struct {
-	svc *services.AdminService
+	svc      *services.AdminService
+	settings *settings.Store
+}
+
+func NewAdminHandler(svc *services.AdminService, settingsStore *settings.Store) *AdminHandler {
+	return &AdminHandler{svc: svc, settings: settingsStore}
+}
+
+// routeIDKeys names the log attribute for the :id parameter of the routes
+// under each path.
+var routeIDKeys = []struct{ path, key string }{
+	{"/merchants/:id", "merchant_id"},
+	{"/transactions/:id", "transaction_id"},
+	{"/payouts/:id", "payout_id"},
+	{"/fraud/cases/:id", "case_id"},
+	{"/fraud/rules/:id", "rule_id"},
+	{"/alerts/:id", "alert_id"},
+	{"/suspension-policies/:id", "policy_id"},
+	{"/suspension-policies/matches/:id", "policy_match_id"},
 }
 
-func NewAdminHandler(svc *services.AdminService) *AdminHandler {
-	return &AdminHandler{svc: svc}
+// requestContext returns the request context annotated for logging with the
+// matched route and the ID of the resource it acts on.
+func requestContext(c *fiber.Ctx) context.Context {
+	route := c.Route().Path
+	args := []any{"route", route}
+	for _, r := range routeIDKeys {
+		if !strings.Contains(route, r.path) {
+			continue
+		}
+		if id, err := strconv.Atoi(c.Params("id")); err == nil {
+			args = append(args, r.key, id)
+		}
+		break
+	}
+	return logging.With(c.UserContext(), args...)
+}
+
+// actorFrom identifies the admin making a change from the X-Admin-ID header,
+// which mutating endpoints that write the audit log require.
+func actorFrom(c *fiber.Ctx) (services.Actor, error) {
+	id := c.Get(middleware.ActorHeader)
+	if id == "" {
+		return services.Actor{}, fiber.NewError(fiber.StatusBadRequest, middleware.ActorHeader+" header is required")
+	}
+	return services.Actor{
+		ID:        utils.CopyString(id),
+		RequestID: utils.CopyString(middleware.GetRequestID(c)),
+	}, nil
 }
 
 func (h *AdminHandler) ListPendingMerchants(c *fiber.Ctx) error {
-	log.Println("AdminHandler: ListPendingMerchants called.")
-	merchants, err := h.svc.ListPendingMerchants(c.Context())
+	merchants, err := h.svc.ListPendingMerchants(requestContext(c))
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(merchants)
 }
@@ -29,7 +81,11 @@ func (h *AdminHandler) ApproveMerchantKYC(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
 	}
-	return c.JSON(h.svc.ApproveMerchantKYC(c.Context(), id))
+	result, err := h.svc.ApproveMerchantKYC(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
 }
 
 func (h *AdminHandler) RejectMerchantKYC(c *fiber.Ctx) error {
@@ -37,7 +93,11 @@ func (h *AdminHandler) RejectMerchantKYC(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
 	}
-	return c.JSON(h.svc.RejectMerchantKYC(c.Context(), id))
+	result, err := h.svc.RejectMerchantKYC(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
 }
 
 func (h *AdminHandler) EnableMerchantKYC(c *fiber.Ctx) error {
@@ -45,35 +105,70 @@ func (h *AdminHandler) EnableMerchantKYC(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
 	}
-	return c.JSON(h.svc.EnableMerchantKYC(c.Context(), id))
+	result, err := h.svc.EnableMerchantKYC(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
 }
 
 func (h *AdminHandler) Transactions(c *fiber.Ctx) error {
-	transactions, err := h.svc.Transactions(c.Context())
+	transactions, err := h.svc.Transactions(requestContext(c))
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(transactions)
 }
 
 func (h *AdminHandler) Stats(c *fiber.Ctx) error {
-	return c.JSON(h.svc.Stats(c.Context()))
+	stats, err := h.svc.Stats(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(stats)
 }
 
 func (h *AdminHandler) ListMerchants(c *fiber.Ctx) error {
-	log.Println("AdminHandler: ListMerchants called.")
-	merchants, err := h.svc.ListMerchants(c.Context())
+	var q dto.MerchantListQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	q.Sort, q.RiskLevel = utils.CopyString(q.Sort), utils.CopyString(q.RiskLevel)
+	merchants, err := h.svc.ListMerchants(requestContext(c), q)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(merchants)
 }
 
+// GetMerchant returns a merchant with its risk score and factors
+func (h *AdminHandler) GetMerchant(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
+	}
+	result, err := h.svc.GetMerchant(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// RecalculateRiskScores rescores every merchant now rather than waiting for
+// the background recalculation
+func (h *AdminHandler) RecalculateRiskScores(c *fiber.Ctx) error {
+	result, err := h.svc.RecalculateRiskScores(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
 func (h *AdminHandler) ListFraudulentTransactions(c *fiber.Ctx) error {
-	limit := c.QueryInt("limit", 50)
-	resp, err := h.svc.ListFraudulentTransactions(c.Context(), limit)
+	limit := c.QueryInt("limit", 0)
+	resp, err := h.svc.ListFraudulentTransactions(requestContext(c), limit)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(resp)
 }
@@ -83,28 +178,219 @@ func (h *AdminHandler) ApproveMerchant(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
 	}
-	return c.JSON(h.svc.ApproveMerchant(c.Context(), id))
+	result, err := h.svc.ApproveMerchant(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
 }
 
+// SuspendMerchant suspends the merchant in the path. The admin is identified
+// by the X-Admin-ID header for the audit log; the header is optional because
+// clients written before it existed still call this endpoint.
 func (h *AdminHandler) SuspendMerchant(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
 	}
-	return c.JSON(h.svc.SuspendMerchant(c.Context(), id))
-}
-
-// Register registers all admin routes
-func (h *AdminHandler) Register(app *fiber.App) {
-	admin := app.Group("/admin")
-	admin.Get("/merchants", h.ListMerchants)
-	admin.Get("/merchants/pending", h.ListPendingMerchants)
-	admin.Post("/merchants/:id/approve", h.ApproveMerchant)
-	admin.Post("/merchants/:id/suspend", h.SuspendMerchant)
-	admin.Post("/merchants/:id/kyc/approve", h.ApproveMerchantKYC)
-	admin.Post("/merchants/:id/kyc/reject", h.RejectMerchantKYC)
-	admin.Post("/merchants/:id/kyc/enable", h.EnableMerchantKYC)
-	admin.Get("/transactions", h.Transactions)
-	admin.Get("/transactions/fraud", h.ListFraudulentTransactions) // New route for fraudulent transactions
-	admin.Get("/stats", h.Stats)
+	actor, err := actorFrom(c)
+	if err != nil {
+		actor = services.Actor{ID: services.AnonymousActor, RequestID: utils.CopyString(middleware.GetRequestID(c))}
+	}
+	result, err := h.svc.SuspendMerchant(requestContext(c), id, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// DecideFraudReview returns a handler applying decision to the transaction
+// in the path. The reviewer is identified by the X-Admin-ID header.
+func (h *AdminHandler) DecideFraudReview(decision string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := c.ParamsInt("id")
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid transaction ID")
+		}
+		actor, err := actorFrom(c)
+		if err != nil {
+			return err
+		}
+		var req dto.FraudDecisionRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+			}
+		}
+
+		result, err := h.svc.DecideFraudReview(requestContext(c), models.FraudDecision{
+			TransactionID: id,
+			Decision:      decision,
+			Reason:        req.Reason,
+			Reviewer:      actor.ID,
+			RequestID:     actor.RequestID,
+		})
+		if err != nil {
+			return err
+		}
+		return c.JSON(result)
+	}
+}
+
+// ListFraudDecisions returns the review decisions on a transaction
+func (h *AdminHandler) ListFraudDecisions(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid transaction ID")
+	}
+	result, err := h.svc.ListFraudDecisions(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// Settings returns the runtime settings currently in effect
+func (h *AdminHandler) Settings(c *fiber.Ctx) error {
+	return c.JSON(h.settings.Snapshot())
+}
+
+// route is one endpoint of a versioned API.
+type route struct {
+	method  string
+	path    string
+	handler fiber.Handler
+}
+
+// apiVersion is a version of the admin API served under /admin/<name>.
+type apiVersion struct {
+	name   string
+	routes []route
+}
+
+// legacyVersion is the version the unversioned /admin routes alias.
+const legacyVersion = "v1"
+
+// versions lists every API version, served side by side. A new version
+// starts from the previous one with only the changed routes replaced, e.g.
+//
+//	{"v2", override(v1, route{fiber.MethodGet, "/merchants", h.ListMerchantsV2})}
+func (h *AdminHandler) versions() []apiVersion {
+	v1 := []route{
+		{fiber.MethodGet, "/merchants", h.ListMerchants},
+		{fiber.MethodGet, "/merchants/pending", h.ListPendingMerchants},
+		{fiber.MethodPost, "/merchants/risk/recalculate", h.RecalculateRiskScores},
+		{fiber.MethodGet, "/merchants/:id", h.GetMerchant},
+		{fiber.MethodPost, "/merchants/:id/approve", h.ApproveMerchant},
+		{fiber.MethodPost, "/merchants/:id/suspend", h.SuspendMerchant},
+		{fiber.MethodPost, "/merchants/:id/kyc/approve", h.ApproveMerchantKYC},
+		{fiber.MethodPost, "/merchants/:id/kyc/reject", h.RejectMerchantKYC},
+		{fiber.MethodPost, "/merchants/:id/kyc/enable", h.EnableMerchantKYC},
+		{fiber.MethodGet, "/merchants/:id/settlement-hold", h.GetMerchantSettlement},
+		{fiber.MethodPut, "/merchants/:id/settlement-hold", h.SetSettlementHold},
+		{fiber.MethodPost, "/merchants/:id/settlement-hold/release", h.ReleaseSettlementHold},
+		{fiber.MethodGet, "/settlement-holds", h.ListSettlementHolds},
+		{fiber.MethodGet, "/transactions", h.Transactions},
+		{fiber.MethodGet, "/transactions/fraud", h.ListFraudulentTransactions},
+		{fiber.MethodGet, "/transactions/:id/fraud/decisions", h.ListFraudDecisions},
+		{fiber.MethodPost, "/transactions/:id/fraud/approve", h.DecideFraudReview(models.FraudDecisionApproved)},
+		{fiber.MethodPost, "/transactions/:id/fraud/decline", h.DecideFraudReview(models.FraudDecisionDeclined)},
+		{fiber.MethodPost, "/transactions/:id/fraud/escalate", h.DecideFraudReview(models.FraudDecisionEscalated)},
+		{fiber.MethodGet, "/payouts", h.ListPayouts},
+		{fiber.MethodPost, "/payouts/screen", h.ScreenPayouts},
+		{fiber.MethodGet, "/payouts/:id", h.GetPayout},
+		{fiber.MethodPost, "/payouts/:id/hold", h.PayoutAction(models.PayoutActionHold)},
+		{fiber.MethodPost, "/payouts/:id/release", h.PayoutAction(models.PayoutActionRelease)},
+		{fiber.MethodPost, "/payouts/:id/cancel", h.PayoutAction(models.PayoutActionCancel)},
+		{fiber.MethodPost, "/payouts/:id/retry", h.PayoutAction(models.PayoutActionRetry)},
+		{fiber.MethodGet, "/fraud/cases", h.ListCases},
+		{fiber.MethodPost, "/fraud/cases/sync", h.SyncFraudCases},
+		{fiber.MethodGet, "/fraud/cases/:id", h.GetCase},
+		{fiber.MethodPost, "/fraud/cases/:id/assign", h.AssignCase},
+		{fiber.MethodPost, "/fraud/cases/:id/status", h.SetCaseStatus},
+		{fiber.MethodPost, "/fraud/cases/:id/comments", h.AddCaseComment},
+		{fiber.MethodPost, "/fraud/cases/:id/attachments", h.AddCaseAttachment},
+		{fiber.MethodGet, "/fraud/cases/:id/attachments/:attachmentId", h.GetCaseAttachment},
+		{fiber.MethodGet, "/fraud/rules", h.ListRules},
+		{fiber.MethodPost, "/fraud/rules", h.CreateRule},
+		{fiber.MethodPost, "/fraud/rules/dry-run", h.DryRunExpression},
+		{fiber.MethodPost, "/fraud/rules/backtest", h.BacktestExpression},
+		{fiber.MethodPost, "/fraud/rules/evaluate", h.EvaluateRules},
+		{fiber.MethodGet, "/fraud/rules/:id", h.GetRule},
+		{fiber.MethodPost, "/fraud/rules/:id/versions", h.ReviseRule},
+		{fiber.MethodPost, "/fraud/rules/:id/enable", h.SetRuleEnabled(true)},
+		{fiber.MethodPost, "/fraud/rules/:id/disable", h.SetRuleEnabled(false)},
+		{fiber.MethodPost, "/fraud/rules/:id/dry-run", h.DryRunRule},
+		{fiber.MethodPost, "/fraud/rules/:id/backtest", h.BacktestRule},
+		{fiber.MethodGet, "/fraud/flags", h.ListRuleFlags},
+		{fiber.MethodGet, "/blocklist", h.ListBlocklist},
+		{fiber.MethodPost, "/blocklist", h.CreateBlocklistEntry},
+		{fiber.MethodPost, "/blocklist/import", h.ImportBlocklist},
+		{fiber.MethodGet, "/blocklist/check", h.CheckBlocklist},
+		{fiber.MethodPost, "/blocklist/:id/expire", h.ExpireBlocklistEntry},
+		{fiber.MethodGet, "/alerts", h.ListAlerts},
+		{fiber.MethodPost, "/alerts/detect", h.DetectAnomalies},
+		{fiber.MethodGet, "/alerts/:id", h.GetAlert},
+		{fiber.MethodPost, "/alerts/:id/acknowledge", h.AcknowledgeAlert},
+		{fiber.MethodPost, "/alerts/:id/resolve", h.ResolveAlert},
+		{fiber.MethodGet, "/suspension-policies", h.ListPolicies},
+		{fiber.MethodPost, "/suspension-policies", h.CreatePolicy},
+		{fiber.MethodPost, "/suspension-policies/evaluate", h.EvaluatePolicies},
+		{fiber.MethodGet, "/suspension-policies/matches", h.ListPolicyMatches},
+		{fiber.MethodPost, "/suspension-policies/matches/:id/approve", h.ReviewPolicyMatch(true)},
+		{fiber.MethodPost, "/suspension-policies/matches/:id/dismiss", h.ReviewPolicyMatch(false)},
+		{fiber.MethodGet, "/suspension-policies/:id", h.GetPolicy},
+		{fiber.MethodPut, "/suspension-policies/:id", h.UpdatePolicy},
+		{fiber.MethodGet, "/stats", h.Stats},
+		{fiber.MethodGet, "/settings", h.Settings},
+	}
+	return []apiVersion{{"v1", v1}}
+}
+
+// override returns base with the routes in replacements substituted for those
+// with the same method and path, and any other replacements added.
+func override(base []route, replacements ...route) []route {
+	routes := append([]route(nil), base...)
+	for _, r := range replacements {
+		replaced := false
+		for i := range routes {
+			if routes[i].method == r.method && routes[i].path == r.path {
+				routes[i], replaced = r, true
+			}
+		}
+		if !replaced {
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
+// Register registers every API version under /admin/<version>, the legacy
+// unversioned /admin routes as an alias of v1, and the API docs. Handler
+// legacy, when non-nil, runs before each legacy route, typically
+// middleware.Deprecation. Any middleware given is applied to the whole /admin
+// group.
+func (h *AdminHandler) Register(app *fiber.App, legacy fiber.Handler, middleware ...fiber.Handler) {
+	admin := app.Group("/admin", middleware...)
+	for _, v := range h.versions() {
+		group := admin.Group("/" + v.name)
+		for _, r := range v.routes {
+			group.Add(r.method, r.path, r.handler)
+		}
+
+		if v.name != legacyVersion {
+			continue
+		}
+		for _, r := range v.routes {
+			if legacy != nil {
+				admin.Add(r.method, r.path, legacy, r.handler)
+			} else {
+				admin.Add(r.method, r.path, r.handler)
+			}
+		}
+	}
+
+	admin.Get("/openapi.json", h.OpenAPI)
+	admin.Get("/docs", h.Docs)
+	admin.Get("/docs/:file", h.DocsAsset)
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/logging"
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/services"
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+func TestLegacyRoutesAliasV1WithDeprecationHeaders(t *testing.T) {
+	store, err := settings.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deprecatedAt := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+	legacy := middleware.Deprecation(deprecatedAt, sunset, func(path string) string { return "/admin/v1" + path[len("/admin"):] })
+
+	app := fiber.New()
+	NewAdminHandler(nil, store).Register(app, legacy)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin/settings", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("legacy route: got %d", resp.StatusCode)
+	}
+	for header, want := range map[string]string{
+		"Deprecation": "@1793491200",
+		"Sunset":      "Sat, 01 May 2027 00:00:00 GMT",
+		"Link":        `</admin/v1/settings>; rel="successor-version"`,
+	} {
+		if got := resp.Header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/v1/settings", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("v1 route: got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Deprecation"); got != "" {
+		t.Errorf("v1 route has Deprecation header %q", got)
+	}
+}
+
+func TestOverrideReplacesAndAddsRoutes(t *testing.T) {
+	handler := func(name string) fiber.Handler {
+		return func(*fiber.Ctx) error { return errors.New(name) }
+	}
+	base := []route{{fiber.MethodGet, "/merchants", handler("v1")}, {fiber.MethodGet, "/stats", handler("v1")}}
+
+	routes := override(base, route{fiber.MethodGet, "/stats", handler("v2")}, route{fiber.MethodGet, "/payouts", handler("v2")})
+	want := []struct{ path, handler string }{{"/merchants", "v1"}, {"/stats", "v2"}, {"/payouts", "v2"}}
+	if len(routes) != len(want) {
+		t.Fatalf("got %d routes, want %d", len(routes), len(want))
+	}
+	for i, w := range want {
+		if routes[i].path != w.path || routes[i].handler(nil).Error() != w.handler {
+			t.Errorf("route %d = %s %s, want %s %s", i, routes[i].path, routes[i].handler(nil), w.path, w.handler)
+		}
+	}
+	if base[1].handler(nil).Error() != "v1" {
+		t.Error("override modified its base")
+	}
+}
+
+func TestLegacySuspendWithoutAdminHeader(t *testing.T) {
+	store, err := settings.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repositories.NewMemoryStore()
+	repo.AddMerchant(models.Merchant{ID: 1, Status: "active"})
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	NewAdminHandler(services.NewAdminService(repo, "", "", nil, store), store).Register(app, nil)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/admin/merchants/1/suspend", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("got %d, want 200", resp.StatusCode)
+	}
+	log := repo.AuditLog()
+	if len(log) != 1 || log[0].Actor != services.AnonymousActor || log[0].Action != "merchant.suspended" {
+		t.Errorf("audit log %+v", log)
+	}
+}
+
+func TestRequestContextLogsResourceID(t *testing.T) {
+	tests := []struct {
+		route, path, want string
+	}{
+		{"/admin/v1/merchants/:id/suspend", "/admin/v1/merchants/7/suspend", `"merchant_id":7`},
+		{"/admin/v1/payouts/:id/hold", "/admin/v1/payouts/7/hold", `"payout_id":7`},
+		{"/admin/v1/suspension-policies/:id", "/admin/v1/suspension-policies/7", `"policy_id":7`},
+		{"/admin/v1/suspension-policies/matches/:id/dismiss", "/admin/v1/suspension-policies/matches/7/dismiss", `"policy_match_id":7`},
+		{"/admin/v1/merchants/:id", "/admin/v1/merchants/abc", `"route":"/admin/v1/merchants/:id"}`},
+		{"/admin/v1/stats", "/admin/v1/stats", `"route":"/admin/v1/stats"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logging.New(&buf, slog.LevelInfo, "json")
+			app := fiber.New()
+			app.Get(tt.route, func(c *fiber.Ctx) error {
+				logger.InfoContext(requestContext(c), "handled")
+				return nil
+			})
+			if _, err := app.Test(httptest.NewRequest("GET", tt.path, nil)); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("log %s, want %s", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/services"
+)
+
+// ErrorHandler renders every error returned by a handler as a dto.ErrorResponse
+// with a status code derived from the service error kind.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	body := dto.ErrorBody{Code: "internal_error", Message: "internal server error"}
+
+	var svcErr *services.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &svcErr):
+		status = statusForKind(svcErr)
+		body.Code = svcErr.Code
+		body.Message = svcErr.Message
+	case errors.As(err, &fiberErr):
+		status = fiberErr.Code
+		body.Code = codeForStatus(status)
+		body.Message = fiberErr.Message
+	}
+
+	body.RequestID = middleware.GetRequestID(c)
+	if status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "request failed",
+			"route", c.Route().Path, "status", status, "error", err)
+	}
+	return c.Status(status).JSON(dto.ErrorResponse{Error: body})
+}
+
+func statusForKind(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		return fiber.StatusConflict
+	case errors.Is(err, services.ErrValidation):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrDownstreamUnavailable):
+		return fiber.StatusBadGateway
+	case errors.Is(err, services.ErrUnavailable):
+		return fiber.StatusServiceUnavailable
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// codeForStatus turns a status such as 422 into "unprocessable_entity".
+func codeForStatus(status int) string {
+	return strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/services"
+)
+
+func TestErrorHandler(t *testing.T) {
+	svcErr := func(kind error, code string) error {
+		return &services.Error{Kind: kind, Code: code, Message: "something went wrong", Err: errors.New("cause")}
+	}
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   dto.ErrorBody
+	}{
+		{"not found", svcErr(services.ErrNotFound, "merchant_not_found"), fiber.StatusNotFound,
+			dto.ErrorBody{Code: "merchant_not_found", Message: "something went wrong"}},
+		{"conflict", svcErr(services.ErrConflict, "payout_held"), fiber.StatusConflict,
+			dto.ErrorBody{Code: "payout_held", Message: "something went wrong"}},
+		{"validation", svcErr(services.ErrValidation, "reason_required"), fiber.StatusBadRequest,
+			dto.ErrorBody{Code: "reason_required", Message: "something went wrong"}},
+		{"downstream unavailable", svcErr(services.ErrDownstreamUnavailable, "downstream_unavailable"), fiber.StatusBadGateway,
+			dto.ErrorBody{Code: "downstream_unavailable", Message: "something went wrong"}},
+		{"unavailable", svcErr(services.ErrUnavailable, "database_unavailable"), fiber.StatusServiceUnavailable,
+			dto.ErrorBody{Code: "database_unavailable", Message: "something went wrong"}},
+		{"wrapped service error", fmt.Errorf("context: %w", svcErr(services.ErrNotFound, "payout_not_found")), fiber.StatusNotFound,
+			dto.ErrorBody{Code: "payout_not_found", Message: "something went wrong"}},
+		{"fiber error", fiber.NewError(fiber.StatusUnprocessableEntity, "Idempotency-Key reused"), fiber.StatusUnprocessableEntity,
+			dto.ErrorBody{Code: "unprocessable_entity", Message: "Idempotency-Key reused"}},
+		{"unknown route", nil, fiber.StatusNotFound,
+			dto.ErrorBody{Code: "not_found", Message: "Cannot GET /missing"}},
+		{"other error", errors.New("pq: connection refused"), fiber.StatusInternalServerError,
+			dto.ErrorBody{Code: "internal_error", Message: "internal server error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(middleware.RequestID())
+			app.Get("/fail", func(c *fiber.Ctx) error { return tt.err })
+
+			path := "/fail"
+			if tt.err == nil {
+				path = "/missing"
+			}
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body dto.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			tt.wantBody.RequestID = "req-1"
+			if resp.StatusCode != tt.wantStatus || body.Error != tt.wantBody {
+				t.Errorf("got %d %+v, want %d %+v", resp.StatusCode, body.Error, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
@@ -1,19 +1,54 @@
 package handlers
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/health"
+)
 
 type HealthHandler struct {
-	Service string
+	Service  string
+	checker  *health.Checker
+	draining atomic.Bool
 }
 
-func NewHealthHandler(service string) *HealthHandler {
-	return &HealthHandler{Service: service}
+func NewHealthHandler(service string, checker *health.Checker) *HealthHandler {
+	return &HealthHandler{Service: service, checker: checker}
 }
 
 func (h *HealthHandler) Register(r fiber.Router) {
 	r.Get("/health", h.Health)
+	r.Get("/health/live", h.Health)
+	r.Get("/health/ready", h.Ready)
 }
 
+// Health reports that the process is up; it does not look at dependencies.
 func (h *HealthHandler) Health(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"status": "ok", "service": h.Service})
 }
+
+// SetDraining makes readiness fail so load balancers stop routing new
+// requests while the service shuts down.
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Ready checks every dependency and answers 503 when a critical one is down.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	if h.draining.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "draining", "service": h.Service})
+	}
+	report := h.checker.Check(c.UserContext())
+	status := "ok"
+	if !report.Ready {
+		status = "unavailable"
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(fiber.Map{
+		"status":       status,
+		"service":      h.Service,
+		"dependencies": report.Dependencies,
+	})
+}
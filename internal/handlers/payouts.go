@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func payoutID(c *fiber.Ctx) (int, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid payout ID")
+	}
+	return id, nil
+}
+
+func (h *AdminHandler) ListPayouts(c *fiber.Ctx) error {
+	var q dto.PayoutListQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	q.Status = utils.CopyString(q.Status)
+	q.Currency = utils.CopyString(q.Currency)
+	q.Since = utils.CopyString(q.Since)
+	q.Until = utils.CopyString(q.Until)
+	result, err := h.svc.ListPayouts(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) ScreenPayouts(c *fiber.Ctx) error {
+	result, err := h.svc.ScreenPayouts(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) GetPayout(c *fiber.Ctx) error {
+	id, err := payoutID(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.GetPayout(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// PayoutAction returns a handler that holds, releases, cancels or retries a
+// payout.
+func (h *AdminHandler) PayoutAction(action string) fiber.Handler {
+	apply := h.svc.HoldPayout
+	switch action {
+	case models.PayoutActionRelease:
+		apply = h.svc.ReleasePayout
+	case models.PayoutActionCancel:
+		apply = h.svc.CancelPayout
+	case models.PayoutActionRetry:
+		apply = h.svc.RetryPayout
+	}
+	return func(c *fiber.Ctx) error {
+		id, err := payoutID(c)
+		if err != nil {
+			return err
+		}
+		actor, err := actorFrom(c)
+		if err != nil {
+			return err
+		}
+		var req dto.PayoutActionRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+			}
+		}
+		result, err := apply(requestContext(c), id, req, actor)
+		if err != nil {
+			return err
+		}
+		return c.JSON(result)
+	}
+}
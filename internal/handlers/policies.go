@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+)
+
+func policyID(c *fiber.Ctx) (int64, error) {
+	return int64Param(c, "id", "policy")
+}
+
+func policyMatchID(c *fiber.Ctx) (int64, error) {
+	return int64Param(c, "id", "policy match")
+}
+
+func (h *AdminHandler) ListPolicies(c *fiber.Ctx) error {
+	result, err := h.svc.ListPolicies(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) CreatePolicy(c *fiber.Ctx) error {
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.CreatePolicy(requestContext(c), req, actor)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *AdminHandler) GetPolicy(c *fiber.Ctx) error {
+	id, err := policyID(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.GetPolicy(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) UpdatePolicy(c *fiber.Ctx) error {
+	id, err := policyID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.UpdatePolicy(requestContext(c), id, req, actor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) EvaluatePolicies(c *fiber.Ctx) error {
+	result, err := h.svc.EvaluatePolicies(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) ListPolicyMatches(c *fiber.Ctx) error {
+	var q dto.PolicyMatchListQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	q.Status = utils.CopyString(q.Status)
+	result, err := h.svc.ListPolicyMatches(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// ReviewPolicyMatch returns a handler that approves or dismisses a pending
+// suspension recommendation.
+func (h *AdminHandler) ReviewPolicyMatch(approve bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := policyMatchID(c)
+		if err != nil {
+			return err
+		}
+		actor, err := actorFrom(c)
+		if err != nil {
+			return err
+		}
+		var req dto.ReviewPolicyMatchRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+			}
+		}
+		review := h.svc.DismissPolicyMatch
+		if approve {
+			review = h.svc.ApprovePolicyMatch
+		}
+		result, err := review(requestContext(c), id, req, actor)
+		if err != nil {
+			return err
+		}
+		return c.JSON(result)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+)
+
+func ruleID(c *fiber.Ctx) (int64, error) {
+	return int64Param(c, "id", "rule")
+}
+
+func (h *AdminHandler) ListRules(c *fiber.Ctx) error {
+	result, err := h.svc.ListRules(requestContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) CreateRule(c *fiber.Ctx) error {
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.CreateRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.CreateRule(requestContext(c), req, actor)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *AdminHandler) GetRule(c *fiber.Ctx) error {
+	id, err := ruleID(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.GetRule(requestContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) ReviseRule(c *fiber.Ctx) error {
+	id, err := ruleID(c)
+	if err != nil {
+		return err
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return err
+	}
+	var req dto.ReviseRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.ReviseRule(requestContext(c), id, req.Expression, actor)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// SetRuleEnabled returns a handler that enables or disables a rule.
+func (h *AdminHandler) SetRuleEnabled(enabled bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := ruleID(c)
+		if err != nil {
+			return err
+		}
+		actor, err := actorFrom(c)
+		if err != nil {
+			return err
+		}
+		result, err := h.svc.SetRuleEnabled(requestContext(c), id, enabled, actor)
+		if err != nil {
+			return err
+		}
+		return c.JSON(result)
+	}
+}
+
+func (h *AdminHandler) DryRunExpression(c *fiber.Ctx) error {
+	var req dto.DryRunRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.DryRunExpression(requestContext(c), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) DryRunRule(c *fiber.Ctx) error {
+	id, err := ruleID(c)
+	if err != nil {
+		return err
+	}
+	var req dto.DryRunRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.DryRunRule(requestContext(c), id, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) EvaluateRules(c *fiber.Ctx) error {
+	var req dto.RuleRangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.EvaluateRules(requestContext(c), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) ListRuleFlags(c *fiber.Ctx) error {
+	var q dto.FlagListQuery
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	result, err := h.svc.ListRuleFlags(requestContext(c), q)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) BacktestExpression(c *fiber.Ctx) error {
+	var req dto.BacktestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.BacktestExpression(requestContext(c), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) BacktestRule(c *fiber.Ctx) error {
+	id, err := ruleID(c)
+	if err != nil {
+		return err
+	}
+	var req dto.BacktestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	result, err := h.svc.BacktestRule(requestContext(c), id, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,148 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc probes a single dependency, returning an error when it is unhealthy.
+type CheckFunc func(ctx context.Context) error
+
+// Dependency is something the service needs in order to serve traffic.
+type Dependency struct {
+	Name     string
+	Critical bool
+	Check    CheckFunc
+}
+
+// Result is the outcome of checking one dependency.
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report aggregates dependency results. Ready is false when any critical
+// dependency is down.
+type Report struct {
+	Ready        bool     `json:"ready"`
+	Dependencies []Result `json:"dependencies"`
+}
+
+// Checker runs dependency checks concurrently, each bounded by a timeout.
+type Checker struct {
+	deps    []Dependency
+	timeout time.Duration
+}
+
+// NewChecker creates a Checker for deps. Dependencies named in critical are
+// marked critical regardless of how they were declared.
+func NewChecker(timeout time.Duration, critical []string, deps ...Dependency) *Checker {
+	isCritical := make(map[string]bool, len(critical))
+	for _, name := range critical {
+		isCritical[strings.TrimSpace(name)] = true
+	}
+	for i := range deps {
+		if isCritical[deps[i].Name] {
+			deps[i].Critical = true
+		}
+	}
+	return &Checker{deps: deps, timeout: timeout}
+}
+
+// Check probes every dependency and reports their status.
+func (c *Checker) Check(ctx context.Context) Report {
+	results := make([]Result, len(c.deps))
+	var wg sync.WaitGroup
+	for i, dep := range c.deps {
+		wg.Add(1)
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := dep.Check(checkCtx)
+			res := Result{
+				Name:      dep.Name,
+				Status:    StatusUp,
+				Critical:  dep.Critical,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				res.Status = StatusDown
+				res.Error = err.Error()
+			}
+			results[i] = res
+		}(i, dep)
+	}
+	wg.Wait()
+
+	report := Report{Ready: true, Dependencies: results}
+	for _, res := range results {
+		if res.Critical && res.Status != StatusUp {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// HTTPCheck expects GET url to answer with a 2xx status.
+func HTTPCheck(client *http.Client, url string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// RedisCheck sends a PING to the Redis server at addr and expects PONG.
+func RedisCheck(addr string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+
+		if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+			return err
+		}
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		// "-NOAUTH" still proves the server is up and answering.
+		if reply := strings.TrimSpace(line); reply != "+PONG" && !strings.HasPrefix(reply, "-NOAUTH") {
+			return fmt.Errorf("unexpected reply %q", reply)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+// Package jobs runs the service's periodic background work.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Every runs fn each interval until ctx is cancelled, starting after the
+// first interval. Failures are logged and retried on the next tick; runs
+// never overlap.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		if err := fn(ctx); err != nil {
+			slog.ErrorContext(ctx, "background job failed", "job", name, "error", err)
+			continue
+		}
+		slog.DebugContext(ctx, "background job finished", "job", name, "duration_ms", time.Since(start).Milliseconds())
+	}
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// sensitiveKeys are attribute keys whose values are always masked.
+var sensitiveKeys = map[string]bool{
+	"email":          true,
+	"customer_email": true,
+	"name":           true,
+	"customer_name":  true,
+	"business_name":  true,
+	"account_name":   true,
+	"account_number": true,
+	"bank_account":   true,
+	"card_number":    true,
+}
+
+// identifierKeys hold generated identifiers that may look like account
+// numbers but are safe to log.
+var identifierKeys = map[string]bool{
+	"request_id": true,
+	"trace_id":   true,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// Runs of 10 or more digits look like account or card numbers.
+	accountPattern = regexp.MustCompile(`\b\d{10,19}\b`)
+)
+
+// Redact is a slog ReplaceAttr function masking personal data: values of
+// sensitiveKeys, and email addresses and account numbers inside any string.
+func Redact(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	if sensitiveKeys[key] {
+		v := a.Value.String()
+		if emailPattern.MatchString(v) {
+			return slog.String(a.Key, RedactString(v))
+		}
+		return slog.String(a.Key, mask(v))
+	}
+	if identifierKeys[key] {
+		return a
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
+
+// RedactString masks email addresses and account numbers found in s.
+func RedactString(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		at := strings.LastIndex(email, "@")
+		return mask(email[:at]) + email[at:]
+	})
+	return accountPattern.ReplaceAllStringFunc(s, func(digits string) string {
+		return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+	})
+}
+
+// mask keeps the first character of s and hides the rest.
+func mask(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	return string(r[0]) + strings.Repeat("*", len(r)-1)
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want slog.Value
+	}{
+		{"sensitive key with an email", slog.String("customer_email", "jane.doe@example.com"), slog.StringValue("j*******@example.com")},
+		{"sensitive keys ignore case", slog.String("Email", "jane@example.com"), slog.StringValue("j***@example.com")},
+		{"name", slog.String("name", "Jane Doe"), slog.StringValue("J*******")},
+		{"business name", slog.String("business_name", "Acme Ltd"), slog.StringValue("A*******")},
+		{"email in a message", slog.String("msg", "refund requested by jane@example.com"), slog.StringValue("refund requested by j***@example.com")},
+		{"account number in a message", slog.String("detail", "payout to 0123456789 failed"), slog.StringValue("payout to ******6789 failed")},
+		{"card number keeps its last 4 digits", slog.String("detail", "card 4111111111111111 declined"), slog.StringValue("card ************1111 declined")},
+		{"digits in an error", slog.Any("error", errors.New("account 01234567890123456789 and 0123456789 for jane@example.com")),
+			slog.StringValue("account 01234567890123456789 and ******6789 for j***@example.com")},
+		{"short digit runs", slog.String("detail", "order 123456789"), slog.StringValue("order 123456789")},
+		{"request ID", slog.String("request_id", "12345678901234"), slog.StringValue("12345678901234")},
+		{"trace ID", slog.String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"), slog.StringValue("4bf92f3577b34da6a3ce929d0e0e4736")},
+		{"int", slog.Int64("transaction_id", 1234567890123), slog.Int64Value(1234567890123)},
+		{"duration", slog.Duration("elapsed", 1500*time.Millisecond), slog.DurationValue(1500 * time.Millisecond)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(nil, tt.attr)
+			if got.Key != tt.attr.Key || !got.Value.Equal(tt.want) {
+				t.Errorf("Redact(%v) = %v, want %s=%v", tt.attr, got, tt.attr.Key, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InstrumentTransport wraps next so every request records downstream latency
+// and result status under the given service label.
+func InstrumentTransport(service string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		downstreamDuration.WithLabelValues(service, req.Method).Observe(time.Since(start).Seconds())
+
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		downstreamRequests.WithLabelValues(service, req.Method, status).Inc()
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var (
+	dbMaxOpenDesc = prometheus.NewDesc("admin_db_max_open_connections",
+		"Maximum number of open connections to the database.", nil, nil)
+	dbOpenDesc = prometheus.NewDesc("admin_db_open_connections",
+		"Established connections, both in use and idle.", nil, nil)
+	dbInUseDesc = prometheus.NewDesc("admin_db_in_use_connections",
+		"Connections currently in use.", nil, nil)
+	dbIdleDesc = prometheus.NewDesc("admin_db_idle_connections",
+		"Idle connections.", nil, nil)
+	dbWaitCountDesc = prometheus.NewDesc("admin_db_wait_count_total",
+		"Connections waited for.", nil, nil)
+	dbWaitDurationDesc = prometheus.NewDesc("admin_db_wait_duration_seconds_total",
+		"Time spent waiting for a connection.", nil, nil)
+	dbMaxIdleClosedDesc = prometheus.NewDesc("admin_db_max_idle_closed_total",
+		"Connections closed due to SetMaxIdleConns.", nil, nil)
+	dbMaxLifetimeClosedDesc = prometheus.NewDesc("admin_db_max_lifetime_closed_total",
+		"Connections closed due to SetConnMaxLifetime.", nil, nil)
+)
+
+// dbStatsCollector reads sql.DBStats at scrape time.
+type dbStatsCollector struct {
+	stats func() sql.DBStats
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbMaxOpenDesc
+	ch <- dbOpenDesc
+	ch <- dbInUseDesc
+	ch <- dbIdleDesc
+	ch <- dbWaitCountDesc
+	ch <- dbWaitDurationDesc
+	ch <- dbMaxIdleClosedDesc
+	ch <- dbMaxLifetimeClosedDesc
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(dbMaxOpenDesc, prometheus.GaugeValue, float64(s.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(dbOpenDesc, prometheus.GaugeValue, float64(s.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(dbInUseDesc, prometheus.GaugeValue, float64(s.InUse))
+	ch <- prometheus.MustNewConstMetric(dbIdleDesc, prometheus.GaugeValue, float64(s.Idle))
+	ch <- prometheus.MustNewConstMetric(dbWaitCountDesc, prometheus.CounterValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(dbWaitDurationDesc, prometheus.CounterValue, s.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(dbMaxIdleClosedDesc, prometheus.CounterValue, float64(s.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(dbMaxLifetimeClosedDesc, prometheus.CounterValue, float64(s.MaxLifetimeClosed))
+}
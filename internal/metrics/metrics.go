@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric exported by the admin service.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_http_requests_total",
+		Help: "HTTP requests handled, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "admin_http_request_duration_seconds",
+		Help:    "HTTP request latency, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	downstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_downstream_requests_total",
+		Help: "Requests made to other services, by service, method and result status (\"error\" for transport failures).",
+	}, []string{"service", "method", "status"})
+
+	downstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "admin_downstream_request_duration_seconds",
+		Help:    "Latency of requests made to other services.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	// KYCDecisions counts KYC reviews by decision (approved, rejected, enabled).
+	KYCDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_kyc_decisions_total",
+		Help: "KYC decisions made by admins, by decision.",
+	}, []string{"decision"})
+
+	// MerchantStatusChanges counts merchant status changes (active, suspended).
+	MerchantStatusChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_merchant_status_changes_total",
+		Help: "Merchant status changes made by admins, by new status.",
+	}, []string{"status"})
+
+	// FraudDecisions counts fraud review decisions (approved, declined, escalated).
+	FraudDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_fraud_decisions_total",
+		Help: "Fraud review decisions made by admins, by decision.",
+	}, []string{"decision"})
+
+	// DeprecatedRequests counts requests to deprecated routes, to tell when
+	// clients have migrated off them.
+	DeprecatedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_deprecated_requests_total",
+		Help: "Requests to deprecated routes, by route.",
+	}, []string{"route"})
+
+	// FraudRuleMatches counts transactions matched by fraud rule evaluations.
+	FraudRuleMatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_fraud_rule_matches_total",
+		Help: "Transactions matched by fraud rule evaluations, by rule.",
+	}, []string{"rule"})
+
+	// BlocklistChecks counts blocklist lookups by result.
+	BlocklistChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_blocklist_checks_total",
+		Help: "Blocklist lookups, by result (blocked or clear).",
+	}, []string{"result"})
+
+	// BlocklistEntries reports the active entries in the cached blocklist.
+	BlocklistEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "admin_blocklist_entries",
+		Help: "Active blocklist entries in the lookup cache.",
+	})
+
+	// MerchantRiskLevels reports how many merchants are at each risk level
+	// as of the last recalculation.
+	MerchantRiskLevels = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "admin_merchant_risk_levels",
+		Help: "Merchants at each risk level (low, medium or high) as of the last recalculation.",
+	}, []string{"level"})
+
+	// MerchantAlerts counts merchant alerts raised by anomaly detection, by type.
+	MerchantAlerts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_merchant_alerts_total",
+		Help: "Merchant alerts raised by anomaly detection, by type.",
+	}, []string{"type"})
+
+	// SuspensionPolicyMatches counts merchants newly matched by suspension
+	// policies, by the match status: suspended, pending or dry_run.
+	SuspensionPolicyMatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_suspension_policy_matches_total",
+		Help: "Merchants newly matched by suspension policies, by match status.",
+	}, []string{"status"})
+
+	// PayoutActions counts holds, releases, cancellations and retries of
+	// payouts, by action.
+	PayoutActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_payout_actions_total",
+		Help: "Payouts held, released, cancelled or retried, by action.",
+	}, []string{"action"})
+
+	// SettlementHoldChanges counts settlement holds set on and released from
+	// merchants, by hold type and change: set or released.
+	SettlementHoldChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admin_settlement_hold_changes_total",
+		Help: "Merchant settlement holds set or released, by type and change.",
+	}, []string{"type", "change"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		downstreamRequests, downstreamDuration,
+		KYCDecisions, MerchantStatusChanges, FraudDecisions, DeprecatedRequests, FraudRuleMatches,
+		BlocklistChecks, BlocklistEntries, MerchantRiskLevels, MerchantAlerts,
+		SuspensionPolicyMatches, PayoutActions, SettlementHoldChanges,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware records request counts and latency per route template, so
+// /admin/merchants/1/approve and /admin/merchants/2/approve share a series.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			// Render the error now so the recorded status is the one sent.
+			if herr := c.App().Config().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// Fiber reuses the request buffers, so label values must be copied.
+		method := utils.CopyString(c.Method())
+		route := utils.CopyString(c.Route().Path)
+		code := c.Response().StatusCode()
+		if code == fiber.StatusNotFound && route == "/" {
+			// Only the global middleware matched; don't label by raw path.
+			route = "unmatched"
+		}
+		status := strconv.Itoa(code)
+		httpRequests.WithLabelValues(method, route, status).Inc()
+		httpDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// RegisterDBStats exports connection pool statistics read from stats.
+func RegisterDBStats(stats func() sql.DBStats) {
+	Registry.MustRegister(&dbStatsCollector{stats: stats})
+}
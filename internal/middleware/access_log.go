@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/logging"
+)
+
+// ActorHeader identifies the admin user on whose behalf the portal calls us.
+const ActorHeader = "X-Admin-ID"
+
+// AccessLog attaches the request ID and actor to the request context so every
+// log line written while handling the request carries them, then logs one
+// line per request once it completes. It must run after RequestID.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		method := utils.CopyString(c.Method())
+		path := utils.CopyString(c.Path())
+
+		args := []any{"request_id", GetRequestID(c)}
+		if actor := c.Get(ActorHeader); actor != "" {
+			args = append(args, "actor", utils.CopyString(actor))
+		}
+		ctx := logging.With(c.UserContext(), args...)
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		switch {
+		case err != nil || status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		attrs := []any{
+			"method", method,
+			"path", path,
+			"route", utils.CopyString(c.Route().Path),
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"ip", c.IP(),
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+		slog.Log(ctx, level, "request completed", attrs...)
+		return err
+	}
+}
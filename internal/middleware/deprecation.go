@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/metrics"
+)
+
+// Deprecation marks responses from a deprecated route with the Deprecation
+// header (RFC 9745), the Sunset header (RFC 8594) when a removal date is set,
+// and a Link to the route replacing it. successor maps the request path to
+// the replacement path.
+func Deprecation(deprecatedAt, sunset time.Time, successor func(path string) string) fiber.Handler {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	var sunsetHeader string
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", deprecation)
+		if sunsetHeader != "" {
+			c.Set("Sunset", sunsetHeader)
+		}
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, successor(c.Path())))
+		metrics.DeprecatedRequests.WithLabelValues(c.Route().Path).Inc()
+		return c.Next()
+	}
+}
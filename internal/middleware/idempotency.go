@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client supplied key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotencyReplayedHeader is set on responses served from a stored result.
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyRecord is a request made with an idempotency key and, once it
+// has completed, its response.
+type IdempotencyRecord struct {
+	// Key is the client supplied key scoped to the admin and route.
+	Key         string
+	RequestHash string
+	// StatusCode is zero while the request is still running.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// Completed reports whether the record holds a response to replay.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyStore persists idempotency records. A request reserves its key
+// before it runs and then completes or releases it.
+type IdempotencyStore interface {
+	// Reserve claims rec.Key for lease, returning nil if it was free or its
+	// record had expired. Otherwise it returns the record holding the key,
+	// which may still be pending.
+	Reserve(ctx context.Context, rec *IdempotencyRecord, lease time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the response of a reserved request until ttl has
+	// elapsed since rec.CreatedAt.
+	Complete(ctx context.Context, rec *IdempotencyRecord, ttl time.Duration) error
+	// Release deletes a pending reservation so the key can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// idempotencyLease bounds how long a reservation outlives a replica that
+// dies while running the request.
+const idempotencyLease = 5 * time.Minute
+
+// Idempotency replays the stored response for requests repeating an Idempotency-Key
+// and rejects reuse of a key with a different request. The key is reserved in
+// store before the request runs, so a duplicate arriving at any replica while
+// it runs is rejected. Keys are scoped to the admin in the X-Admin-ID header
+// and the route, so two admins, or one admin on two endpoints, never share a
+// key. Safe methods and requests without the header pass through untouched.
+func Idempotency(store IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if fiber.IsMethodSafe(c.Method()) {
+			return c.Next()
+		}
+		key := utils.CopyString(c.Get(IdempotencyKeyHeader))
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return fiber.NewError(fiber.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+		}
+
+		rec := &IdempotencyRecord{Key: scopedKey(c, key), RequestHash: requestHash(c), CreatedAt: time.Now().UTC()}
+		ctx := c.UserContext()
+
+		existing, err := store.Reserve(ctx, rec, idempotencyLease)
+		if err != nil {
+			// Running the request without the reservation could repeat it.
+			slog.ErrorContext(ctx, "failed to reserve Idempotency-Key", "error", err)
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Idempotency-Key could not be checked, retry later")
+		}
+		if existing != nil {
+			if !existing.Completed() {
+				return fiber.NewError(fiber.StatusConflict, "a request with this Idempotency-Key is already in progress")
+			}
+			if existing.RequestHash != rec.RequestHash {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+			}
+			c.Set(IdempotencyReplayedHeader, "true")
+			if existing.ContentType != "" {
+				c.Set(fiber.HeaderContentType, existing.ContentType)
+			}
+			return c.Status(existing.StatusCode).Send(existing.Body)
+		}
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError {
+			// Failures are left retryable.
+			if releaseErr := store.Release(ctx, rec.Key); releaseErr != nil {
+				slog.WarnContext(ctx, "failed to release Idempotency-Key", "error", releaseErr)
+			}
+			return err
+		}
+		rec.StatusCode = status
+		rec.ContentType = string(c.Response().Header.ContentType())
+		rec.Body = utils.CopyBytes(c.Response().Body())
+		if err := store.Complete(ctx, rec, ttl); err != nil {
+			// The change was made, so the client still gets its response.
+			slog.WarnContext(ctx, "failed to store Idempotency-Key response", "error", err)
+		}
+		return nil
+	}
+}
+
+// scopedKey derives the stored key from the client supplied key, the admin
+// making the request and the route it was sent to.
+func scopedKey(c *fiber.Ctx, key string) string {
+	h := sha256.New()
+	h.Write([]byte(c.Get(ActorHeader)))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Path()))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestHash fingerprints the method, path and body of a request.
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(c.OriginalURL()))
+	h.Write([]byte{0})
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryIdempotencyStore keeps idempotency records in process memory.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]memoryIdempotencyEntry)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(_ context.Context, rec *IdempotencyRecord, lease time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if entry, ok := s.records[rec.Key]; ok && now.Before(entry.expiresAt) {
+		existing := entry.record
+		return &existing, nil
+	}
+	pending := *rec
+	pending.StatusCode, pending.ContentType, pending.Body = 0, "", nil
+	s.records[rec.Key] = memoryIdempotencyEntry{record: pending, expiresAt: now.Add(lease)}
+
+	// Expired keys are otherwise only replaced, so sweep occasionally.
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.records {
+			if now.After(entry.expiresAt) {
+				delete(s.records, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, rec *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.records[rec.Key]; ok && !entry.record.Completed() {
+		s.records[rec.Key] = memoryIdempotencyEntry{record: *rec, expiresAt: rec.CreatedAt.Add(ttl)}
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.records[key]; ok && !entry.record.Completed() {
+		delete(s.records, key)
+	}
+	return nil
+}
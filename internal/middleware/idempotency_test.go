@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// idempotentApp serves POST /charge behind the Idempotency middleware,
+// counting how many times the handler runs.
+func idempotentApp(store IdempotencyStore, handler fiber.Handler) (*fiber.App, *atomic.Int32) {
+	var calls atomic.Int32
+	app := fiber.New()
+	app.Post("/charge", Idempotency(store, time.Hour), func(c *fiber.Ctx) error {
+		calls.Add(1)
+		return handler(c)
+	})
+	return app, &calls
+}
+
+func post(t *testing.T, app *fiber.App, key, admin, body string) (*http.Response, string) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodPost, "/charge", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	if admin != "" {
+		req.Header.Set(ActorHeader, admin)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(b)
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	var n atomic.Int32
+	app, calls := idempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"charge": n.Add(1)})
+	})
+
+	first, body := post(t, app, "k1", "admin-1", `{"amount":100}`)
+	if first.StatusCode != fiber.StatusCreated || body != `{"charge":1}` {
+		t.Fatalf("first response %d %s", first.StatusCode, body)
+	}
+	replay, replayed := post(t, app, "k1", "admin-1", `{"amount":100}`)
+	if replay.StatusCode != fiber.StatusCreated || replayed != body || replay.Header.Get(IdempotencyReplayedHeader) != "true" ||
+		replay.Header.Get(fiber.HeaderContentType) != fiber.MIMEApplicationJSON {
+		t.Errorf("replay %d %s %v", replay.StatusCode, replayed, replay.Header)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+
+	// the same key from another admin is a different request
+	if resp, body := post(t, app, "k1", "admin-2", `{"amount":100}`); resp.StatusCode != fiber.StatusCreated || body != `{"charge":2}` {
+		t.Errorf("other admin: %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestIdempotencyRejectsReuseForDifferentRequest(t *testing.T) {
+	app, calls := idempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	post(t, app, "k1", "admin-1", `{"amount":100}`)
+	resp, _ := post(t, app, "k1", "admin-1", `{"amount":999}`)
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Errorf("got %d, want 422", resp.StatusCode)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsConcurrentDuplicate(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	app, _ := idempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.SendString("ok")
+	})
+
+	done := make(chan int)
+	go func() {
+		resp, err := app.Test(func() *http.Request {
+			req := httptest.NewRequest(fiber.MethodPost, "/charge", strings.NewReader(`{}`))
+			req.Header.Set(IdempotencyKeyHeader, "k1")
+			req.Header.Set(ActorHeader, "admin-1")
+			return req
+		}(), -1)
+		if err != nil {
+			t.Error(err)
+			done <- 0
+			return
+		}
+		done <- resp.StatusCode
+	}()
+	<-started
+
+	if resp, _ := post(t, app, "k1", "admin-1", `{}`); resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("concurrent duplicate: got %d, want 409", resp.StatusCode)
+	}
+	close(release)
+	if status := <-done; status != fiber.StatusOK {
+		t.Errorf("first request: got %d, want 200", status)
+	}
+}
+
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	app, calls := idempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		if fail.Load() {
+			return c.Status(fiber.StatusBadGateway).SendString("downstream failed")
+		}
+		return c.SendString("ok")
+	})
+
+	if resp, _ := post(t, app, "k1", "admin-1", `{}`); resp.StatusCode != fiber.StatusBadGateway {
+		t.Fatalf("got %d, want 502", resp.StatusCode)
+	}
+	fail.Store(false)
+	resp, body := post(t, app, "k1", "admin-1", `{}`)
+	if resp.StatusCode != fiber.StatusOK || body != "ok" || resp.Header.Get(IdempotencyReplayedHeader) != "" {
+		t.Errorf("retry after 5xx: %d %s", resp.StatusCode, body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("handler ran %d times, want 2", calls.Load())
+	}
+}
+
+func TestIdempotencyReleasesKeyWhenHandlerFails(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	app, calls := idempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		if fail.Load() {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+		}
+		return c.SendString("ok")
+	})
+
+	if resp, _ := post(t, app, "k1", "admin-1", `{}`); resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("got %d, want 400", resp.StatusCode)
+	}
+	fail.Store(false)
+	if resp, body := post(t, app, "k1", "admin-1", `{}`); resp.StatusCode != fiber.StatusOK || body != "ok" {
+		t.Errorf("retry after an error: %d %s", resp.StatusCode, body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("handler ran %d times, want 2", calls.Load())
+	}
+}
+
+func TestMemoryIdempotencyStoreReservesOnce(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryIdempotencyStore()
+	rec := &IdempotencyRecord{Key: "k1", RequestHash: "h1", CreatedAt: time.Now()}
+
+	var reserved atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			existing, err := store.Reserve(ctx, rec, time.Minute)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if existing == nil {
+				reserved.Add(1)
+			} else if existing.Completed() {
+				t.Errorf("pending key reported complete: %+v", existing)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved.Load() != 1 {
+		t.Fatalf("%d goroutines reserved the key, want 1", reserved.Load())
+	}
+
+	done := *rec
+	done.StatusCode, done.Body = fiber.StatusCreated, []byte("created")
+	if err := store.Complete(ctx, &done, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Release(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if existing, err := store.Reserve(ctx, rec, time.Minute); err != nil || existing == nil || existing.StatusCode != fiber.StatusCreated {
+		t.Errorf("completed key: %+v, %v", existing, err)
+	}
+}
+
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) Reserve(context.Context, *IdempotencyRecord, time.Duration) (*IdempotencyRecord, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func (failingIdempotencyStore) Complete(context.Context, *IdempotencyRecord, time.Duration) error {
+	return errors.New("database unavailable")
+}
+
+func (failingIdempotencyStore) Release(context.Context, string) error {
+	return errors.New("database unavailable")
+}
+
+func TestIdempotencyUnavailableStore(t *testing.T) {
+	app, calls := idempotentApp(failingIdempotencyStore{}, func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	if resp, _ := post(t, app, "k1", "admin-1", `{}`); resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503", resp.StatusCode)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("handler ran %d times, want 0", calls.Load())
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+// RateLimit allows each client a number of requests per window, both read
+// from store on every request so changes apply immediately. Clients are
+// identified by their IP address rather than the X-Admin-ID header, which
+// they could change on every request.
+func RateLimit(store *settings.Store) fiber.Handler {
+	var (
+		mu      sync.Mutex
+		windows = make(map[string]*rateWindow)
+	)
+
+	return func(c *fiber.Ctx) error {
+		limit := store.Get().RateLimit
+		if limit.Requests <= 0 {
+			return c.Next()
+		}
+
+		client := utils.CopyString(c.IP())
+		now := time.Now()
+
+		mu.Lock()
+		w, ok := windows[client]
+		if !ok || now.After(w.resetAt) {
+			w = &rateWindow{resetAt: now.Add(limit.Window.Std())}
+			windows[client] = w
+			if len(windows) > 10000 {
+				for k, other := range windows {
+					if now.After(other.resetAt) {
+						delete(windows, k)
+					}
+				}
+			}
+		}
+		w.count++
+		count, resetAt := w.count, w.resetAt
+		mu.Unlock()
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(max(limit.Requests-count, 0)))
+		if count > limit.Requests {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(resetAt).Seconds())+1))
+			return fiber.NewError(fiber.StatusTooManyRequests, "rate limit exceeded")
+		}
+		return c.Next()
+	}
+}
+
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+func TestRateLimitKeysOnClientIP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	if err := os.WriteFile(path, []byte("rate_limit:\n  requests: 2\n  window: 1m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := settings.NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// app.Test always connects from the same address, so clients are told
+	// apart by the proxy header
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Get("/stats", RateLimit(store), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	get := func(ip, admin string) int {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodGet, "/stats", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		req.Header.Set(ActorHeader, admin)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	// a new X-Admin-ID on every request does not reset the limit
+	for i, admin := range []string{"admin-1", "admin-2"} {
+		if status := get("10.0.0.1", admin); status != fiber.StatusOK {
+			t.Fatalf("request %d: got %d, want 200", i+1, status)
+		}
+	}
+	if status := get("10.0.0.1", "admin-3"); status != fiber.StatusTooManyRequests {
+		t.Errorf("third request: got %d, want 429", status)
+	}
+	if status := get("10.0.0.2", "admin-1"); status != fiber.StatusOK {
+		t.Errorf("another client: got %d, want 200", status)
+	}
+}
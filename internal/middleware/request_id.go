@@ -7,6 +7,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+const requestIDLocal = "request_id"
+
 func RequestID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		requestID := c.Get("X-Request-ID")
@@ -14,6 +16,13 @@ func RequestID() fiber.Handler {
 			requestID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
 		c.Set("X-Request-ID", requestID)
+		c.Locals(requestIDLocal, requestID)
 		return c.Next()
 	}
 }
+
+// GetRequestID returns the ID assigned to the current request by RequestID.
+func GetRequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocal).(string)
+	return id
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id          BIGSERIAL PRIMARY KEY,
+    actor       TEXT        NOT NULL,
+    action      TEXT        NOT NULL,
+    target_type TEXT        NOT NULL,
+    target_id   TEXT        NOT NULL,
+    details     JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    request_id  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log (target_type, target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log (created_at DESC);
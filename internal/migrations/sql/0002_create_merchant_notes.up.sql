@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS merchant_notes (
+    id          BIGSERIAL PRIMARY KEY,
+    merchant_id INTEGER     NOT NULL,
+    author      TEXT        NOT NULL,
+    body        TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_notes_merchant ON merchant_notes (merchant_id, created_at DESC);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS admin_approvals (
+    id           BIGSERIAL PRIMARY KEY,
+    action       TEXT        NOT NULL,
+    target_type  TEXT        NOT NULL,
+    target_id    TEXT        NOT NULL,
+    payload      JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    status       TEXT        NOT NULL DEFAULT 'pending'
+                 CHECK (status IN ('pending', 'approved', 'rejected')),
+    requested_by TEXT        NOT NULL,
+    decided_by   TEXT,
+    reason       TEXT,
+    decided_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_approvals_status ON admin_approvals (status, created_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS fraud_reviews (
+    id             BIGSERIAL PRIMARY KEY,
+    transaction_id INTEGER     NOT NULL,
+    decision       TEXT        NOT NULL
+                   CHECK (decision IN ('approved', 'declined', 'escalated')),
+    reason         TEXT        NOT NULL,
+    reviewer       TEXT        NOT NULL,
+    request_id     TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_reviews_transaction ON fraud_reviews (transaction_id, created_at DESC);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS fraud_case_attachments;
+DROP TABLE IF EXISTS fraud_case_comments;
+DROP TABLE IF EXISTS fraud_case_transactions;
+DROP TABLE IF EXISTS fraud_cases;
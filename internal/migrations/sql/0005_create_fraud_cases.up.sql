@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS fraud_cases (
+    id          BIGSERIAL PRIMARY KEY,
+    group_type  TEXT        NOT NULL
+                CHECK (group_type IN ('card_fingerprint', 'customer_email', 'merchant')),
+    group_key   TEXT        NOT NULL,
+    status      TEXT        NOT NULL DEFAULT 'open'
+                CHECK (status IN ('open', 'investigating', 'resolved')),
+    assignee    TEXT,
+    resolution  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+-- At most one unresolved case per group, so new flags join the existing case.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fraud_cases_open_group ON fraud_cases (group_type, group_key) WHERE status <> 'resolved';
+CREATE INDEX IF NOT EXISTS idx_fraud_cases_status ON fraud_cases (status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS fraud_case_transactions (
+    transaction_id   INTEGER PRIMARY KEY,
+    case_id          BIGINT      NOT NULL REFERENCES fraud_cases (id) ON DELETE CASCADE,
+    merchant_id      INTEGER     NOT NULL,
+    amount           BIGINT      NOT NULL,
+    currency         TEXT        NOT NULL,
+    customer_email   TEXT        NOT NULL DEFAULT '',
+    card_fingerprint TEXT        NOT NULL DEFAULT '',
+    flagged_at       TIMESTAMPTZ NOT NULL,
+    added_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_case_transactions_case ON fraud_case_transactions (case_id);
+
+CREATE TABLE IF NOT EXISTS fraud_case_comments (
+    id         BIGSERIAL PRIMARY KEY,
+    case_id    BIGINT      NOT NULL REFERENCES fraud_cases (id) ON DELETE CASCADE,
+    author     TEXT        NOT NULL,
+    body       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_case_comments_case ON fraud_case_comments (case_id, created_at);
+
+CREATE TABLE IF NOT EXISTS fraud_case_attachments (
+    id           BIGSERIAL PRIMARY KEY,
+    case_id      BIGINT      NOT NULL REFERENCES fraud_cases (id) ON DELETE CASCADE,
+    filename     TEXT        NOT NULL,
+    content_type TEXT        NOT NULL,
+    size         BIGINT      NOT NULL,
+    uploaded_by  TEXT        NOT NULL,
+    data         BYTEA       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_case_attachments_case ON fraud_case_attachments (case_id, created_at);
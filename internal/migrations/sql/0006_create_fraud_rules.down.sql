@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fraud_rule_flags;
+DROP TABLE IF EXISTS fraud_rule_versions;
+DROP TABLE IF EXISTS fraud_rules;
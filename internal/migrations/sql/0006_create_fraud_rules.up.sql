@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS fraud_rules (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT        NOT NULL UNIQUE,
+    description TEXT        NOT NULL DEFAULT '',
+    enabled     BOOLEAN     NOT NULL DEFAULT FALSE,
+    version     INTEGER     NOT NULL DEFAULT 1,
+    created_by  TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Every revision of a rule's expression is kept, so flags can be traced to
+-- the exact version that raised them.
+CREATE TABLE IF NOT EXISTS fraud_rule_versions (
+    rule_id    BIGINT      NOT NULL REFERENCES fraud_rules (id) ON DELETE CASCADE,
+    version    INTEGER     NOT NULL,
+    expression TEXT        NOT NULL,
+    created_by TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rule_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS fraud_rule_flags (
+    id             BIGSERIAL PRIMARY KEY,
+    rule_id        BIGINT      NOT NULL REFERENCES fraud_rules (id) ON DELETE CASCADE,
+    rule_version   INTEGER     NOT NULL,
+    transaction_id INTEGER     NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (rule_id, rule_version, transaction_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_rule_flags_transaction ON fraud_rule_flags (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_fraud_rule_flags_created ON fraud_rule_flags (created_at DESC, id DESC);
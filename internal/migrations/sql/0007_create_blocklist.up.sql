@@ -0,0 +1,15 @@
+-- One row per blocked value. Expiring an entry keeps the row, and blocking
+-- the value again reuses it; the audit log keeps the history.
+CREATE TABLE IF NOT EXISTS blocklist_entries (
+    id         BIGSERIAL PRIMARY KEY,
+    type       TEXT        NOT NULL,
+    value      TEXT        NOT NULL,
+    reason     TEXT        NOT NULL,
+    created_by TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    UNIQUE (type, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocklist_entries_created ON blocklist_entries (created_at DESC, id DESC);
@@ -0,0 +1,11 @@
+-- The latest risk score of each merchant, replaced on every recalculation.
+-- factors holds the breakdown as [{name, value, weight, score}].
+CREATE TABLE IF NOT EXISTS merchant_risk_scores (
+    merchant_id INTEGER          PRIMARY KEY,
+    score       DOUBLE PRECISION NOT NULL,
+    level       TEXT             NOT NULL,
+    factors     JSONB            NOT NULL DEFAULT '[]',
+    computed_at TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_risk_scores_score ON merchant_risk_scores (score DESC);
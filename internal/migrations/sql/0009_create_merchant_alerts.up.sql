@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS merchant_alerts (
+    id              BIGSERIAL PRIMARY KEY,
+    merchant_id     INTEGER          NOT NULL,
+    type            TEXT             NOT NULL
+                    CHECK (type IN ('volume_spike', 'success_rate_drop')),
+    status          TEXT             NOT NULL DEFAULT 'open'
+                    CHECK (status IN ('open', 'acknowledged', 'resolved')),
+    observed        DOUBLE PRECISION NOT NULL,
+    expected        DOUBLE PRECISION NOT NULL,
+    deviation       DOUBLE PRECISION NOT NULL,
+    period_start    TIMESTAMPTZ      NOT NULL,
+    period_end      TIMESTAMPTZ      NOT NULL,
+    created_at      TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    acknowledged_by TEXT,
+    acknowledged_at TIMESTAMPTZ,
+    resolved_by     TEXT,
+    resolved_at     TIMESTAMPTZ,
+    resolution      TEXT
+);
+
+-- At most one unresolved alert per merchant and type, so a persisting
+-- anomaly does not flood the inbox.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_alerts_unresolved ON merchant_alerts (merchant_id, type) WHERE status <> 'resolved';
+CREATE INDEX IF NOT EXISTS idx_merchant_alerts_status ON merchant_alerts (status, created_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS suspension_policy_matches;
+DROP TABLE IF EXISTS suspension_policies;
+DROP TABLE IF EXISTS merchant_kyc_rejections;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS suspension_policies (
+    id               BIGSERIAL PRIMARY KEY,
+    name             TEXT             NOT NULL UNIQUE,
+    description      TEXT             NOT NULL DEFAULT '',
+    condition        TEXT             NOT NULL
+                     CHECK (condition IN ('chargeback_ratio', 'failed_ratio', 'refund_ratio', 'kyc_rejections')),
+    threshold        DOUBLE PRECISION NOT NULL,
+    window_days      INTEGER          NOT NULL,
+    min_transactions INTEGER          NOT NULL DEFAULT 0,
+    action           TEXT             NOT NULL CHECK (action IN ('suspend', 'recommend')),
+    dry_run          BOOLEAN          NOT NULL DEFAULT TRUE,
+    enabled          BOOLEAN          NOT NULL DEFAULT FALSE,
+    created_by       TEXT             NOT NULL,
+    created_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS suspension_policy_matches (
+    id          BIGSERIAL PRIMARY KEY,
+    policy_id   BIGINT           NOT NULL REFERENCES suspension_policies (id) ON DELETE CASCADE,
+    merchant_id INTEGER          NOT NULL,
+    value       DOUBLE PRECISION NOT NULL,
+    action      TEXT             NOT NULL,
+    status      TEXT             NOT NULL
+                CHECK (status IN ('suspended', 'pending', 'approved', 'dismissed', 'dry_run')),
+    reviewed_by TEXT,
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT,
+    created_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+-- At most one pending recommendation per policy and merchant.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suspension_policy_matches_pending ON suspension_policy_matches (policy_id, merchant_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_suspension_policy_matches_created ON suspension_policy_matches (created_at DESC, id DESC);
+
+-- KYC rejections made through the admin service, counted by the
+-- kyc_rejections policy condition.
+CREATE TABLE IF NOT EXISTS merchant_kyc_rejections (
+    id          BIGSERIAL PRIMARY KEY,
+    merchant_id INTEGER     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_kyc_rejections_merchant ON merchant_kyc_rejections (merchant_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payout_actions;
+DROP TABLE IF EXISTS payout_holds;
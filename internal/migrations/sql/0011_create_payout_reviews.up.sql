@@ -0,0 +1,21 @@
+-- Payouts in the manual review queue. A payout has a row while it is held,
+-- from the hold until an admin releases or cancels it.
+CREATE TABLE IF NOT EXISTS payout_holds (
+    payout_id  INTEGER     PRIMARY KEY,
+    rule       TEXT        NOT NULL CHECK (rule IN ('manual', 'amount_threshold', 'new_merchant')),
+    reason     TEXT        NOT NULL,
+    held_by    TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Every hold, release, cancellation and retry of a payout, with its reason.
+CREATE TABLE IF NOT EXISTS payout_actions (
+    id         BIGSERIAL PRIMARY KEY,
+    payout_id  INTEGER     NOT NULL,
+    action     TEXT        NOT NULL CHECK (action IN ('hold', 'release', 'cancel', 'retry')),
+    reason     TEXT        NOT NULL,
+    actor      TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_actions_payout ON payout_actions (payout_id, created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE payout_holds DROP CONSTRAINT IF EXISTS payout_holds_rule_check;
+UPDATE payout_holds SET rule = 'manual' WHERE rule = 'settlement_hold';
+ALTER TABLE payout_holds ADD CONSTRAINT payout_holds_rule_check
+    CHECK (rule IN ('manual', 'amount_threshold', 'new_merchant'));
+DROP TABLE IF EXISTS settlement_holds;
@@ -0,0 +1,28 @@
+-- Settlement holds keep back some or all of a merchant's money: a full hold
+-- stops their payouts, a rolling reserve keeps reserve_percent of each
+-- settlement for release_days days, and a fixed reserve keeps reserve_amount
+-- of currency. Ended holds are kept as history.
+CREATE TABLE IF NOT EXISTS settlement_holds (
+    id              BIGSERIAL PRIMARY KEY,
+    merchant_id     INTEGER          NOT NULL,
+    type            TEXT             NOT NULL CHECK (type IN ('full_hold', 'rolling_reserve', 'fixed_reserve')),
+    reserve_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    release_days    INTEGER          NOT NULL DEFAULT 0,
+    reserve_amount  BIGINT           NOT NULL DEFAULT 0,
+    currency        TEXT             NOT NULL DEFAULT '',
+    reason          TEXT             NOT NULL,
+    created_by      TEXT             NOT NULL,
+    created_at      TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    released_by     TEXT,
+    released_at     TIMESTAMPTZ,
+    release_reason  TEXT
+);
+
+-- At most one hold in effect per merchant.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlement_holds_active ON settlement_holds (merchant_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_settlement_holds_merchant ON settlement_holds (merchant_id, created_at DESC, id DESC);
+
+-- Payouts of merchants under a full hold are held for review.
+ALTER TABLE payout_holds DROP CONSTRAINT IF EXISTS payout_holds_rule_check;
+ALTER TABLE payout_holds ADD CONSTRAINT payout_holds_rule_check
+    CHECK (rule IN ('manual', 'amount_threshold', 'new_merchant', 'settlement_hold'));
@@ -0,0 +1,16 @@
+-- Requests made with an Idempotency-Key, replayed when the request is retried
+-- on any replica until the record expires. The key is the client's key
+-- scoped to the admin and route. A request reserves its key before it runs;
+-- status_code and body stay NULL until it completes, and the reservation
+-- expires early if the replica running it dies.
+CREATE TABLE IF NOT EXISTS admin_idempotency_keys (
+    key          TEXT        PRIMARY KEY,
+    request_hash TEXT        NOT NULL,
+    status_code  INTEGER,
+    content_type TEXT        NOT NULL DEFAULT '',
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_idempotency_keys_expires_at ON admin_idempotency_keys (expires_at);
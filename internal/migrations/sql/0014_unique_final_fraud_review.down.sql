@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_fraud_reviews_final;
@@ -0,0 +1,5 @@
+-- A transaction is approved or declined at most once. Recording a final
+-- decision inserts its row before the transaction service is called, so a
+-- concurrent final decision waits here and is then rejected.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fraud_reviews_final ON fraud_reviews (transaction_id)
+    WHERE decision IN ('approved', 'declined');
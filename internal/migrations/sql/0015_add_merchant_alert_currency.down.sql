@@ -0,0 +1 @@
+ALTER TABLE merchant_alerts DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,3 @@
+-- Volume spikes are detected per currency, so their alerts name it. Success
+-- rate alerts cover every currency and leave it empty.
+ALTER TABLE merchant_alerts ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT '';
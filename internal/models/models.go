@@ -1,3 +1,640 @@
 package models
 
-// TODO: define data models/entities for this microservice
+import "time"
+
+// Merchant is a merchant account together with its balance summary.
+type Merchant struct {
+	ID           int
+	Name         string
+	Email        string
+	BusinessName string
+	Status       string
+	KYCStatus    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	// TotalVolume is in minor currency units
+	TotalVolume int64
+	Currency    string
+	// Category and Country describe the business for fraud rules; merchant
+	// listings leave them empty
+	Category string
+	Country  string
+	// KYCVerifiedAt is when the merchant's KYC was completed, or nil when the
+	// merchant service has not recorded it; only loaded for risk scoring
+	KYCVerifiedAt *time.Time
+	// Risk is the merchant's latest risk score without its factors, or nil
+	// when it has not been scored
+	Risk *RiskScore
+}
+
+// MerchantFilter selects merchants to list.
+type MerchantFilter struct {
+	// RiskLevel and MinRiskScore only match merchants that have been scored
+	RiskLevel    string
+	MinRiskScore float64
+	// SortByRisk lists the riskiest merchants first and unscored ones last;
+	// otherwise merchants are listed newest first
+	SortByRisk bool
+	Limit      int
+}
+
+// Merchant risk levels, from lowest to highest.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// RiskFactor is one signal's contribution to a merchant's risk score.
+type RiskFactor struct {
+	Name string
+	// Value is the measured signal, such as a ratio or an age in days
+	Value float64
+	// Weight is the most the factor can add to the score and Score what it
+	// added
+	Weight float64
+	Score  float64
+}
+
+// RiskScore is a merchant's computed risk, from 0 to 100.
+type RiskScore struct {
+	MerchantID int
+	Score      float64
+	Level      string
+	Factors    []RiskFactor
+	ComputedAt time.Time
+}
+
+// ActivityQuery sets the windows, ending at Now, that merchant activity is
+// measured over.
+type ActivityQuery struct {
+	Now time.Time
+	// RatioWindow is counted for payment outcome ratios
+	RatioWindow time.Duration
+	// RecentWindow's volume is compared with the BaselineWindow before it
+	RecentWindow   time.Duration
+	BaselineWindow time.Duration
+}
+
+// MerchantActivity is what a merchant's risk score is computed from and
+// suspension policies are evaluated against.
+type MerchantActivity struct {
+	MerchantID    int
+	Status        string
+	KYCStatus     string
+	KYCVerifiedAt *time.Time
+	CreatedAt     time.Time
+	// Payments in the ratio window, in total and by outcome
+	Transactions int
+	Failed       int
+	Refunded     int
+	Chargebacks  int
+	// KYCRejections counts the merchant's KYC rejections in the ratio window
+	KYCRejections int
+	// Volumes holds the merchant's successful payments in each currency it
+	// took any in, ordered by currency
+	Volumes []CurrencyVolume
+}
+
+// CurrencyVolume is a merchant's successful payments in one currency, in its
+// minor units: Recent over the recent window and Baseline over the baseline
+// window before it.
+type CurrencyVolume struct {
+	Currency string
+	Recent   int64
+	Baseline int64
+}
+
+// Transaction is a customer payment to a merchant.
+type Transaction struct {
+	ID            int
+	Reference     string
+	MerchantID    int
+	MerchantName  string
+	CustomerEmail string
+	CustomerName  *string
+	// Amount is in minor currency units
+	Amount        int64
+	Currency      string
+	Status        string
+	PaymentMethod *string
+	CreatedAt     time.Time
+	// CardFingerprint, IPAddress, CardCountry and IPCountry are risk signals
+	// loaded for fraud rules; the transaction feed leaves them empty
+	CardFingerprint string
+	IPAddress       string
+	CardCountry     string
+	IPCountry       string
+}
+
+// Payout is a settlement of merchant funds to their bank account.
+type Payout struct {
+	ID           int
+	Reference    string
+	MerchantID   int
+	MerchantName string
+	// Amount is in minor currency units
+	Amount    int64
+	Currency  string
+	Status    string
+	CreatedAt time.Time
+	// MerchantCreatedAt is when the payout's merchant signed up
+	MerchantCreatedAt time.Time
+	// Hold is set while the payout is in the manual review queue
+	Hold *PayoutHold
+}
+
+// PayoutStatusPending is the status of a payout waiting to be sent.
+const PayoutStatusPending = "pending"
+
+// PayoutFilter selects payouts; zero fields match every payout.
+type PayoutFilter struct {
+	MerchantID int
+	Status     string
+	Currency   string
+	// MinAmount is in minor currency units
+	MinAmount int64
+	// Since and Until bound CreatedAt; Until is exclusive
+	Since time.Time
+	Until time.Time
+	// Held only matches payouts in the manual review queue
+	Held bool
+	// Unreviewed only matches payouts no one has held, released, cancelled
+	// or retried
+	Unreviewed bool
+	Limit      int
+}
+
+// Why a payout was placed in the manual review queue.
+const (
+	PayoutRuleManual          = "manual"
+	PayoutRuleAmountThreshold = "amount_threshold"
+	PayoutRuleNewMerchant     = "new_merchant"
+	PayoutRuleSettlementHold  = "settlement_hold"
+)
+
+// PayoutHold keeps a payout in the manual review queue until it is released
+// or cancelled.
+type PayoutHold struct {
+	PayoutID  int
+	Rule      string
+	Reason    string
+	HeldBy    string
+	CreatedAt time.Time
+}
+
+// Actions taken on payouts.
+const (
+	PayoutActionHold    = "hold"
+	PayoutActionRelease = "release"
+	PayoutActionCancel  = "cancel"
+	PayoutActionRetry   = "retry"
+)
+
+// PayoutAction is an action taken on a payout, by an admin or by the review
+// rules.
+type PayoutAction struct {
+	ID        int64
+	PayoutID  int
+	Action    string
+	Reason    string
+	Actor     string
+	CreatedAt time.Time
+}
+
+// Settlement hold types. A full hold stops a merchant's payouts, a rolling
+// reserve keeps a percentage of each settlement for a number of days, and a
+// fixed reserve keeps a set amount.
+const (
+	SettlementFullHold       = "full_hold"
+	SettlementRollingReserve = "rolling_reserve"
+	SettlementFixedReserve   = "fixed_reserve"
+)
+
+// SettlementHold keeps back some or all of a merchant's money until it is
+// released or replaced.
+type SettlementHold struct {
+	ID         int64
+	MerchantID int
+	Type       string
+	// ReservePercent and ReleaseDays are set for rolling reserves
+	ReservePercent float64
+	ReleaseDays    int
+	// ReserveAmount, in minor units of Currency, is set for fixed reserves
+	ReserveAmount int64
+	Currency      string
+	Reason        string
+	CreatedBy     string
+	CreatedAt     time.Time
+	// ReleasedAt is set once the hold no longer applies
+	ReleasedBy    string
+	ReleasedAt    *time.Time
+	ReleaseReason string
+}
+
+// SettlementHoldFilter selects settlement holds; zero fields match every hold.
+type SettlementHoldFilter struct {
+	MerchantID int
+	Type       string
+	// ActiveOnly leaves out released holds
+	ActiveOnly bool
+	Limit      int
+}
+
+// PlatformStats are platform-wide merchant and transaction totals.
+type PlatformStats struct {
+	TotalMerchants    int
+	ActiveMerchants   int
+	PendingKYC        int
+	TotalTransactions int
+	// TotalVolume and MonthlyVolume are in minor currency units
+	TotalVolume   int64
+	MonthlyVolume int64
+	// SuccessRate is a percentage, zero when there are no transactions
+	SuccessRate float64
+	GeneratedAt time.Time
+}
+
+// Fraud review decisions on transactions held for review. Approved
+// transactions are released, declined ones reversed, and escalated ones stay
+// held for a senior reviewer.
+const (
+	FraudDecisionApproved  = "approved"
+	FraudDecisionDeclined  = "declined"
+	FraudDecisionEscalated = "escalated"
+)
+
+// FraudDecision is a reviewer's decision on a transaction held for review.
+type FraudDecision struct {
+	ID            int64
+	TransactionID int
+	Decision      string
+	Reason        string
+	Reviewer      string
+	RequestID     string
+	CreatedAt     time.Time
+}
+
+// Final reports whether the decision resolves the review.
+func (d FraudDecision) Final() bool {
+	return d.Decision == FraudDecisionApproved || d.Decision == FraudDecisionDeclined
+}
+
+// AuditEntry records an action taken by an admin or by the service itself.
+type AuditEntry struct {
+	ID         int64
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	Details    map[string]interface{}
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+// Fraud case statuses.
+const (
+	CaseStatusOpen          = "open"
+	CaseStatusInvestigating = "investigating"
+	CaseStatusResolved      = "resolved"
+)
+
+// Fraud case groupings, from most to least specific.
+const (
+	CaseGroupCardFingerprint = "card_fingerprint"
+	CaseGroupCustomerEmail   = "customer_email"
+	CaseGroupMerchant        = "merchant"
+)
+
+// FraudCase groups related flagged transactions for investigation.
+type FraudCase struct {
+	ID int64
+	// GroupType and GroupKey identify what the case's transactions share,
+	// such as customer_email and the address
+	GroupType  string
+	GroupKey   string
+	Status     string
+	Assignee   string
+	Resolution string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ResolvedAt *time.Time
+	// TransactionCount and TotalAmount summarise the case's transactions;
+	// TotalAmount is in minor currency units
+	TransactionCount int
+	TotalAmount      int64
+}
+
+// CaseFilter selects fraud cases. Zero fields match every case.
+type CaseFilter struct {
+	Status   string
+	Assignee string
+	// OpenedBefore matches unresolved cases created before it
+	OpenedBefore time.Time
+	Limit        int
+}
+
+// CaseTransaction is a flagged transaction added to a fraud case.
+type CaseTransaction struct {
+	TransactionID   int
+	CaseID          int64
+	MerchantID      int
+	Amount          int64
+	Currency        string
+	CustomerEmail   string
+	CardFingerprint string
+	FlaggedAt       time.Time
+	AddedAt         time.Time
+}
+
+// CaseComment is an analyst's note on a fraud case.
+type CaseComment struct {
+	ID        int64
+	CaseID    int64
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// CaseAttachment is a file attached to a fraud case. Data is only loaded
+// when the attachment itself is fetched.
+type CaseAttachment struct {
+	ID          int64
+	CaseID      int64
+	Filename    string
+	ContentType string
+	Size        int64
+	UploadedBy  string
+	Data        []byte
+	CreatedAt   time.Time
+}
+
+// Velocity dimensions: what transactions must share to count towards each
+// other's velocity.
+const (
+	VelocityCard     = "card"
+	VelocityEmail    = "email"
+	VelocityIP       = "ip"
+	VelocityMerchant = "merchant"
+)
+
+// VelocityWindow is a dimension and the time before a transaction over which
+// to count transactions sharing it.
+type VelocityWindow struct {
+	Dimension string
+	Window    time.Duration
+}
+
+// VelocityStat counts the transactions in a velocity window, including the
+// one being evaluated. Volume is in minor currency units.
+type VelocityStat struct {
+	Count  int
+	Volume int64
+}
+
+// TransactionFacts is a transaction with what fraud rules evaluate it on.
+type TransactionFacts struct {
+	Transaction
+	MerchantCategory  string
+	MerchantCountry   string
+	MerchantCreatedAt time.Time
+	// Velocity holds the windows requested in the FactQuery
+	Velocity map[VelocityWindow]VelocityStat
+	// ReviewDecision is the latest approved or declined fraud review
+	// decision on the transaction, or empty when none has been made
+	ReviewDecision string
+}
+
+// FactQuery selects transactions created in [From, To) for rule evaluation,
+// oldest first, with the velocity windows to compute for each.
+type FactQuery struct {
+	From    time.Time
+	To      time.Time
+	Windows []VelocityWindow
+}
+
+// FraudRule is an admin-defined fraud rule at its current version.
+type FraudRule struct {
+	ID          int64
+	Name        string
+	Description string
+	Enabled     bool
+	Version     int
+	Expression  string
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// FraudRuleVersion is one revision of a fraud rule's expression.
+type FraudRuleVersion struct {
+	RuleID     int64
+	Version    int
+	Expression string
+	CreatedBy  string
+	CreatedAt  time.Time
+}
+
+// RuleFlag records that a version of a rule matched a transaction.
+type RuleFlag struct {
+	ID            int64
+	RuleID        int64
+	RuleName      string
+	RuleVersion   int
+	TransactionID int
+	CreatedAt     time.Time
+}
+
+// FlagFilter selects rule flags. Zero fields match everything; a limit of
+// zero or less returns up to 100.
+type FlagFilter struct {
+	RuleID        int64
+	TransactionID int
+	Limit         int
+}
+
+// Blocklist entry types.
+const (
+	BlockEmail           = "email"
+	BlockCardFingerprint = "card_fingerprint"
+	BlockCardBIN         = "card_bin"
+	BlockIP              = "ip"
+	BlockDevice          = "device_id"
+)
+
+// BlocklistEntry blocks a customer identifier across all merchants until it
+// expires.
+type BlocklistEntry struct {
+	ID   int64
+	Type string
+	// Value is normalised: emails are lower-cased and IPs are CIDR prefixes
+	Value     string
+	Reason    string
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// ExpiresAt is nil for entries that never expire
+	ExpiresAt *time.Time
+}
+
+// Active reports whether the entry blocks its value at t.
+func (e BlocklistEntry) Active(t time.Time) bool {
+	return e.ExpiresAt == nil || e.ExpiresAt.After(t)
+}
+
+// BlocklistFilter selects blocklist entries. Zero fields match every active
+// entry.
+type BlocklistFilter struct {
+	Type string
+	// Search matches entries whose value contains it
+	Search         string
+	IncludeExpired bool
+	Limit          int
+}
+
+// Merchant alert types.
+const (
+	AlertVolumeSpike     = "volume_spike"
+	AlertSuccessRateDrop = "success_rate_drop"
+)
+
+// Merchant alert statuses. Alerts are acknowledged while being looked into
+// and resolved once dealt with.
+const (
+	AlertStatusOpen         = "open"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
+)
+
+// MerchantAlert is an anomaly in a merchant's payments raised for admins. A
+// merchant has at most one unresolved alert of each type.
+type MerchantAlert struct {
+	ID         int64
+	MerchantID int
+	Type       string
+	Status     string
+	// Currency is the volume's currency for volume spikes and empty for
+	// success rate drops
+	Currency string
+	// Observed is the value over the period from PeriodStart to PeriodEnd,
+	// Expected the baseline it was compared with and Deviation how many
+	// standard deviations separate them
+	Observed    float64
+	Expected    float64
+	Deviation   float64
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// AcknowledgedBy and ResolvedBy are admin IDs, empty until the alert
+	// reaches that status
+	AcknowledgedBy string
+	AcknowledgedAt *time.Time
+	ResolvedBy     string
+	ResolvedAt     *time.Time
+	Resolution     string
+}
+
+// AlertFilter selects merchant alerts; zero fields match every alert.
+type AlertFilter struct {
+	Status     string
+	Type       string
+	MerchantID int
+	Limit      int
+}
+
+// MetricsQuery splits the time before End into Periods consecutive periods
+// of length Period.
+type MetricsQuery struct {
+	End     time.Time
+	Period  time.Duration
+	Periods int
+}
+
+// MerchantMetrics summarises a merchant's payments in one currency in one
+// period of a MetricsQuery. Volume is successful payments in minor units of
+// Currency.
+type MerchantMetrics struct {
+	MerchantID int
+	Currency   string
+	// Period counts back from the query's end: 0 ends at End
+	Period       int
+	Transactions int
+	Successful   int
+	Volume       int64
+}
+
+// Suspension policy conditions. The ratio conditions are the fraction of a
+// merchant's payments in the policy window with that outcome; kyc_rejections
+// counts the merchant's KYC rejections in the window.
+const (
+	PolicyChargebackRatio = "chargeback_ratio"
+	PolicyFailedRatio     = "failed_ratio"
+	PolicyRefundRatio     = "refund_ratio"
+	PolicyKYCRejections   = "kyc_rejections"
+)
+
+// Suspension policy actions: suspend the merchant, or recommend suspending
+// it to an admin.
+const (
+	PolicyActionSuspend   = "suspend"
+	PolicyActionRecommend = "recommend"
+)
+
+// Policy match statuses. A recommendation is pending until an admin approves
+// or dismisses it; matches of dry-run policies are only recorded.
+const (
+	MatchStatusSuspended = "suspended"
+	MatchStatusPending   = "pending"
+	MatchStatusApproved  = "approved"
+	MatchStatusDismissed = "dismissed"
+	MatchStatusDryRun    = "dry_run"
+)
+
+// SuspensionPolicy suspends, or recommends suspending, active merchants whose
+// Condition over the last WindowDays days reaches Threshold. Ratio
+// conditions are only evaluated for merchants with at least MinTransactions
+// payments in the window.
+type SuspensionPolicy struct {
+	ID              int64
+	Name            string
+	Description     string
+	Condition       string
+	Threshold       float64
+	WindowDays      int
+	MinTransactions int
+	Action          string
+	// DryRun policies record their matches without acting on them
+	DryRun    bool
+	Enabled   bool
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PolicyMatch records a suspension policy matching a merchant and what was
+// done about it.
+type PolicyMatch struct {
+	ID         int64
+	PolicyID   int64
+	PolicyName string
+	MerchantID int
+	// Value is the policy's condition for the merchant when it matched
+	Value  float64
+	Action string
+	Status string
+	// ReviewedBy is the admin who approved or dismissed a recommendation
+	ReviewedBy string
+	ReviewedAt *time.Time
+	ReviewNote string
+	CreatedAt  time.Time
+}
+
+// PolicyMatchFilter selects policy matches. Zero fields match everything; a
+// limit of zero or less returns up to 100.
+type PolicyMatchFilter struct {
+	PolicyID   int64
+	MerchantID int
+	Status     string
+	Limit      int
+}
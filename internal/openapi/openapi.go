@@ -0,0 +1,18 @@
+// Package openapi embeds the OpenAPI 3 document describing the /admin API
+// and the Swagger UI page that renders it.
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI document served at /admin/openapi.json. Routes added
+// to the admin handler must be described here; TestSpecMatchesRoutes in the
+// handlers package fails otherwise.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// SwaggerUI is the HTML page served at /admin/docs. It loads the Swagger UI
+// assets from /admin/docs/.
+//
+//go:embed swagger.html
+var SwaggerUI []byte
@@ -3,118 +3,325 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+var (
+	// ErrNotFound is returned when an update or lookup matches no rows.
+	ErrNotFound = errors.New("not found")
+	// ErrUnavailable is returned while the database cannot be reached.
+	ErrUnavailable = errors.New("database unavailable")
+	// ErrConflict is returned when a change would violate a uniqueness rule.
+	ErrConflict = errors.New("conflict")
+)
+
+const (
+	pingTimeout       = 5 * time.Second
+	pingInterval      = 15 * time.Second
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
 )
 
 type AdminRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	available atomic.Bool
+	stop      chan struct{}
+	stopOnce  sync.Once
+	done      chan struct{}
+	// recheck asks the monitor to ping now rather than at the next interval
+	recheck chan struct{}
 }
 
+// NewAdminRepository opens the connection pool without waiting for the database.
+// Connectivity is established in the background and queries fail with
+// ErrUnavailable until the first successful ping.
 func NewAdminRepository(dsn string) (*AdminRepository, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
 	// Set connection pool settings
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
-	return &AdminRepository{db: db}, nil
+	return newAdminRepository(db), nil
+}
+
+func newAdminRepository(db *sql.DB) *AdminRepository {
+	r := &AdminRepository{
+		db:      db,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		recheck: make(chan struct{}, 1),
+	}
+	go r.monitor()
+	return r
+}
+
+// Available reports whether the last health check reached the database.
+func (r *AdminRepository) Available() bool {
+	return r.available.Load()
+}
+
+// Ping checks the database directly, bypassing the cached availability.
+func (r *AdminRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// DB returns the underlying connection pool, for schema migrations.
+func (r *AdminRepository) DB() *sql.DB {
+	return r.db
+}
+
+// Stats returns connection pool statistics.
+func (r *AdminRepository) Stats() sql.DBStats {
+	return r.db.Stats()
+}
+
+// monitor pings the database, retrying with backoff until it is reachable and
+// then periodically so an outage after startup is noticed too. A query that
+// loses its connection has it ping straight away, so a dropped connection
+// does not keep the repository unavailable until the next interval.
+func (r *AdminRepository) monitor() {
+	defer close(r.done)
+	delay := minReconnectDelay
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+
+		wait := pingInterval
+		if err != nil {
+			slog.Warn("database unavailable", "error", err, "retry_in", delay.String())
+			r.available.Store(false)
+			wait = delay
+			delay = min(delay*2, maxReconnectDelay)
+		} else {
+			if !r.available.Swap(true) {
+				slog.Info("database connection established")
+			}
+			delay = minReconnectDelay
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-r.recheck:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// checkAvailable short-circuits queries while the database is known to be down.
+func (r *AdminRepository) checkAvailable() error {
+	if !r.available.Load() {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// wrapErr marks the repository unavailable when a query fails because the
+// connection was lost, so callers stop waiting on a dead database, and has
+// the monitor check the database again at once.
+func (r *AdminRepository) wrapErr(err error) error {
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) {
+		r.available.Store(false)
+		select {
+		case r.recheck <- struct{}{}:
+		default:
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
 }
 
+// Close stops the health monitor and closes the pool. Calling it again is
+// harmless.
 func (r *AdminRepository) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
 	return r.db.Close()
 }
 
-// ListMerchants retrieves merchants with basic fields for the admin portal
-func (r *AdminRepository) ListMerchants(ctx context.Context, limit int) ([]map[string]interface{}, error) {
-	if limit <= 0 {
-		limit = 100
-	}
-	query := `
-		SELECT
-			m.id,
-			m.name,
-			m.email,
-			m.business_name,
-			m.status,
-			m.kyc_status,
-			m.created_at,
-			m.updated_at,
-			COALESCE(mb.total_volume, 0) as total_volume,
-			COALESCE(mb.currency, 'NGN') as currency
+// merchantColumns and merchantFrom select merchants with their balances and
+// risk score summaries; both are optional.
+const (
+	merchantColumns = `
+		m.id,
+		m.name,
+		m.email,
+		m.business_name,
+		m.status,
+		m.kyc_status,
+		m.created_at,
+		m.updated_at,
+		COALESCE(mb.total_volume, 0) as total_volume,
+		COALESCE(mb.currency, 'NGN') as currency,
+		rs.score,
+		rs.level,
+		rs.computed_at`
+	merchantFrom = `
 		FROM merchants m
 		LEFT JOIN merchant_balances mb ON m.id = mb.merchant_id
-		ORDER BY m.created_at DESC
-		LIMIT $1
-	`
-	rows, err := r.db.QueryContext(ctx, query, limit)
-	if err != nil {
+		LEFT JOIN merchant_risk_scores rs ON m.id = rs.merchant_id`
+)
+
+// scanMerchant scans merchantColumns followed by any extra columns.
+func scanMerchant(row rowScanner, extra ...interface{}) (models.Merchant, error) {
+	var (
+		m          models.Merchant
+		score      sql.NullFloat64
+		level      sql.NullString
+		computedAt sql.NullTime
+	)
+	dest := append([]interface{}{&m.ID, &m.Name, &m.Email, &m.BusinessName, &m.Status, &m.KYCStatus, &m.CreatedAt, &m.UpdatedAt,
+		&m.TotalVolume, &m.Currency, &score, &level, &computedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return models.Merchant{}, err
+	}
+	if score.Valid {
+		m.Risk = &models.RiskScore{MerchantID: m.ID, Score: score.Float64, Level: level.String, ComputedAt: computedAt.Time}
+	}
+	return m, nil
+}
+
+// ListMerchants retrieves merchants with basic fields for the admin portal
+func (r *AdminRepository) ListMerchants(ctx context.Context, f models.MerchantFilter) (_ []models.Merchant, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListMerchants")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
 		return nil, err
 	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.RiskLevel != "" {
+		where = append(where, "rs.level = "+arg(f.RiskLevel))
+	}
+	if f.MinRiskScore > 0 {
+		where = append(where, "rs.score >= "+arg(f.MinRiskScore))
+	}
+	query := `SELECT ` + merchantColumns + merchantFrom
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	if f.SortByRisk {
+		query += " ORDER BY rs.score DESC NULLS LAST, m.created_at DESC"
+	} else {
+		query += " ORDER BY m.created_at DESC"
+	}
+	query += " LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
 	defer rows.Close()
 
-	var merchants []map[string]interface{}
+	var merchants []models.Merchant
 	for rows.Next() {
-		var (
-			id                                                         int // Changed from string to int
-			name, email, businessName, status, kycStatus, currency string
-			createdAt, updatedAt                                       time.Time
-			totalVolume                                                int64
-		)
-		if err := rows.Scan(&id, &name, &email, &businessName, &status, &kycStatus, &createdAt, &updatedAt, &totalVolume, &currency); err != nil {
+		m, err := scanMerchant(rows)
+		if err != nil {
 			return nil, err
 		}
-		merchants = append(merchants, map[string]interface{}{
-			"id":            id, // Changed to int
-			"name":          name,
-			"email":         email,
-			"business_name": businessName,
-			"status":        status,
-			"kyc_status":    kycStatus,
-			"created_at":    createdAt,
-			"updated_at":    updatedAt,
-			"total_volume":  totalVolume,
-			"currency":      currency,
-		})
+		merchants = append(merchants, m)
 	}
 	return merchants, rows.Err()
 }
 
+func (r *AdminRepository) GetMerchant(ctx context.Context, id int) (_ models.Merchant, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetMerchant")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.Merchant{}, err
+	}
+
+	var factors []byte
+	m, err := scanMerchant(r.db.QueryRowContext(ctx, `SELECT `+merchantColumns+`, rs.factors`+merchantFrom+` WHERE m.id = $1`, id), &factors)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Merchant{}, fmt.Errorf("merchant %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.Merchant{}, r.wrapErr(err)
+	}
+	if m.Risk != nil {
+		if m.Risk.Factors, err = decodeRiskFactors(factors); err != nil {
+			return models.Merchant{}, err
+		}
+	}
+	return m, nil
+}
+
 // UpdateMerchantStatus updates the merchant status
-func (r *AdminRepository) UpdateMerchantStatus(ctx context.Context, id int, status string) error { // Changed id from string to int
-	log.Printf("Attempting to update merchant status for ID: %d to status: %s", id, status) // Changed %s to %d
+func (r *AdminRepository) UpdateMerchantStatus(ctx context.Context, id int, status string, audit models.AuditEntry) (err error) { // Changed id from string to int
+	ctx, span := tracing.StartDB(ctx, "UpdateMerchantStatus")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return err
+	}
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		return updateMerchantStatus(ctx, tx, id, status, auditTarget(audit, int64(id)))
+	})
+}
+
+// updateMerchantStatus sets the merchant's status within tx and records audit
+// alongside it.
+func updateMerchantStatus(ctx context.Context, tx *sql.Tx, id int, status string, audit models.AuditEntry) error {
 	query := `UPDATE merchants SET status = $2, updated_at = NOW() WHERE id = $1`
-	res, err := r.db.ExecContext(ctx, query, id, status) // id is int
+	res, err := tx.ExecContext(ctx, query, id, status)
 	if err != nil {
-		log.Printf("Error updating merchant status for ID %d: %v", id, err) // Changed %s to %d
 		return err
 	}
 	affected, err := res.RowsAffected()
 	if err != nil {
-		log.Printf("Error getting rows affected after updating merchant status for ID %d: %v", id, err) // Changed %s to %d
 		return err
 	}
 	if affected == 0 {
-		log.Printf("No merchant found with ID: %d or status was already %s", id, status) // Changed %s to %d
-		return fmt.Errorf("merchant not found or status already %s", status)
+		return fmt.Errorf("merchant %d: %w", id, ErrNotFound)
 	}
-	log.Printf("Successfully updated merchant status for ID: %d to status: %s. Rows affected: %d", id, status, affected) // Changed %s to %d
-	return nil
+	slog.DebugContext(ctx, "updated merchant status", "status", status, "rows_affected", affected)
+	return insertAudit(ctx, tx, audit)
 }
 
 // GetStats retrieves platform statistics
-func (r *AdminRepository) GetStats(ctx context.Context) (map[string]interface{}, error) {
+func (r *AdminRepository) GetStats(ctx context.Context) (_ models.PlatformStats, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetStats")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.PlatformStats{}, err
+	}
 	query := `
 		SELECT
 			COUNT(DISTINCT m.id) as total_merchants,
@@ -129,125 +336,76 @@ func (r *AdminRepository) GetStats(ctx context.Context) (map[string]interface{},
 	`
 
 	var (
-		totalMerchants, activeMerchants, pendingKYC, totalTransactions int
-		totalVolume, monthlyVolume                                     int64
-		successRate                                                    sql.NullFloat64
+		stats       models.PlatformStats
+		successRate sql.NullFloat64
 	)
-
-	err := r.db.QueryRowContext(ctx, query).Scan(
-		&totalMerchants, &activeMerchants, &pendingKYC,
-		&totalTransactions, &totalVolume, &monthlyVolume, &successRate,
+	err = r.db.QueryRowContext(ctx, query).Scan(
+		&stats.TotalMerchants, &stats.ActiveMerchants, &stats.PendingKYC,
+		&stats.TotalTransactions, &stats.TotalVolume, &stats.MonthlyVolume, &successRate,
 	)
 	if err != nil {
-		return nil, err
+		return models.PlatformStats{}, r.wrapErr(err)
 	}
-
-	stats := map[string]interface{}{
-		"total_merchants":    totalMerchants,
-		"active_merchants":   activeMerchants,
-		"pending_kyc":        pendingKYC,
-		"total_transactions": totalTransactions,
-		"total_volume":       totalVolume,
-		"monthly_volume":     monthlyVolume,
-		"success_rate":       0.0,
-		"timestamp":          time.Now().UTC().Format(time.RFC3339),
-	}
-
 	if successRate.Valid {
-		stats["success_rate"] = successRate.Float64
+		stats.SuccessRate = successRate.Float64
 	}
-
+	stats.GeneratedAt = time.Now().UTC()
 	return stats, nil
 }
 
-// GetTransactions retrieves recent transactions
-func (r *AdminRepository) GetTransactions(ctx context.Context, limit int) ([]map[string]interface{}, error) {
+// ListTransactions retrieves the most recent payments, newest first
+func (r *AdminRepository) ListTransactions(ctx context.Context, limit int) (_ []models.Transaction, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListTransactions")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
 	query := `
-		SELECT * FROM (
-			SELECT
-				t.id,
-				t.reference,
-				t.merchant_id,
-				m.business_name as merchant_name,
-				t.customer_email,
-				t.customer_name,
-				t.amount,
-				t.currency,
-				t.status,
-				t.payment_method,
-				t.created_at,
-				'payment' as type
-			FROM transactions t
-			JOIN merchants m ON t.merchant_id = m.id
-			
-			UNION ALL
-			
-			SELECT
-				p.id,
-				p.reference,
-				p.merchant_id,
-				m.business_name as merchant_name,
-				'' as customer_email,
-				'' as customer_name,
-				p.amount,
-				p.currency,
-				p.status,
-				'payout' as payment_method,
-				p.created_at,
-				'payout' as type
-			FROM payouts p
-			JOIN merchants m ON p.merchant_id = m.id
-		) AS combined
-		ORDER BY created_at DESC
+		SELECT
+			t.id,
+			t.reference,
+			t.merchant_id,
+			m.business_name as merchant_name,
+			t.customer_email,
+			t.customer_name,
+			t.amount,
+			t.currency,
+			t.status,
+			t.payment_method,
+			t.created_at
+		FROM transactions t
+		JOIN merchants m ON t.merchant_id = m.id
+		ORDER BY t.created_at DESC
 		LIMIT $1
 	`
-
 	rows, err := r.db.QueryContext(ctx, query, limit)
 	if err != nil {
-		return nil, err
+		return nil, r.wrapErr(err)
 	}
 	defer rows.Close()
 
-	transactions := []map[string]interface{}{}
+	transactions := []models.Transaction{}
 	for rows.Next() {
 		var (
-			id, merchantID                                                                    int // Changed from string to int
-			reference, merchantName, customerEmail, currency, status, txnType string
-			customerName, paymentMethod                                                       sql.NullString
-			amount                                                                            int64
-			createdAt                                                                         time.Time
+			t                           models.Transaction
+			customerName, paymentMethod sql.NullString
 		)
-
-		err := rows.Scan(
-			&id, &reference, &merchantID, &merchantName, &customerEmail,
-			&customerName, &amount, &currency, &status, &paymentMethod, &createdAt, &txnType,
-		)
-		if err != nil {
+		if err := rows.Scan(
+			&t.ID, &t.Reference, &t.MerchantID, &t.MerchantName, &t.CustomerEmail,
+			&customerName, &t.Amount, &t.Currency, &t.Status, &paymentMethod, &t.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
-
-		transaction := map[string]interface{}{
-			"id":             id,
-			"reference":      reference,
-			"merchant_id":    merchantID,
-			"merchant_name":  merchantName,
-			"customer_email": customerEmail,
-			"amount":         float64(amount) / 100, // return currency units
-			"currency":       currency,
-			"status":         status,
-			"created_at":     createdAt,
-			"type":           txnType,
-		}
-
-		if customerName.Valid {
-			transaction["customer_name"] = customerName.String
-		}
-		if paymentMethod.Valid {
-			transaction["payment_method"] = paymentMethod.String
-		}
-
-		transactions = append(transactions, transaction)
+		t.CustomerName = nullString(customerName)
+		t.PaymentMethod = nullString(paymentMethod)
+		transactions = append(transactions, t)
 	}
-
 	return transactions, rows.Err()
 }
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/migrations"
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// The Postgres contract tests need a scratch database, named by
+// TEST_POSTGRES_URL. Each test creates its own schema holding the columns
+// the repository reads from the tables other services own, and drops it
+// afterwards.
+const testPostgresEnv = "TEST_POSTGRES_URL"
+
+const testSchema = `
+	CREATE TABLE merchants (
+		id            INTEGER PRIMARY KEY,
+		name          TEXT NOT NULL,
+		email         TEXT NOT NULL,
+		business_name TEXT NOT NULL,
+		status        TEXT NOT NULL,
+		kyc_status    TEXT NOT NULL,
+		category      TEXT,
+		country       TEXT,
+		kyc_verified_at TIMESTAMPTZ,
+		created_at    TIMESTAMPTZ NOT NULL,
+		updated_at    TIMESTAMPTZ NOT NULL
+	);
+	CREATE TABLE merchant_balances (
+		merchant_id  INTEGER PRIMARY KEY REFERENCES merchants (id),
+		total_volume BIGINT NOT NULL,
+		currency     TEXT NOT NULL
+	);
+	CREATE TABLE transactions (
+		id               INTEGER PRIMARY KEY,
+		reference        TEXT NOT NULL,
+		merchant_id      INTEGER NOT NULL REFERENCES merchants (id),
+		customer_email   TEXT NOT NULL,
+		customer_name    TEXT,
+		amount           BIGINT NOT NULL,
+		currency         TEXT NOT NULL,
+		status           TEXT NOT NULL,
+		payment_method   TEXT,
+		card_fingerprint TEXT,
+		ip_address       TEXT,
+		card_country     TEXT,
+		ip_country       TEXT,
+		created_at       TIMESTAMPTZ NOT NULL
+	);
+	CREATE TABLE payouts (
+		id          INTEGER PRIMARY KEY,
+		reference   TEXT NOT NULL,
+		merchant_id INTEGER NOT NULL REFERENCES merchants (id),
+		amount      BIGINT NOT NULL,
+		currency    TEXT NOT NULL,
+		status      TEXT NOT NULL,
+		created_at  TIMESTAMPTZ NOT NULL
+	)`
+
+type postgresFixture struct {
+	*AdminRepository
+}
+
+func (f postgresFixture) exec(t *testing.T, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := f.db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f postgresFixture) addMerchant(t *testing.T, m models.Merchant) {
+	f.exec(t, `INSERT INTO merchants (id, name, email, business_name, status, kyc_status, category, country, kyc_verified_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11)`,
+		m.ID, m.Name, m.Email, m.BusinessName, m.Status, m.KYCStatus, m.Category, m.Country, m.KYCVerifiedAt, m.CreatedAt, m.UpdatedAt)
+	if m.Currency != "" {
+		f.exec(t, `INSERT INTO merchant_balances (merchant_id, total_volume, currency) VALUES ($1, $2, $3)`,
+			m.ID, m.TotalVolume, m.Currency)
+	}
+}
+
+func (f postgresFixture) addTransaction(t *testing.T, tx models.Transaction) {
+	f.exec(t, `INSERT INTO transactions (id, reference, merchant_id, customer_email, customer_name, amount, currency, status, payment_method,
+			card_fingerprint, ip_address, card_country, ip_country, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), $14)`,
+		tx.ID, tx.Reference, tx.MerchantID, tx.CustomerEmail, tx.CustomerName, tx.Amount, tx.Currency, tx.Status, tx.PaymentMethod,
+		tx.CardFingerprint, tx.IPAddress, tx.CardCountry, tx.IPCountry, tx.CreatedAt)
+}
+
+func (f postgresFixture) addPayout(t *testing.T, p models.Payout) {
+	f.exec(t, `INSERT INTO payouts (id, reference, merchant_id, amount, currency, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		p.ID, p.Reference, p.MerchantID, p.Amount, p.Currency, p.Status, p.CreatedAt)
+}
+
+func (f postgresFixture) auditLog(t *testing.T) []models.AuditEntry {
+	rows, err := f.db.Query(`
+		SELECT id, actor, action, target_type, target_id, details, COALESCE(request_id, ''), created_at
+		FROM admin_audit_log ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var (
+			e       models.AuditEntry
+			details []byte
+		)
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.TargetType, &e.TargetID, &details, &e.RequestID, &e.CreatedAt); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(details, &e.Details); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestAdminRepository(t *testing.T) {
+	dsn := os.Getenv(testPostgresEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testPostgresEnv)
+	}
+	testAdminStoreContract(t, func(t *testing.T) storeFixture {
+		return postgresFixture{newTestRepository(t, dsn)}
+	})
+}
+
+// newTestRepository returns a repository whose search_path is a fresh schema
+// containing the test tables and the service's own migrated tables.
+func newTestRepository(t *testing.T, dsn string) *AdminRepository {
+	t.Helper()
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("admin_store_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("failed to drop schema %s: %v", schema, err)
+		}
+	})
+	if _, err := admin.Exec(`SET search_path TO ` + schema + `; ` + testSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	repo, err := NewAdminRepository(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for !repo.Available() {
+		select {
+		case <-ctx.Done():
+			t.Fatal("database did not become available")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	migrator, err := migrations.New(repo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestAdminRepositoryCloseTwice(t *testing.T) {
+	// nothing listens on port 1, so the repository never becomes available
+	repo, err := NewAdminRepository("postgres://admin@127.0.0.1:1/admin?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+// pingDriver opens connections that answer pings and nothing else.
+type pingDriver struct{}
+
+func (pingDriver) Open(string) (driver.Conn, error) { return pingConn{}, nil }
+
+type pingConn struct{}
+
+func (pingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (pingConn) Close() error                        { return nil }
+func (pingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (pingConn) Ping(context.Context) error          { return nil }
+
+func init() {
+	sql.Register("ping", pingDriver{})
+}
+
+func TestLostConnectionRechecksAtOnce(t *testing.T) {
+	db, err := sql.Open("ping", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newAdminRepository(db)
+	defer repo.Close()
+	waitAvailable := func() {
+		t.Helper()
+		// well inside pingInterval, so only an immediate recheck can pass
+		deadline := time.Now().Add(2 * time.Second)
+		for !repo.Available() {
+			if time.Now().After(deadline) {
+				t.Fatal("repository did not become available")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitAvailable()
+
+	err = repo.wrapErr(&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("wrapErr = %v, want ErrUnavailable", err)
+	}
+	waitAvailable()
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	dsn := os.Getenv(testPostgresEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testPostgresEnv)
+	}
+	ctx := context.Background()
+	store := NewIdempotencyStore(newTestRepository(t, dsn))
+	now := time.Now().UTC().Truncate(time.Second)
+	request := func(key, hash string, createdAt time.Time) *middleware.IdempotencyRecord {
+		return &middleware.IdempotencyRecord{Key: key, RequestHash: hash, CreatedAt: createdAt}
+	}
+
+	// of two replicas racing for a key, one reserves it and the other sees it pending
+	var (
+		wg       sync.WaitGroup
+		reserved atomic.Int32
+	)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			existing, err := store.Reserve(ctx, request("k1", "h1", now), time.Minute)
+			switch {
+			case err != nil:
+				t.Error(err)
+			case existing == nil:
+				reserved.Add(1)
+			case existing.Completed():
+				t.Errorf("pending key reported complete: %+v", existing)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved.Load() != 1 {
+		t.Fatalf("%d goroutines reserved the key, want 1", reserved.Load())
+	}
+
+	done := request("k1", "h1", now)
+	done.StatusCode, done.ContentType, done.Body = 200, "application/json", []byte(`{"id":1}`)
+	if err := store.Complete(ctx, done, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// a completed record is not released and keeps the first response
+	if err := store.Release(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := store.Reserve(ctx, request("k1", "h2", now), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec == nil || rec.RequestHash != "h1" || rec.StatusCode != 200 || rec.ContentType != "application/json" || string(rec.Body) != `{"id":1}` || !rec.CreatedAt.Equal(now) {
+		t.Errorf("Reserve of a completed key = %+v", rec)
+	}
+
+	// a released reservation can be taken again
+	if rec, err := store.Reserve(ctx, request("k2", "h1", now), time.Minute); err != nil || rec != nil {
+		t.Fatalf("Reserve k2 = %+v, %v", rec, err)
+	}
+	if err := store.Release(ctx, "k2"); err != nil {
+		t.Fatal(err)
+	}
+	if rec, err := store.Reserve(ctx, request("k2", "h1", now), time.Minute); err != nil || rec != nil {
+		t.Errorf("Reserve after Release = %+v, %v", rec, err)
+	}
+
+	// an expired record is replaced
+	old := request("k3", "h1", now.Add(-2*time.Hour))
+	if _, err := store.Reserve(ctx, old, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	old.StatusCode, old.Body = 201, []byte(`{}`)
+	if err := store.Complete(ctx, old, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if rec, err := store.Reserve(ctx, request("k3", "h2", now), time.Minute); err != nil || rec != nil {
+		t.Errorf("Reserve over an expired record = %+v, %v", rec, err)
+	}
+}
@@ -0,0 +1,221 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+const alertColumns = `id, merchant_id, type, status, currency, observed, expected, deviation, period_start, period_end, created_at, updated_at,
+	COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(resolved_by, ''), resolved_at, COALESCE(resolution, '')`
+
+func scanAlert(row rowScanner) (models.MerchantAlert, error) {
+	var (
+		a                        models.MerchantAlert
+		acknowledgedAt, resolved sql.NullTime
+	)
+	err := row.Scan(&a.ID, &a.MerchantID, &a.Type, &a.Status, &a.Currency, &a.Observed, &a.Expected, &a.Deviation, &a.PeriodStart, &a.PeriodEnd,
+		&a.CreatedAt, &a.UpdatedAt, &a.AcknowledgedBy, &acknowledgedAt, &a.ResolvedBy, &resolved, &a.Resolution)
+	if acknowledgedAt.Valid {
+		a.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	if resolved.Valid {
+		a.ResolvedAt = &resolved.Time
+	}
+	return a, err
+}
+
+// MerchantMetrics buckets payments by currency and by how many whole periods
+// before End they were made, so period 0 covers (End-Period, End].
+func (r *AdminRepository) MerchantMetrics(ctx context.Context, q models.MetricsQuery) (_ []models.MerchantMetrics, err error) {
+	ctx, span := tracing.StartDB(ctx, "MerchantMetrics")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			merchant_id,
+			currency,
+			FLOOR(EXTRACT(EPOCH FROM ($1::timestamptz - created_at)) / $2::double precision)::int AS period,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'successful'),
+			COALESCE(SUM(amount) FILTER (WHERE status = 'successful'), 0)
+		FROM transactions
+		WHERE created_at <= $1 AND created_at > $3
+		GROUP BY merchant_id, currency, period
+		ORDER BY merchant_id, currency, period`,
+		q.End, q.Period.Seconds(), q.End.Add(-q.Period*time.Duration(q.Periods)))
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var metrics []models.MerchantMetrics
+	for rows.Next() {
+		var m models.MerchantMetrics
+		if err := rows.Scan(&m.MerchantID, &m.Currency, &m.Period, &m.Transactions, &m.Successful, &m.Volume); err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, rows.Err()
+}
+
+func (r *AdminRepository) RaiseAlert(ctx context.Context, a models.MerchantAlert) (_ models.MerchantAlert, _ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "RaiseAlert")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.MerchantAlert{}, false, err
+	}
+
+	raised, err := scanAlert(r.db.QueryRowContext(ctx, `
+		INSERT INTO merchant_alerts (merchant_id, type, currency, observed, expected, deviation, period_start, period_end)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (merchant_id, type) WHERE status <> 'resolved' DO NOTHING
+		RETURNING `+alertColumns,
+		a.MerchantID, a.Type, a.Currency, a.Observed, a.Expected, a.Deviation, a.PeriodStart, a.PeriodEnd))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.MerchantAlert{}, false, nil
+	}
+	if err != nil {
+		return models.MerchantAlert{}, false, r.wrapErr(err)
+	}
+	return raised, true, nil
+}
+
+func (r *AdminRepository) ListAlerts(ctx context.Context, f models.AlertFilter) (_ []models.MerchantAlert, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListAlerts")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.Status != "" {
+		where = append(where, "status = "+arg(f.Status))
+	}
+	if f.Type != "" {
+		where = append(where, "type = "+arg(f.Type))
+	}
+	if f.MerchantID != 0 {
+		where = append(where, "merchant_id = "+arg(f.MerchantID))
+	}
+	query := `SELECT ` + alertColumns + ` FROM merchant_alerts`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var alerts []models.MerchantAlert
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *AdminRepository) GetAlert(ctx context.Context, id int64) (_ models.MerchantAlert, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetAlert")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.MerchantAlert{}, err
+	}
+	a, err := scanAlert(r.db.QueryRowContext(ctx, `SELECT `+alertColumns+` FROM merchant_alerts WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.MerchantAlert{}, fmt.Errorf("alert %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.MerchantAlert{}, r.wrapErr(err)
+	}
+	return a, nil
+}
+
+func (r *AdminRepository) AcknowledgeAlert(ctx context.Context, id int64, by string, audit models.AuditEntry) (_ models.MerchantAlert, err error) {
+	ctx, span := tracing.StartDB(ctx, "AcknowledgeAlert")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.MerchantAlert{}, err
+	}
+	return r.updateAlert(ctx, id, audit, `
+		UPDATE merchant_alerts SET
+			status = 'acknowledged',
+			acknowledged_by = $2,
+			acknowledged_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'open'
+		RETURNING `+alertColumns, by)
+}
+
+func (r *AdminRepository) ResolveAlert(ctx context.Context, id int64, by, resolution string, audit models.AuditEntry) (_ models.MerchantAlert, err error) {
+	ctx, span := tracing.StartDB(ctx, "ResolveAlert")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.MerchantAlert{}, err
+	}
+	return r.updateAlert(ctx, id, audit, `
+		UPDATE merchant_alerts SET
+			status = 'resolved',
+			resolved_by = $2,
+			resolved_at = NOW(),
+			resolution = $3,
+			updated_at = NOW()
+		WHERE id = $1 AND status <> 'resolved'
+		RETURNING `+alertColumns, by, resolution)
+}
+
+// updateAlert runs an update of alert id that matches no rows when the alert
+// is not in a status it applies to, writing the audit entry with it.
+func (r *AdminRepository) updateAlert(ctx context.Context, id int64, audit models.AuditEntry, query string, args ...interface{}) (models.MerchantAlert, error) {
+	var updated models.MerchantAlert
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		updated, err = scanAlert(tx.QueryRowContext(ctx, query, append([]interface{}{id}, args...)...))
+		if errors.Is(err, sql.ErrNoRows) {
+			var status string
+			err := tx.QueryRowContext(ctx, `SELECT status FROM merchant_alerts WHERE id = $1`, id).Scan(&status)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("alert %d: %w", id, ErrNotFound)
+			}
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("alert %d is %s: %w", id, status, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.MerchantAlert{}, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testMerchantAlertContract runs the merchant alert behaviour every
+// AdminStore must share; it is called from testAdminStoreContract.
+func testMerchantAlertContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "merchant_alert"}
+	}
+	alert := func(merchantID int, typ string) models.MerchantAlert {
+		return models.MerchantAlert{
+			MerchantID: merchantID, Type: typ, Currency: "NGN", Observed: 40000, Expected: 10000, Deviation: 4.5,
+			PeriodStart: base.Add(-24 * time.Hour), PeriodEnd: base,
+		}
+	}
+
+	t.Run("MerchantMetrics buckets periods", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base.Add(-30*24*time.Hour)))
+		s.addMerchant(t, merchant(2, base.Add(-30*24*time.Hour)))
+		s.addTransaction(t, transaction(1, 1, 100, "successful", base))
+		s.addTransaction(t, transaction(2, 1, 200, "failed", base.Add(-time.Hour)))
+		s.addTransaction(t, transaction(3, 1, 400, "successful", base.Add(-24*time.Hour)))
+		s.addTransaction(t, transaction(4, 2, 800, "successful", base.Add(-47*time.Hour)))
+		usd := transaction(7, 1, 50, "successful", base.Add(-2*time.Hour))
+		usd.Currency = "USD"
+		s.addTransaction(t, usd)
+		// outside the query
+		s.addTransaction(t, transaction(5, 1, 1600, "successful", base.Add(time.Minute)))
+		s.addTransaction(t, transaction(6, 2, 3200, "successful", base.Add(-48*time.Hour)))
+
+		got, err := s.MerchantMetrics(ctx, models.MetricsQuery{End: base, Period: 24 * time.Hour, Periods: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []models.MerchantMetrics{
+			{MerchantID: 1, Currency: "NGN", Period: 0, Transactions: 2, Successful: 1, Volume: 100},
+			{MerchantID: 1, Currency: "NGN", Period: 1, Transactions: 1, Successful: 1, Volume: 400},
+			{MerchantID: 1, Currency: "USD", Period: 0, Transactions: 1, Successful: 1, Volume: 50},
+			{MerchantID: 2, Currency: "NGN", Period: 1, Transactions: 1, Successful: 1, Volume: 800},
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("metrics = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("alert workflow", func(t *testing.T) {
+		s := newStore(t)
+		a, raised, err := s.RaiseAlert(ctx, alert(1, models.AlertVolumeSpike))
+		if err != nil || !raised {
+			t.Fatalf("RaiseAlert = %+v, %v, %v", a, raised, err)
+		}
+		if a.ID == 0 || a.Status != models.AlertStatusOpen || a.Currency != "NGN" || a.Observed != 40000 || !a.PeriodEnd.Equal(base) || a.CreatedAt.IsZero() {
+			t.Errorf("raised %+v", a)
+		}
+		if _, raised, err := s.RaiseAlert(ctx, alert(1, models.AlertVolumeSpike)); err != nil || raised {
+			t.Errorf("raising again while unresolved: raised %v, %v", raised, err)
+		}
+		if _, raised, err := s.RaiseAlert(ctx, alert(1, models.AlertSuccessRateDrop)); err != nil || !raised {
+			t.Errorf("raising another type: raised %v, %v", raised, err)
+		}
+
+		a, err = s.AcknowledgeAlert(ctx, a.ID, "analyst-1", audit("merchant_alert.acknowledged"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Status != models.AlertStatusAcknowledged || a.AcknowledgedBy != "analyst-1" || a.AcknowledgedAt == nil {
+			t.Errorf("acknowledged %+v", a)
+		}
+		if _, err := s.AcknowledgeAlert(ctx, a.ID, "analyst-1", audit("merchant_alert.acknowledged")); !errors.Is(err, ErrConflict) {
+			t.Errorf("acknowledging twice: got %v, want ErrConflict", err)
+		}
+		if _, raised, err := s.RaiseAlert(ctx, alert(1, models.AlertVolumeSpike)); err != nil || raised {
+			t.Errorf("raising while acknowledged: raised %v, %v", raised, err)
+		}
+
+		a, err = s.ResolveAlert(ctx, a.ID, "analyst-2", "seasonal sale", audit("merchant_alert.resolved"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Status != models.AlertStatusResolved || a.ResolvedBy != "analyst-2" || a.ResolvedAt == nil || a.Resolution != "seasonal sale" || a.AcknowledgedBy != "analyst-1" {
+			t.Errorf("resolved %+v", a)
+		}
+		if _, err := s.ResolveAlert(ctx, a.ID, "analyst-2", "again", audit("merchant_alert.resolved")); !errors.Is(err, ErrConflict) {
+			t.Errorf("resolving twice: got %v, want ErrConflict", err)
+		}
+		if _, err := s.ResolveAlert(ctx, a.ID+100, "analyst-2", "missing", audit("merchant_alert.resolved")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("resolving a missing alert: got %v, want ErrNotFound", err)
+		}
+		if _, err := s.GetAlert(ctx, a.ID+100); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetAlert on missing alert: got %v, want ErrNotFound", err)
+		}
+
+		// once resolved, the anomaly may be raised again
+		again, raised, err := s.RaiseAlert(ctx, alert(1, models.AlertVolumeSpike))
+		if err != nil || !raised || again.ID == a.ID {
+			t.Errorf("raising after resolution = %+v, %v, %v", again, raised, err)
+		}
+
+		log := s.auditLog(t)
+		if len(log) != 2 || log[0].Action != "merchant_alert.acknowledged" || log[1].TargetID != itoa64(a.ID) {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("ListAlerts filters", func(t *testing.T) {
+		s := newStore(t)
+		var ids []int64
+		for _, a := range []models.MerchantAlert{
+			alert(1, models.AlertVolumeSpike), alert(1, models.AlertSuccessRateDrop), alert(2, models.AlertVolumeSpike),
+		} {
+			raised, _, err := s.RaiseAlert(ctx, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, raised.ID)
+		}
+		if _, err := s.ResolveAlert(ctx, ids[0], "analyst-1", "ok", audit("merchant_alert.resolved")); err != nil {
+			t.Fatal(err)
+		}
+
+		list := func(f models.AlertFilter) []int64 {
+			t.Helper()
+			alerts, err := s.ListAlerts(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, a := range alerts {
+				got = append(got, a.ID)
+			}
+			return got
+		}
+		if got := list(models.AlertFilter{}); !slices.Equal(got, []int64{ids[2], ids[1], ids[0]}) {
+			t.Errorf("all alerts = %v", got)
+		}
+		if got := list(models.AlertFilter{Status: models.AlertStatusOpen}); !slices.Equal(got, []int64{ids[2], ids[1]}) {
+			t.Errorf("open alerts = %v", got)
+		}
+		if got := list(models.AlertFilter{MerchantID: 1, Type: models.AlertVolumeSpike}); !slices.Equal(got, []int64{ids[0]}) {
+			t.Errorf("merchant 1 volume spikes = %v", got)
+		}
+		if got := list(models.AlertFilter{Limit: 1}); len(got) != 1 {
+			t.Errorf("limit 1 = %v", got)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// insertAudit appends an entry to the audit log within tx, so the entry is
+// only kept if the change it describes is.
+func insertAudit(ctx context.Context, tx *sql.Tx, e models.AuditEntry) error {
+	details, err := json.Marshal(e.Details)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit details: %w", err)
+	}
+	if e.Details == nil {
+		details = []byte("{}")
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO admin_audit_log (actor, action, target_type, target_id, details, request_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))`,
+		e.Actor, e.Action, e.TargetType, e.TargetID, details, e.RequestID)
+	return err
+}
+
+// auditTarget defaults an entry's TargetID to the ID of the record changed.
+func auditTarget(audit models.AuditEntry, id int64) models.AuditEntry {
+	if audit.TargetID == "" {
+		audit.TargetID = strconv.FormatInt(id, 10)
+	}
+	return audit
+}
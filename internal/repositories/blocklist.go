@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+const blocklistColumns = `id, type, value, reason, created_by, created_at, updated_at, expires_at`
+
+// addBlocklistEntry inserts an entry, or revives the expired entry for the
+// same value. It returns no rows while an active entry blocks the value.
+const addBlocklistEntry = `
+	INSERT INTO blocklist_entries (type, value, reason, created_by, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (type, value) DO UPDATE SET
+		reason = EXCLUDED.reason,
+		created_by = EXCLUDED.created_by,
+		expires_at = EXCLUDED.expires_at,
+		created_at = NOW(),
+		updated_at = NOW()
+	WHERE blocklist_entries.expires_at IS NOT NULL AND blocklist_entries.expires_at <= NOW()
+	RETURNING ` + blocklistColumns
+
+func scanBlocklistEntry(row rowScanner) (models.BlocklistEntry, error) {
+	var (
+		e         models.BlocklistEntry
+		expiresAt sql.NullTime
+	)
+	err := row.Scan(&e.ID, &e.Type, &e.Value, &e.Reason, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt, &expiresAt)
+	if expiresAt.Valid {
+		e.ExpiresAt = &expiresAt.Time
+	}
+	return e, err
+}
+
+func (r *AdminRepository) AddBlocklistEntry(ctx context.Context, e models.BlocklistEntry, audit models.AuditEntry) (_ models.BlocklistEntry, err error) {
+	ctx, span := tracing.StartDB(ctx, "AddBlocklistEntry")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.BlocklistEntry{}, err
+	}
+
+	var added models.BlocklistEntry
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		added, err = scanBlocklistEntry(tx.QueryRowContext(ctx, addBlocklistEntry, e.Type, e.Value, e.Reason, e.CreatedBy, e.ExpiresAt))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("blocklist %s %q: %w", e.Type, e.Value, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, added.ID))
+	})
+	if err != nil {
+		return models.BlocklistEntry{}, err
+	}
+	return added, nil
+}
+
+func (r *AdminRepository) ImportBlocklistEntries(ctx context.Context, entries []models.BlocklistEntry, audit models.AuditEntry) (_ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ImportBlocklistEntries")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, addBlocklistEntry)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, e := range entries {
+			_, err := scanBlocklistEntry(stmt.QueryRowContext(ctx, e.Type, e.Value, e.Reason, e.CreatedBy, e.ExpiresAt))
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			added++
+		}
+		return insertAudit(ctx, tx, audit)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func (r *AdminRepository) ExpireBlocklistEntry(ctx context.Context, id int64, at time.Time, audit models.AuditEntry) (_ models.BlocklistEntry, err error) {
+	ctx, span := tracing.StartDB(ctx, "ExpireBlocklistEntry")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.BlocklistEntry{}, err
+	}
+
+	var expired models.BlocklistEntry
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		expired, err = scanBlocklistEntry(tx.QueryRowContext(ctx, `
+			UPDATE blocklist_entries SET expires_at = $2, updated_at = NOW()
+			WHERE id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+			RETURNING `+blocklistColumns, id, at))
+		if errors.Is(err, sql.ErrNoRows) {
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM blocklist_entries WHERE id = $1)`, id).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("blocklist entry %d already expired: %w", id, ErrConflict)
+			}
+			return fmt.Errorf("blocklist entry %d: %w", id, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.BlocklistEntry{}, err
+	}
+	return expired, nil
+}
+
+func (r *AdminRepository) ListBlocklist(ctx context.Context, f models.BlocklistFilter) (_ []models.BlocklistEntry, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListBlocklist")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.Type != "" {
+		where = append(where, "type = "+arg(f.Type))
+	}
+	if f.Search != "" {
+		where = append(where, "strpos(value, "+arg(f.Search)+") > 0")
+	}
+	if !f.IncludeExpired {
+		where = append(where, "(expires_at IS NULL OR expires_at > NOW())")
+	}
+	query := `SELECT ` + blocklistColumns + ` FROM blocklist_entries`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT " + arg(f.Limit)
+	return r.queryBlocklist(ctx, query, args...)
+}
+
+func (r *AdminRepository) ActiveBlocklist(ctx context.Context) (_ []models.BlocklistEntry, err error) {
+	ctx, span := tracing.StartDB(ctx, "ActiveBlocklist")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	return r.queryBlocklist(ctx, `
+		SELECT `+blocklistColumns+` FROM blocklist_entries
+		WHERE expires_at IS NULL OR expires_at > NOW()
+		ORDER BY id`)
+}
+
+func (r *AdminRepository) queryBlocklist(ctx context.Context, query string, args ...interface{}) ([]models.BlocklistEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	entries := []models.BlocklistEntry{}
+	for rows.Next() {
+		e, err := scanBlocklistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, r.wrapErr(rows.Err())
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testBlocklistContract runs the blocklist behaviour every AdminStore must
+// share; it is called from testAdminStoreContract.
+func testBlocklistContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "blocklist_entry"}
+	}
+	entry := func(typ, value string) models.BlocklistEntry {
+		return models.BlocklistEntry{Type: typ, Value: value, Reason: "chargebacks", CreatedBy: "analyst-1"}
+	}
+
+	t.Run("entries block until they expire", func(t *testing.T) {
+		s := newStore(t)
+		e, err := s.AddBlocklistEntry(ctx, entry(models.BlockEmail, "eve@example.com"), audit("blocklist.added"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.ID == 0 || e.CreatedAt.IsZero() || e.ExpiresAt != nil || e.Reason != "chargebacks" {
+			t.Fatalf("added %+v", e)
+		}
+		if _, err := s.AddBlocklistEntry(ctx, entry(models.BlockEmail, "eve@example.com"), audit("blocklist.added")); !errors.Is(err, ErrConflict) {
+			t.Fatalf("adding an active value again: got %v, want ErrConflict", err)
+		}
+		// The same value under another type is a different entry.
+		if _, err := s.AddBlocklistEntry(ctx, entry(models.BlockDevice, "eve@example.com"), audit("blocklist.added")); err != nil {
+			t.Fatal(err)
+		}
+
+		// Expiring in the future keeps the entry active until then.
+		later := base.Add(100 * 365 * 24 * time.Hour)
+		if e, err = s.ExpireBlocklistEntry(ctx, e.ID, later, audit("blocklist.expired")); err != nil || e.ExpiresAt == nil || !e.ExpiresAt.Equal(later) {
+			t.Fatalf("ExpireBlocklistEntry(later) = %+v, %v", e, err)
+		}
+		if e, err = s.ExpireBlocklistEntry(ctx, e.ID, time.Now().Add(-time.Second), audit("blocklist.expired")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.ExpireBlocklistEntry(ctx, e.ID, time.Now(), audit("blocklist.expired")); !errors.Is(err, ErrConflict) {
+			t.Errorf("expiring an expired entry: got %v, want ErrConflict", err)
+		}
+		if _, err := s.ExpireBlocklistEntry(ctx, e.ID+100, time.Now(), audit("blocklist.expired")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expiring a missing entry: got %v, want ErrNotFound", err)
+		}
+
+		active, err := s.ActiveBlocklist(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(active) != 1 || active[0].Type != models.BlockDevice {
+			t.Errorf("active entries %+v", active)
+		}
+
+		// Blocking the value again revives the expired entry.
+		again := entry(models.BlockEmail, "eve@example.com")
+		again.Reason = "fraud ring"
+		revived, err := s.AddBlocklistEntry(ctx, again, audit("blocklist.added"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if revived.ID != e.ID || revived.ExpiresAt != nil || revived.Reason != "fraud ring" {
+			t.Errorf("revived %+v", revived)
+		}
+
+		log := s.auditLog(t)
+		if len(log) != 5 || log[2].Action != "blocklist.expired" || log[2].TargetID != itoa64(e.ID) {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("ListBlocklist filters entries", func(t *testing.T) {
+		s := newStore(t)
+		for _, e := range []models.BlocklistEntry{
+			entry(models.BlockEmail, "eve@example.com"),
+			entry(models.BlockEmail, "mallory@example.org"),
+			entry(models.BlockIP, "203.0.113.0/24"),
+		} {
+			if _, err := s.AddBlocklistEntry(ctx, e, audit("blocklist.added")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expired, err := s.AddBlocklistEntry(ctx, entry(models.BlockEmail, "trent@example.com"), audit("blocklist.added"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.ExpireBlocklistEntry(ctx, expired.ID, time.Now().Add(-time.Second), audit("blocklist.expired")); err != nil {
+			t.Fatal(err)
+		}
+
+		values := func(f models.BlocklistFilter) []string {
+			t.Helper()
+			entries, err := s.ListBlocklist(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v []string
+			for _, e := range entries {
+				v = append(v, e.Value)
+			}
+			return v
+		}
+		if got := values(models.BlocklistFilter{Type: models.BlockEmail}); len(got) != 2 || got[0] != "mallory@example.org" {
+			t.Errorf("active emails %v", got)
+		}
+		if got := values(models.BlocklistFilter{Type: models.BlockEmail, IncludeExpired: true}); len(got) != 3 || got[0] != "trent@example.com" {
+			t.Errorf("all emails %v", got)
+		}
+		if got := values(models.BlocklistFilter{Search: "example.org"}); len(got) != 1 {
+			t.Errorf("search example.org %v", got)
+		}
+		if got := values(models.BlocklistFilter{Limit: 1}); len(got) != 1 || got[0] != "203.0.113.0/24" {
+			t.Errorf("limit 1 %v", got)
+		}
+	})
+
+	t.Run("ImportBlocklistEntries skips blocked values", func(t *testing.T) {
+		s := newStore(t)
+		if _, err := s.AddBlocklistEntry(ctx, entry(models.BlockCardBIN, "424242"), audit("blocklist.added")); err != nil {
+			t.Fatal(err)
+		}
+		expires := base.Add(100 * 365 * 24 * time.Hour)
+		withExpiry := entry(models.BlockDevice, "dev-1")
+		withExpiry.ExpiresAt = &expires
+		n, err := s.ImportBlocklistEntries(ctx, []models.BlocklistEntry{
+			entry(models.BlockCardBIN, "424242"),
+			entry(models.BlockCardBIN, "555555"),
+			withExpiry,
+		}, models.AuditEntry{Actor: "analyst-1", Action: "blocklist.imported", TargetType: "blocklist"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("imported %d entries, want 2", n)
+		}
+		entries, err := s.ListBlocklist(ctx, models.BlocklistFilter{Type: models.BlockDevice})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].ExpiresAt == nil || !entries[0].ExpiresAt.Equal(expires) {
+			t.Errorf("imported device entries %+v", entries)
+		}
+		if log := s.auditLog(t); len(log) != 2 || log[1].Action != "blocklist.imported" {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+}
@@ -0,0 +1,450 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// caseQuery selects fraud cases with their transaction summary; callers
+// append WHERE and ORDER BY clauses.
+const caseQuery = `
+	SELECT
+		c.id,
+		c.group_type,
+		c.group_key,
+		c.status,
+		COALESCE(c.assignee, ''),
+		COALESCE(c.resolution, ''),
+		c.created_at,
+		c.updated_at,
+		c.resolved_at,
+		t.count,
+		t.total
+	FROM fraud_cases c
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total
+		FROM fraud_case_transactions
+		WHERE case_id = c.id
+	) t ON true`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCase(row rowScanner) (models.FraudCase, error) {
+	var (
+		c          models.FraudCase
+		resolvedAt sql.NullTime
+	)
+	err := row.Scan(&c.ID, &c.GroupType, &c.GroupKey, &c.Status, &c.Assignee, &c.Resolution,
+		&c.CreatedAt, &c.UpdatedAt, &resolvedAt, &c.TransactionCount, &c.TotalAmount)
+	if err != nil {
+		return models.FraudCase{}, err
+	}
+	if resolvedAt.Valid {
+		c.ResolvedAt = &resolvedAt.Time
+	}
+	return c, nil
+}
+
+// withTx runs fn in a transaction, committing when it returns nil.
+func (r *AdminRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return r.wrapErr(err)
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return r.wrapErr(err)
+	}
+	return r.wrapErr(tx.Commit())
+}
+
+// touchCase bumps a case's updated_at, returning ErrNotFound when it does
+// not exist.
+func touchCase(ctx context.Context, tx *sql.Tx, id int64) error {
+	res, err := tx.ExecContext(ctx, `UPDATE fraud_cases SET updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("case %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+func (r *AdminRepository) FindOpenCase(ctx context.Context, groupType, groupKey string) (_ models.FraudCase, err error) {
+	ctx, span := tracing.StartDB(ctx, "FindOpenCase")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudCase{}, err
+	}
+	c, err := scanCase(r.db.QueryRowContext(ctx, caseQuery+`
+		WHERE c.group_type = $1 AND c.group_key = $2 AND c.status <> 'resolved'`, groupType, groupKey))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.FraudCase{}, fmt.Errorf("open %s case: %w", groupType, ErrNotFound)
+	}
+	if err != nil {
+		return models.FraudCase{}, r.wrapErr(err)
+	}
+	return c, nil
+}
+
+func (r *AdminRepository) OpenCase(ctx context.Context, groupType, groupKey string, audit models.AuditEntry) (_ models.FraudCase, created bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "OpenCase")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudCase{}, false, err
+	}
+
+	var id int64
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO fraud_cases (group_type, group_key)
+			VALUES ($1, $2)
+			ON CONFLICT (group_type, group_key) WHERE status <> 'resolved' DO NOTHING
+			RETURNING id`, groupType, groupKey).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		created = true
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.FraudCase{}, false, err
+	}
+	if !created {
+		c, err := r.FindOpenCase(ctx, groupType, groupKey)
+		return c, false, err
+	}
+	c, err := r.GetCase(ctx, id)
+	return c, true, err
+}
+
+func (r *AdminRepository) CaseForTransaction(ctx context.Context, transactionID int) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "CaseForTransaction")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return 0, err
+	}
+	var caseID int64
+	err = r.db.QueryRowContext(ctx, `SELECT case_id FROM fraud_case_transactions WHERE transaction_id = $1`, transactionID).Scan(&caseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("transaction %d: %w", transactionID, ErrNotFound)
+	}
+	if err != nil {
+		return 0, r.wrapErr(err)
+	}
+	return caseID, nil
+}
+
+func (r *AdminRepository) AddCaseTransaction(ctx context.Context, t models.CaseTransaction) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "AddCaseTransaction")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return false, err
+	}
+	var added bool
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := touchCase(ctx, tx, t.CaseID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO fraud_case_transactions
+				(transaction_id, case_id, merchant_id, amount, currency, customer_email, card_fingerprint, flagged_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (transaction_id) DO NOTHING`,
+			t.TransactionID, t.CaseID, t.MerchantID, t.Amount, t.Currency, t.CustomerEmail, t.CardFingerprint, t.FlaggedAt)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		added = n == 1
+		return err
+	})
+	return added, err
+}
+
+func (r *AdminRepository) ListCases(ctx context.Context, f models.CaseFilter) (_ []models.FraudCase, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListCases")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.Status != "" {
+		where = append(where, "c.status = "+arg(f.Status))
+	}
+	if f.Assignee != "" {
+		where = append(where, "c.assignee = "+arg(f.Assignee))
+	}
+	if !f.OpenedBefore.IsZero() {
+		where = append(where, "c.status <> 'resolved' AND c.created_at < "+arg(f.OpenedBefore))
+	}
+	query := caseQuery
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY c.created_at DESC, c.id DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	cases := []models.FraudCase{}
+	for rows.Next() {
+		c, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, rows.Err()
+}
+
+func (r *AdminRepository) GetCase(ctx context.Context, id int64) (_ models.FraudCase, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetCase")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudCase{}, err
+	}
+	c, err := scanCase(r.db.QueryRowContext(ctx, caseQuery+` WHERE c.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.FraudCase{}, fmt.Errorf("case %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.FraudCase{}, r.wrapErr(err)
+	}
+	return c, nil
+}
+
+func (r *AdminRepository) ListCaseTransactions(ctx context.Context, caseID int64) (_ []models.CaseTransaction, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListCaseTransactions")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT transaction_id, case_id, merchant_id, amount, currency, customer_email, card_fingerprint, flagged_at, added_at
+		FROM fraud_case_transactions
+		WHERE case_id = $1
+		ORDER BY flagged_at DESC, transaction_id DESC`, caseID)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	transactions := []models.CaseTransaction{}
+	for rows.Next() {
+		var t models.CaseTransaction
+		if err := rows.Scan(&t.TransactionID, &t.CaseID, &t.MerchantID, &t.Amount, &t.Currency,
+			&t.CustomerEmail, &t.CardFingerprint, &t.FlaggedAt, &t.AddedAt); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	return transactions, rows.Err()
+}
+
+func (r *AdminRepository) ListCaseComments(ctx context.Context, caseID int64) (_ []models.CaseComment, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListCaseComments")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, case_id, author, body, created_at
+		FROM fraud_case_comments
+		WHERE case_id = $1
+		ORDER BY created_at, id`, caseID)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	comments := []models.CaseComment{}
+	for rows.Next() {
+		var c models.CaseComment
+		if err := rows.Scan(&c.ID, &c.CaseID, &c.Author, &c.Body, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+func (r *AdminRepository) ListCaseAttachments(ctx context.Context, caseID int64) (_ []models.CaseAttachment, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListCaseAttachments")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, case_id, filename, content_type, size, uploaded_by, created_at
+		FROM fraud_case_attachments
+		WHERE case_id = $1
+		ORDER BY created_at, id`, caseID)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	attachments := []models.CaseAttachment{}
+	for rows.Next() {
+		var a models.CaseAttachment
+		if err := rows.Scan(&a.ID, &a.CaseID, &a.Filename, &a.ContentType, &a.Size, &a.UploadedBy, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *AdminRepository) GetCaseAttachment(ctx context.Context, caseID, id int64) (_ models.CaseAttachment, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetCaseAttachment")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.CaseAttachment{}, err
+	}
+	var a models.CaseAttachment
+	err = r.db.QueryRowContext(ctx, `
+		SELECT id, case_id, filename, content_type, size, uploaded_by, data, created_at
+		FROM fraud_case_attachments
+		WHERE case_id = $1 AND id = $2`, caseID, id,
+	).Scan(&a.ID, &a.CaseID, &a.Filename, &a.ContentType, &a.Size, &a.UploadedBy, &a.Data, &a.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.CaseAttachment{}, fmt.Errorf("attachment %d on case %d: %w", id, caseID, ErrNotFound)
+	}
+	if err != nil {
+		return models.CaseAttachment{}, r.wrapErr(err)
+	}
+	return a, nil
+}
+
+func (r *AdminRepository) AssignCase(ctx context.Context, id int64, assignee string, audit models.AuditEntry) (_ models.FraudCase, err error) {
+	ctx, span := tracing.StartDB(ctx, "AssignCase")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudCase{}, err
+	}
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := touchCase(ctx, tx, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE fraud_cases SET assignee = NULLIF($2, '') WHERE id = $1`, id, assignee); err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.FraudCase{}, err
+	}
+	return r.GetCase(ctx, id)
+}
+
+func (r *AdminRepository) SetCaseStatus(ctx context.Context, id int64, status, resolution string, audit models.AuditEntry) (_ models.FraudCase, err error) {
+	ctx, span := tracing.StartDB(ctx, "SetCaseStatus")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudCase{}, err
+	}
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := touchCase(ctx, tx, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE fraud_cases SET
+				status = $2::text,
+				resolution = CASE WHEN $2::text = 'resolved' THEN $3 END,
+				resolved_at = CASE WHEN $2::text = 'resolved' THEN NOW() END
+			WHERE id = $1`, id, status, resolution)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("case %d: another unresolved case exists for its group: %w", id, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.FraudCase{}, err
+	}
+	return r.GetCase(ctx, id)
+}
+
+func (r *AdminRepository) AddCaseComment(ctx context.Context, c models.CaseComment, audit models.AuditEntry) (_ models.CaseComment, err error) {
+	ctx, span := tracing.StartDB(ctx, "AddCaseComment")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.CaseComment{}, err
+	}
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := touchCase(ctx, tx, c.CaseID); err != nil {
+			return err
+		}
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO fraud_case_comments (case_id, author, body)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at`, c.CaseID, c.Author, c.Body).Scan(&c.ID, &c.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, c.CaseID))
+	})
+	if err != nil {
+		return models.CaseComment{}, err
+	}
+	return c, nil
+}
+
+func (r *AdminRepository) AddCaseAttachment(ctx context.Context, a models.CaseAttachment, audit models.AuditEntry) (_ models.CaseAttachment, err error) {
+	ctx, span := tracing.StartDB(ctx, "AddCaseAttachment")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.CaseAttachment{}, err
+	}
+	a.Size = int64(len(a.Data))
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := touchCase(ctx, tx, a.CaseID); err != nil {
+			return err
+		}
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO fraud_case_attachments (case_id, filename, content_type, size, uploaded_by, data)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at`, a.CaseID, a.Filename, a.ContentType, a.Size, a.UploadedBy, a.Data).Scan(&a.ID, &a.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, a.CaseID))
+	})
+	if err != nil {
+		return models.CaseAttachment{}, err
+	}
+	a.Data = nil
+	return a, nil
+}
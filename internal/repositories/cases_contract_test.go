@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testFraudCaseContract runs the fraud case behaviour every AdminStore must
+// share; it is called from testAdminStoreContract.
+func testFraudCaseContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "fraud_case"}
+	}
+	flagged := func(caseID int64, txID int, amount int64, flaggedAt time.Time) models.CaseTransaction {
+		return models.CaseTransaction{
+			TransactionID: txID, CaseID: caseID, MerchantID: 1, Amount: amount, Currency: "NGN",
+			CustomerEmail: "buyer@example.com", CardFingerprint: "fp-1", FlaggedAt: flaggedAt,
+		}
+	}
+
+	t.Run("OpenCase reuses the unresolved case for a group", func(t *testing.T) {
+		s := newStore(t)
+		if _, err := s.FindOpenCase(ctx, models.CaseGroupCardFingerprint, "fp-1"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("FindOpenCase on empty store: got %v, want ErrNotFound", err)
+		}
+		c, created, err := s.OpenCase(ctx, models.CaseGroupCardFingerprint, "fp-1", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !created || c.ID == 0 || c.Status != models.CaseStatusOpen || c.GroupKey != "fp-1" || c.CreatedAt.IsZero() {
+			t.Fatalf("got %+v created=%v", c, created)
+		}
+		again, created, err := s.OpenCase(ctx, models.CaseGroupCardFingerprint, "fp-1", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created || again.ID != c.ID {
+			t.Fatalf("second OpenCase created case %d, want existing %d", again.ID, c.ID)
+		}
+		found, err := s.FindOpenCase(ctx, models.CaseGroupCardFingerprint, "fp-1")
+		if err != nil || found.ID != c.ID {
+			t.Fatalf("FindOpenCase: got %+v, %v", found, err)
+		}
+
+		entries := s.auditLog(t)
+		if len(entries) != 1 || entries[0].Action != "fraud_case.opened" || entries[0].TargetID != itoa64(c.ID) {
+			t.Fatalf("got audit %+v, want one opened entry targeting the case", entries)
+		}
+	})
+
+	t.Run("AddCaseTransaction", func(t *testing.T) {
+		s := newStore(t)
+		c, _, err := s.OpenCase(ctx, models.CaseGroupCardFingerprint, "fp-1", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, _, err := s.OpenCase(ctx, models.CaseGroupMerchant, "1", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range []models.CaseTransaction{flagged(c.ID, 10, 5000, base), flagged(c.ID, 11, 2500, base.Add(time.Hour))} {
+			if added, err := s.AddCaseTransaction(ctx, tx); err != nil || !added {
+				t.Fatalf("AddCaseTransaction(%d): added=%v err=%v", tx.TransactionID, added, err)
+			}
+		}
+		if added, err := s.AddCaseTransaction(ctx, flagged(other.ID, 10, 5000, base)); err != nil || added {
+			t.Fatalf("adding a transaction already in a case: added=%v err=%v", added, err)
+		}
+		if _, err := s.AddCaseTransaction(ctx, flagged(999, 12, 1, base)); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("adding to a missing case: got %v, want ErrNotFound", err)
+		}
+
+		if id, err := s.CaseForTransaction(ctx, 10); err != nil || id != c.ID {
+			t.Fatalf("CaseForTransaction: got %d, %v", id, err)
+		}
+		if _, err := s.CaseForTransaction(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("CaseForTransaction on unknown transaction: got %v", err)
+		}
+
+		got, err := s.GetCase(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.TransactionCount != 2 || got.TotalAmount != 7500 {
+			t.Errorf("summary = %d transactions %d total, want 2 and 7500", got.TransactionCount, got.TotalAmount)
+		}
+		transactions, err := s.ListCaseTransactions(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transactions) != 2 || transactions[0].TransactionID != 11 || transactions[1].CardFingerprint != "fp-1" {
+			t.Errorf("got %+v, want transactions 11 and 10", transactions)
+		}
+	})
+
+	t.Run("ListCases filters", func(t *testing.T) {
+		s := newStore(t)
+		first, _, _ := s.OpenCase(ctx, models.CaseGroupCustomerEmail, "a@example.com", audit("fraud_case.opened"))
+		second, _, _ := s.OpenCase(ctx, models.CaseGroupCustomerEmail, "b@example.com", audit("fraud_case.opened"))
+		third, _, err := s.OpenCase(ctx, models.CaseGroupCustomerEmail, "c@example.com", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.AssignCase(ctx, second.ID, "analyst-2", audit("fraud_case.assigned")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.SetCaseStatus(ctx, third.ID, models.CaseStatusResolved, "false positive", audit("fraud_case.resolved")); err != nil {
+			t.Fatal(err)
+		}
+
+		check := func(name string, f models.CaseFilter, want ...int64) {
+			t.Helper()
+			cases, err := s.ListCases(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, c := range cases {
+				got = append(got, c.ID)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: got cases %v, want %v", name, got, want)
+			}
+		}
+		check("all", models.CaseFilter{}, third.ID, second.ID, first.ID)
+		check("limit", models.CaseFilter{Limit: 1}, third.ID)
+		check("status", models.CaseFilter{Status: models.CaseStatusOpen}, second.ID, first.ID)
+		check("assignee", models.CaseFilter{Assignee: "analyst-2"}, second.ID)
+		check("opened before", models.CaseFilter{OpenedBefore: time.Now().Add(time.Hour)}, second.ID, first.ID)
+		check("opened before none", models.CaseFilter{OpenedBefore: time.Now().Add(-time.Hour)})
+	})
+
+	t.Run("SetCaseStatus", func(t *testing.T) {
+		s := newStore(t)
+		c, _, err := s.OpenCase(ctx, models.CaseGroupMerchant, "7", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.SetCaseStatus(ctx, c.ID, models.CaseStatusInvestigating, "", audit("fraud_case.investigating"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != models.CaseStatusInvestigating || got.ResolvedAt != nil {
+			t.Fatalf("got %+v", got)
+		}
+		got, err = s.SetCaseStatus(ctx, c.ID, models.CaseStatusResolved, "merchant confirmed refunds", audit("fraud_case.resolved"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Resolution != "merchant confirmed refunds" || got.ResolvedAt == nil {
+			t.Fatalf("resolved case: got %+v", got)
+		}
+
+		// A resolved case frees its group for a new case, which blocks reopening.
+		newer, created, err := s.OpenCase(ctx, models.CaseGroupMerchant, "7", audit("fraud_case.opened"))
+		if err != nil || !created {
+			t.Fatalf("OpenCase after resolving: created=%v err=%v", created, err)
+		}
+		if _, err := s.SetCaseStatus(ctx, c.ID, models.CaseStatusOpen, "", audit("fraud_case.open")); !errors.Is(err, ErrConflict) {
+			t.Fatalf("reopening with another open case: got %v, want ErrConflict", err)
+		}
+		if _, err := s.SetCaseStatus(ctx, newer.ID, models.CaseStatusResolved, "duplicate", audit("fraud_case.resolved")); err != nil {
+			t.Fatal(err)
+		}
+		got, err = s.SetCaseStatus(ctx, c.ID, models.CaseStatusOpen, "", audit("fraud_case.open"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != models.CaseStatusOpen || got.Resolution != "" || got.ResolvedAt != nil {
+			t.Fatalf("reopened case: got %+v", got)
+		}
+
+		if _, err := s.SetCaseStatus(ctx, 999, models.CaseStatusResolved, "", audit("fraud_case.resolved")); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("missing case: got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("comments and attachments", func(t *testing.T) {
+		s := newStore(t)
+		c, _, err := s.OpenCase(ctx, models.CaseGroupMerchant, "7", audit("fraud_case.opened"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, body := range []string{"called the merchant", "awaiting documents"} {
+			if _, err := s.AddCaseComment(ctx, models.CaseComment{CaseID: c.ID, Author: "analyst-1", Body: body}, audit("fraud_case.commented")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		comments, err := s.ListCaseComments(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(comments) != 2 || comments[0].Body != "called the merchant" || comments[0].ID == 0 || comments[0].CreatedAt.IsZero() {
+			t.Fatalf("got comments %+v", comments)
+		}
+		if _, err := s.AddCaseComment(ctx, models.CaseComment{CaseID: 999, Author: "a", Body: "b"}, audit("fraud_case.commented")); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("comment on missing case: got %v, want ErrNotFound", err)
+		}
+
+		att, err := s.AddCaseAttachment(ctx, models.CaseAttachment{
+			CaseID: c.ID, Filename: "statement.pdf", ContentType: "application/pdf", UploadedBy: "analyst-1", Data: []byte("%PDF-1.4"),
+		}, audit("fraud_case.attachment_added"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if att.ID == 0 || att.Size != 8 || att.Data != nil {
+			t.Fatalf("got attachment %+v, want ID and size set without data", att)
+		}
+		listed, err := s.ListCaseAttachments(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listed) != 1 || listed[0].Filename != "statement.pdf" || listed[0].Data != nil {
+			t.Fatalf("got attachments %+v", listed)
+		}
+		full, err := s.GetCaseAttachment(ctx, c.ID, att.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(full.Data) != "%PDF-1.4" || full.ContentType != "application/pdf" {
+			t.Fatalf("got %+v", full)
+		}
+		if _, err := s.GetCaseAttachment(ctx, c.ID+1, att.ID); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("attachment on another case: got %v, want ErrNotFound", err)
+		}
+		if n := len(s.auditLog(t)); n != 4 {
+			t.Errorf("got %d audit entries, want 4", n)
+		}
+	})
+}
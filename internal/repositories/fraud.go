@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// RecordFraudDecision stores a review decision together with its audit log
+// entry and returns it with its ID and timestamp set. The decision row is
+// inserted first, so a concurrent final decision on the same transaction
+// waits on the unique index and then fails with ErrConflict; apply, when set,
+// runs while the row is held and its error is returned as is, storing
+// nothing.
+func (r *AdminRepository) RecordFraudDecision(ctx context.Context, d models.FraudDecision, apply func(ctx context.Context) error) (_ models.FraudDecision, err error) {
+	ctx, span := tracing.StartDB(ctx, "RecordFraudDecision")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudDecision{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.FraudDecision{}, r.wrapErr(err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO fraud_reviews (transaction_id, decision, reason, reviewer, request_id)
+		SELECT $1, $2, $3, $4, NULLIF($5, '')
+		WHERE NOT EXISTS (
+			SELECT 1 FROM fraud_reviews
+			WHERE transaction_id = $1 AND decision IN ('approved', 'declined'))
+		RETURNING id, created_at`,
+		d.TransactionID, d.Decision, d.Reason, d.Reviewer, d.RequestID,
+	).Scan(&d.ID, &d.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) || isUniqueViolation(err) {
+		return models.FraudDecision{}, fmt.Errorf("transaction %d already has a final decision: %w", d.TransactionID, ErrConflict)
+	}
+	if err != nil {
+		return models.FraudDecision{}, r.wrapErr(err)
+	}
+	if apply != nil {
+		if err := apply(ctx); err != nil {
+			return models.FraudDecision{}, err
+		}
+	}
+	if err := insertAudit(ctx, tx, fraudDecisionAudit(d)); err != nil {
+		return models.FraudDecision{}, r.wrapErr(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return models.FraudDecision{}, r.wrapErr(err)
+	}
+	return d, nil
+}
+
+// ListFraudDecisions returns the decisions on a transaction, newest first.
+func (r *AdminRepository) ListFraudDecisions(ctx context.Context, transactionID int) (_ []models.FraudDecision, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListFraudDecisions")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, transaction_id, decision, reason, reviewer, request_id, created_at
+		FROM fraud_reviews
+		WHERE transaction_id = $1
+		ORDER BY created_at DESC, id DESC`, transactionID)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	decisions := []models.FraudDecision{}
+	for rows.Next() {
+		var (
+			d         models.FraudDecision
+			requestID sql.NullString
+		)
+		if err := rows.Scan(&d.ID, &d.TransactionID, &d.Decision, &d.Reason, &d.Reviewer, &requestID, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.RequestID = requestID.String
+		decisions = append(decisions, d)
+	}
+	return decisions, rows.Err()
+}
+
+// fraudDecisionAudit describes a recorded decision for the audit log.
+func fraudDecisionAudit(d models.FraudDecision) models.AuditEntry {
+	return models.AuditEntry{
+		Actor:      d.Reviewer,
+		Action:     "fraud_review." + d.Decision,
+		TargetType: "transaction",
+		TargetID:   strconv.Itoa(d.TransactionID),
+		Details:    map[string]interface{}{"decision_id": d.ID, "reason": d.Reason},
+		RequestID:  d.RequestID,
+		CreatedAt:  d.CreatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// idempotencySweepInterval is how often a replica deletes expired records.
+const idempotencySweepInterval = time.Minute
+
+// IdempotencyStore keeps idempotency records in Postgres, so a retried
+// request is replayed after a restart and on any replica, and a duplicate
+// sent to another replica while the first runs is rejected.
+type IdempotencyStore struct {
+	repo      *AdminRepository
+	lastSweep atomic.Int64
+}
+
+// NewIdempotencyStore stores idempotency records through repo.
+func NewIdempotencyStore(repo *AdminRepository) *IdempotencyStore {
+	return &IdempotencyStore{repo: repo}
+}
+
+// Reserve inserts a pending record for rec.Key, replacing an expired one.
+// When the key is held it returns the holding record, with a zero status
+// code while that request is still running.
+func (s *IdempotencyStore) Reserve(ctx context.Context, rec *middleware.IdempotencyRecord, lease time.Duration) (_ *middleware.IdempotencyRecord, err error) {
+	ctx, span := tracing.StartDB(ctx, "ReserveIdempotencyKey")
+	defer tracing.End(span, &err)
+	if err := s.repo.checkAvailable(); err != nil {
+		return nil, err
+	}
+	res, err := s.repo.db.ExecContext(ctx, `
+		INSERT INTO admin_idempotency_keys (key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = '',
+			body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE admin_idempotency_keys.expires_at <= NOW()`,
+		rec.Key, rec.RequestHash, rec.CreatedAt, time.Now().Add(lease))
+	if err != nil {
+		return nil, s.repo.wrapErr(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 1 {
+		s.sweep(ctx)
+		return nil, nil
+	}
+
+	existing := middleware.IdempotencyRecord{Key: rec.Key}
+	var status sql.NullInt64
+	err = s.repo.db.QueryRowContext(ctx, `
+		SELECT request_hash, status_code, content_type, body, created_at
+		FROM admin_idempotency_keys
+		WHERE key = $1`, rec.Key).
+		Scan(&existing.RequestHash, &status, &existing.ContentType, &existing.Body, &existing.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The holder released the key in the meantime; report it as still
+		// running so the client retries.
+		return &middleware.IdempotencyRecord{Key: rec.Key, RequestHash: rec.RequestHash}, nil
+	}
+	if err != nil {
+		return nil, s.repo.wrapErr(err)
+	}
+	existing.StatusCode = int(status.Int64)
+	return &existing, nil
+}
+
+// Complete stores the response of a pending record, keeping it until ttl has
+// elapsed since the request was made.
+func (s *IdempotencyStore) Complete(ctx context.Context, rec *middleware.IdempotencyRecord, ttl time.Duration) (err error) {
+	ctx, span := tracing.StartDB(ctx, "CompleteIdempotencyKey")
+	defer tracing.End(span, &err)
+	if err := s.repo.checkAvailable(); err != nil {
+		return err
+	}
+	_, err = s.repo.db.ExecContext(ctx, `
+		UPDATE admin_idempotency_keys
+		SET status_code = $3, content_type = $4, body = $5, expires_at = $6
+		WHERE key = $1 AND request_hash = $2 AND status_code IS NULL`,
+		rec.Key, rec.RequestHash, rec.StatusCode, rec.ContentType, rec.Body, rec.CreatedAt.Add(ttl))
+	if err != nil {
+		return s.repo.wrapErr(err)
+	}
+	return nil
+}
+
+// Release deletes a pending record so the key can be retried.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ReleaseIdempotencyKey")
+	defer tracing.End(span, &err)
+	if err := s.repo.checkAvailable(); err != nil {
+		return err
+	}
+	_, err = s.repo.db.ExecContext(ctx, `DELETE FROM admin_idempotency_keys WHERE key = $1 AND status_code IS NULL`, key)
+	if err != nil {
+		return s.repo.wrapErr(err)
+	}
+	return nil
+}
+
+// sweep deletes expired records, at most once per idempotencySweepInterval.
+func (s *IdempotencyStore) sweep(ctx context.Context) {
+	now := time.Now()
+	last := s.lastSweep.Load()
+	if now.Sub(time.Unix(0, last)) < idempotencySweepInterval || !s.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	res, err := s.repo.db.ExecContext(ctx, `DELETE FROM admin_idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to delete expired idempotency records", "error", err)
+		return
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		slog.DebugContext(ctx, "deleted expired idempotency records", "deleted", n)
+	}
+}
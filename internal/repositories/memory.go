@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// MemoryStore is an in-memory AdminStore for tests and local development.
+// Merchants carry their balance directly; a merchant with no currency is
+// treated as having no balance row.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	merchants    []models.Merchant
+	transactions []models.Transaction
+	payouts      []models.Payout
+	decisions    []models.FraudDecision
+	deciding     map[int]bool
+	audit        []models.AuditEntry
+	fraud        memoryCases
+	rules        memoryRules
+	blocklist    []models.BlocklistEntry
+	risk         map[int]models.RiskScore
+	alerts       []models.MerchantAlert
+	policies     memoryPolicies
+	payoutReview memoryPayoutReview
+	settlement   []models.SettlementHold
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// AddMerchant stores a merchant.
+func (s *MemoryStore) AddMerchant(m models.Merchant) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merchants = append(s.merchants, m)
+}
+
+// AddTransaction stores a payment. Like the transactions table it is only
+// listed while its merchant exists.
+func (s *MemoryStore) AddTransaction(t models.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions = append(s.transactions, t)
+}
+
+// AddPayout stores a payout. Like the payouts table it is only listed while
+// its merchant exists.
+func (s *MemoryStore) AddPayout(p models.Payout) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payouts = append(s.payouts, p)
+}
+
+// merchant returns m as listed, with its balance defaulted and its risk
+// score summary; callers hold s.mu.
+func (s *MemoryStore) merchant(m models.Merchant) models.Merchant {
+	if m.Currency == "" {
+		m.TotalVolume, m.Currency = 0, "NGN"
+	}
+	m.KYCVerifiedAt, m.Risk = nil, nil
+	if score, ok := s.risk[m.ID]; ok {
+		score.Factors = nil
+		m.Risk = &score
+	}
+	return m
+}
+
+func (s *MemoryStore) ListMerchants(ctx context.Context, f models.MerchantFilter) ([]models.Merchant, error) {
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var merchants []models.Merchant
+	for _, m := range s.merchants {
+		m = s.merchant(m)
+		if f.RiskLevel != "" && (m.Risk == nil || m.Risk.Level != f.RiskLevel) {
+			continue
+		}
+		if f.MinRiskScore > 0 && (m.Risk == nil || m.Risk.Score < f.MinRiskScore) {
+			continue
+		}
+		merchants = append(merchants, m)
+	}
+	sort.SliceStable(merchants, func(i, j int) bool { return merchants[i].CreatedAt.After(merchants[j].CreatedAt) })
+	if f.SortByRisk {
+		sort.SliceStable(merchants, func(i, j int) bool {
+			a, b := merchants[i].Risk, merchants[j].Risk
+			return a != nil && (b == nil || a.Score > b.Score)
+		})
+	}
+	return truncate(merchants, f.Limit), nil
+}
+
+func (s *MemoryStore) GetMerchant(ctx context.Context, id int) (models.Merchant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, m := range s.merchants {
+		if m.ID == id {
+			m = s.merchant(m)
+			if m.Risk != nil {
+				m.Risk.Factors = append([]models.RiskFactor(nil), s.risk[id].Factors...)
+			}
+			return m, nil
+		}
+	}
+	return models.Merchant{}, fmt.Errorf("merchant %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) UpdateMerchantStatus(ctx context.Context, id int, status string, audit models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.merchants {
+		if s.merchants[i].ID == id {
+			s.merchants[i].Status = status
+			s.merchants[i].UpdatedAt = time.Now().UTC()
+			s.appendAudit(auditTarget(audit, int64(id)))
+			return nil
+		}
+	}
+	return fmt.Errorf("merchant %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) GetStats(ctx context.Context) (models.PlatformStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stats models.PlatformStats
+	monthStart := time.Now().Add(-30 * 24 * time.Hour)
+	successful := 0
+	for _, m := range s.merchants {
+		stats.TotalMerchants++
+		if m.Status == "active" {
+			stats.ActiveMerchants++
+		}
+		if m.KYCStatus == "pending" || m.KYCStatus == "not_started" || m.Status == "inactive" {
+			stats.PendingKYC++
+		}
+		for _, t := range s.transactions {
+			if t.MerchantID != m.ID {
+				continue
+			}
+			stats.TotalTransactions++
+			if t.Status == "successful" {
+				successful++
+				stats.TotalVolume += t.Amount
+				if !t.CreatedAt.Before(monthStart) {
+					stats.MonthlyVolume += t.Amount
+				}
+			}
+		}
+	}
+	if stats.TotalTransactions > 0 {
+		stats.SuccessRate = float64(successful) / float64(stats.TotalTransactions) * 100
+	}
+	stats.GeneratedAt = time.Now().UTC()
+	return stats, nil
+}
+
+func (s *MemoryStore) ListTransactions(ctx context.Context, limit int) ([]models.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	transactions := []models.Transaction{}
+	for _, t := range s.transactions {
+		if name, ok := s.businessName(t.MerchantID); ok {
+			t.MerchantName = name
+			transactions = append(transactions, t)
+		}
+	}
+	sort.SliceStable(transactions, func(i, j int) bool { return transactions[i].CreatedAt.After(transactions[j].CreatedAt) })
+	return truncate(transactions, limit), nil
+}
+
+func (s *MemoryStore) RecordFraudDecision(ctx context.Context, d models.FraudDecision, apply func(ctx context.Context) error) (models.FraudDecision, error) {
+	s.mu.Lock()
+	closed := s.deciding[d.TransactionID]
+	for _, existing := range s.decisions {
+		if existing.TransactionID == d.TransactionID && existing.Final() {
+			closed = true
+		}
+	}
+	if closed {
+		s.mu.Unlock()
+		return models.FraudDecision{}, fmt.Errorf("transaction %d already has a final decision: %w", d.TransactionID, ErrConflict)
+	}
+	// Reserve the transaction while apply runs, as the unique index does.
+	if d.Final() {
+		if s.deciding == nil {
+			s.deciding = make(map[int]bool)
+		}
+		s.deciding[d.TransactionID] = true
+	}
+	s.mu.Unlock()
+
+	var err error
+	if apply != nil {
+		err = apply(ctx)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deciding, d.TransactionID)
+	if err != nil {
+		return models.FraudDecision{}, err
+	}
+	d.ID = int64(len(s.decisions) + 1)
+	d.CreatedAt = time.Now().UTC()
+	s.decisions = append(s.decisions, d)
+	s.appendAudit(fraudDecisionAudit(d))
+	return d, nil
+}
+
+func (s *MemoryStore) ListFraudDecisions(ctx context.Context, transactionID int) ([]models.FraudDecision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	decisions := []models.FraudDecision{}
+	for i := len(s.decisions) - 1; i >= 0; i-- {
+		if s.decisions[i].TransactionID == transactionID {
+			decisions = append(decisions, s.decisions[i])
+		}
+	}
+	return decisions, nil
+}
+
+// AuditLog returns every audit log entry, oldest first.
+func (s *MemoryStore) AuditLog() []models.AuditEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.AuditEntry(nil), s.audit...)
+}
+
+// appendAudit records an audit log entry; callers hold s.mu.
+func (s *MemoryStore) appendAudit(e models.AuditEntry) {
+	e.ID = int64(len(s.audit) + 1)
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	s.audit = append(s.audit, e)
+}
+
+// businessName joins a row to its merchant; callers hold s.mu.
+func (s *MemoryStore) businessName(merchantID int) (string, bool) {
+	for _, m := range s.merchants {
+		if m.ID == merchantID {
+			return m.BusinessName, true
+		}
+	}
+	return "", false
+}
+
+// truncate applies a SQL LIMIT to items.
+func truncate[T any](items []T, limit int) []T {
+	limit = max(limit, 0)
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func (s *MemoryStore) MerchantMetrics(ctx context.Context, q models.MetricsQuery) ([]models.MerchantMetrics, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type key struct {
+		merchant int
+		currency string
+		period   int
+	}
+	buckets := map[key]*models.MerchantMetrics{}
+	for _, t := range s.transactions {
+		if t.CreatedAt.After(q.End) || !t.CreatedAt.After(q.End.Add(-q.Period*time.Duration(q.Periods))) {
+			continue
+		}
+		k := key{t.MerchantID, t.Currency, int(q.End.Sub(t.CreatedAt) / q.Period)}
+		m, ok := buckets[k]
+		if !ok {
+			m = &models.MerchantMetrics{MerchantID: k.merchant, Currency: k.currency, Period: k.period}
+			buckets[k] = m
+		}
+		m.Transactions++
+		if t.Status == "successful" {
+			m.Successful++
+			m.Volume += t.Amount
+		}
+	}
+
+	metrics := make([]models.MerchantMetrics, 0, len(buckets))
+	for _, m := range buckets {
+		metrics = append(metrics, *m)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].MerchantID != metrics[j].MerchantID {
+			return metrics[i].MerchantID < metrics[j].MerchantID
+		}
+		if metrics[i].Currency != metrics[j].Currency {
+			return metrics[i].Currency < metrics[j].Currency
+		}
+		return metrics[i].Period < metrics[j].Period
+	})
+	return metrics, nil
+}
+
+func (s *MemoryStore) RaiseAlert(ctx context.Context, a models.MerchantAlert) (models.MerchantAlert, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.alerts {
+		if existing.MerchantID == a.MerchantID && existing.Type == a.Type && existing.Status != models.AlertStatusResolved {
+			return models.MerchantAlert{}, false, nil
+		}
+	}
+	now := time.Now().UTC()
+	a.ID = int64(len(s.alerts) + 1)
+	a.Status = models.AlertStatusOpen
+	a.CreatedAt, a.UpdatedAt = now, now
+	a.AcknowledgedBy, a.AcknowledgedAt, a.ResolvedBy, a.ResolvedAt, a.Resolution = "", nil, "", nil, ""
+	s.alerts = append(s.alerts, a)
+	return a, true, nil
+}
+
+func (s *MemoryStore) ListAlerts(ctx context.Context, f models.AlertFilter) ([]models.MerchantAlert, error) {
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var alerts []models.MerchantAlert
+	for _, a := range s.alerts {
+		if (f.Status == "" || a.Status == f.Status) && (f.Type == "" || a.Type == f.Type) && (f.MerchantID == 0 || a.MerchantID == f.MerchantID) {
+			alerts = append(alerts, a)
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if !alerts[i].CreatedAt.Equal(alerts[j].CreatedAt) {
+			return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+		}
+		return alerts[i].ID > alerts[j].ID
+	})
+	return truncate(alerts, f.Limit), nil
+}
+
+// findAlert returns the index of an alert; callers hold s.mu.
+func (s *MemoryStore) findAlert(id int64) (int, error) {
+	for i, a := range s.alerts {
+		if a.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("alert %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) GetAlert(ctx context.Context, id int64) (models.MerchantAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, err := s.findAlert(id)
+	if err != nil {
+		return models.MerchantAlert{}, err
+	}
+	return s.alerts[i], nil
+}
+
+func (s *MemoryStore) AcknowledgeAlert(ctx context.Context, id int64, by string, audit models.AuditEntry) (models.MerchantAlert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findAlert(id)
+	if err != nil {
+		return models.MerchantAlert{}, err
+	}
+	a := &s.alerts[i]
+	if a.Status != models.AlertStatusOpen {
+		return models.MerchantAlert{}, fmt.Errorf("alert %d is %s: %w", id, a.Status, ErrConflict)
+	}
+	now := time.Now().UTC()
+	a.Status, a.AcknowledgedBy, a.AcknowledgedAt, a.UpdatedAt = models.AlertStatusAcknowledged, by, &now, now
+	s.appendAudit(auditTarget(audit, id))
+	return *a, nil
+}
+
+func (s *MemoryStore) ResolveAlert(ctx context.Context, id int64, by, resolution string, audit models.AuditEntry) (models.MerchantAlert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findAlert(id)
+	if err != nil {
+		return models.MerchantAlert{}, err
+	}
+	a := &s.alerts[i]
+	if a.Status == models.AlertStatusResolved {
+		return models.MerchantAlert{}, fmt.Errorf("alert %d is %s: %w", id, a.Status, ErrConflict)
+	}
+	now := time.Now().UTC()
+	a.Status, a.ResolvedBy, a.ResolvedAt, a.Resolution, a.UpdatedAt = models.AlertStatusResolved, by, &now, resolution, now
+	s.appendAudit(auditTarget(audit, id))
+	return *a, nil
+}
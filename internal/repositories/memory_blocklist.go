@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// addBlocklistEntry stores e or revives the expired entry for its value,
+// reporting false while an active entry blocks it; callers hold s.mu.
+func (s *MemoryStore) addBlocklistEntry(e models.BlocklistEntry, now time.Time) (models.BlocklistEntry, bool) {
+	e.CreatedAt, e.UpdatedAt = now, now
+	for i, existing := range s.blocklist {
+		if existing.Type != e.Type || existing.Value != e.Value {
+			continue
+		}
+		if existing.Active(now) {
+			return models.BlocklistEntry{}, false
+		}
+		e.ID = existing.ID
+		s.blocklist[i] = e
+		return e, true
+	}
+	e.ID = int64(len(s.blocklist) + 1)
+	s.blocklist = append(s.blocklist, e)
+	return e, true
+}
+
+func (s *MemoryStore) AddBlocklistEntry(ctx context.Context, e models.BlocklistEntry, audit models.AuditEntry) (models.BlocklistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added, ok := s.addBlocklistEntry(e, time.Now().UTC())
+	if !ok {
+		return models.BlocklistEntry{}, fmt.Errorf("blocklist %s %q: %w", e.Type, e.Value, ErrConflict)
+	}
+	s.appendAudit(auditTarget(audit, added.ID))
+	return added, nil
+}
+
+func (s *MemoryStore) ImportBlocklistEntries(ctx context.Context, entries []models.BlocklistEntry, audit models.AuditEntry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	added := 0
+	for _, e := range entries {
+		if _, ok := s.addBlocklistEntry(e, now); ok {
+			added++
+		}
+	}
+	s.appendAudit(audit)
+	return added, nil
+}
+
+func (s *MemoryStore) ExpireBlocklistEntry(ctx context.Context, id int64, at time.Time, audit models.AuditEntry) (models.BlocklistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	for i := range s.blocklist {
+		e := &s.blocklist[i]
+		if e.ID != id {
+			continue
+		}
+		if !e.Active(now) {
+			return models.BlocklistEntry{}, fmt.Errorf("blocklist entry %d already expired: %w", id, ErrConflict)
+		}
+		at := at.UTC()
+		e.ExpiresAt = &at
+		e.UpdatedAt = now
+		s.appendAudit(auditTarget(audit, id))
+		return *e, nil
+	}
+	return models.BlocklistEntry{}, fmt.Errorf("blocklist entry %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) ListBlocklist(ctx context.Context, f models.BlocklistFilter) ([]models.BlocklistEntry, error) {
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	entries := []models.BlocklistEntry{}
+	for _, e := range s.blocklist {
+		if (f.Type != "" && e.Type != f.Type) || !strings.Contains(e.Value, f.Search) || (!f.IncludeExpired && !e.Active(now)) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	return truncate(entries, f.Limit), nil
+}
+
+func (s *MemoryStore) ActiveBlocklist(ctx context.Context) ([]models.BlocklistEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	entries := []models.BlocklistEntry{}
+	for _, e := range s.blocklist {
+		if e.Active(now) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// memoryCases holds the MemoryStore's fraud case tables.
+type memoryCases struct {
+	cases        []models.FraudCase
+	transactions []models.CaseTransaction
+	comments     []models.CaseComment
+	attachments  []models.CaseAttachment
+}
+
+// findCase returns the index of a case; callers hold s.mu.
+func (s *MemoryStore) findCase(id int64) (int, error) {
+	for i, c := range s.fraud.cases {
+		if c.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("case %d: %w", id, ErrNotFound)
+}
+
+// withSummary fills in a case's transaction summary; callers hold s.mu.
+func (s *MemoryStore) withSummary(c models.FraudCase) models.FraudCase {
+	c.TransactionCount, c.TotalAmount = 0, 0
+	for _, t := range s.fraud.transactions {
+		if t.CaseID == c.ID {
+			c.TransactionCount++
+			c.TotalAmount += t.Amount
+		}
+	}
+	return c
+}
+
+// touchCase bumps a case's UpdatedAt and returns its index; callers hold s.mu.
+func (s *MemoryStore) touchCase(id int64) (int, error) {
+	i, err := s.findCase(id)
+	if err != nil {
+		return 0, err
+	}
+	s.fraud.cases[i].UpdatedAt = time.Now().UTC()
+	return i, nil
+}
+
+func (s *MemoryStore) FindOpenCase(ctx context.Context, groupType, groupKey string) (models.FraudCase, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.findOpenCase(groupType, groupKey)
+}
+
+func (s *MemoryStore) findOpenCase(groupType, groupKey string) (models.FraudCase, error) {
+	for _, c := range s.fraud.cases {
+		if c.GroupType == groupType && c.GroupKey == groupKey && c.Status != models.CaseStatusResolved {
+			return s.withSummary(c), nil
+		}
+	}
+	return models.FraudCase{}, fmt.Errorf("open %s case: %w", groupType, ErrNotFound)
+}
+
+func (s *MemoryStore) OpenCase(ctx context.Context, groupType, groupKey string, audit models.AuditEntry) (models.FraudCase, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, err := s.findOpenCase(groupType, groupKey); err == nil {
+		return c, false, nil
+	}
+
+	now := time.Now().UTC()
+	c := models.FraudCase{
+		ID:        int64(len(s.fraud.cases) + 1),
+		GroupType: groupType,
+		GroupKey:  groupKey,
+		Status:    models.CaseStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.fraud.cases = append(s.fraud.cases, c)
+	s.appendAudit(auditTarget(audit, c.ID))
+	return c, true, nil
+}
+
+func (s *MemoryStore) CaseForTransaction(ctx context.Context, transactionID int) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range s.fraud.transactions {
+		if t.TransactionID == transactionID {
+			return t.CaseID, nil
+		}
+	}
+	return 0, fmt.Errorf("transaction %d: %w", transactionID, ErrNotFound)
+}
+
+func (s *MemoryStore) AddCaseTransaction(ctx context.Context, t models.CaseTransaction) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.touchCase(t.CaseID); err != nil {
+		return false, err
+	}
+	for _, existing := range s.fraud.transactions {
+		if existing.TransactionID == t.TransactionID {
+			return false, nil
+		}
+	}
+	t.AddedAt = time.Now().UTC()
+	s.fraud.transactions = append(s.fraud.transactions, t)
+	return true, nil
+}
+
+func (s *MemoryStore) ListCases(ctx context.Context, f models.CaseFilter) ([]models.FraudCase, error) {
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cases := []models.FraudCase{}
+	for _, c := range s.fraud.cases {
+		if f.Status != "" && c.Status != f.Status {
+			continue
+		}
+		if f.Assignee != "" && c.Assignee != f.Assignee {
+			continue
+		}
+		if !f.OpenedBefore.IsZero() && (c.Status == models.CaseStatusResolved || !c.CreatedAt.Before(f.OpenedBefore)) {
+			continue
+		}
+		cases = append(cases, s.withSummary(c))
+	}
+	sort.SliceStable(cases, func(i, j int) bool {
+		if !cases[i].CreatedAt.Equal(cases[j].CreatedAt) {
+			return cases[i].CreatedAt.After(cases[j].CreatedAt)
+		}
+		return cases[i].ID > cases[j].ID
+	})
+	return truncate(cases, f.Limit), nil
+}
+
+func (s *MemoryStore) GetCase(ctx context.Context, id int64) (models.FraudCase, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, err := s.findCase(id)
+	if err != nil {
+		return models.FraudCase{}, err
+	}
+	return s.withSummary(s.fraud.cases[i]), nil
+}
+
+func (s *MemoryStore) ListCaseTransactions(ctx context.Context, caseID int64) ([]models.CaseTransaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	transactions := []models.CaseTransaction{}
+	for _, t := range s.fraud.transactions {
+		if t.CaseID == caseID {
+			transactions = append(transactions, t)
+		}
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].FlaggedAt.Equal(transactions[j].FlaggedAt) {
+			return transactions[i].FlaggedAt.After(transactions[j].FlaggedAt)
+		}
+		return transactions[i].TransactionID > transactions[j].TransactionID
+	})
+	return transactions, nil
+}
+
+func (s *MemoryStore) ListCaseComments(ctx context.Context, caseID int64) ([]models.CaseComment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	comments := []models.CaseComment{}
+	for _, c := range s.fraud.comments {
+		if c.CaseID == caseID {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (s *MemoryStore) ListCaseAttachments(ctx context.Context, caseID int64) ([]models.CaseAttachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	attachments := []models.CaseAttachment{}
+	for _, a := range s.fraud.attachments {
+		if a.CaseID == caseID {
+			a.Data = nil
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+func (s *MemoryStore) GetCaseAttachment(ctx context.Context, caseID, id int64) (models.CaseAttachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, a := range s.fraud.attachments {
+		if a.CaseID == caseID && a.ID == id {
+			a.Data = append([]byte(nil), a.Data...)
+			return a, nil
+		}
+	}
+	return models.CaseAttachment{}, fmt.Errorf("attachment %d on case %d: %w", id, caseID, ErrNotFound)
+}
+
+func (s *MemoryStore) AssignCase(ctx context.Context, id int64, assignee string, audit models.AuditEntry) (models.FraudCase, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.touchCase(id)
+	if err != nil {
+		return models.FraudCase{}, err
+	}
+	s.fraud.cases[i].Assignee = assignee
+	s.appendAudit(auditTarget(audit, id))
+	return s.withSummary(s.fraud.cases[i]), nil
+}
+
+func (s *MemoryStore) SetCaseStatus(ctx context.Context, id int64, status, resolution string, audit models.AuditEntry) (models.FraudCase, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findCase(id)
+	if err != nil {
+		return models.FraudCase{}, err
+	}
+	c := s.fraud.cases[i]
+	if status != models.CaseStatusResolved && c.Status == models.CaseStatusResolved {
+		if _, err := s.findOpenCase(c.GroupType, c.GroupKey); err == nil {
+			return models.FraudCase{}, fmt.Errorf("case %d: another unresolved case exists for its group: %w", id, ErrConflict)
+		}
+	}
+
+	now := time.Now().UTC()
+	c.Status, c.UpdatedAt = status, now
+	c.Resolution, c.ResolvedAt = "", nil
+	if status == models.CaseStatusResolved {
+		c.Resolution, c.ResolvedAt = resolution, &now
+	}
+	s.fraud.cases[i] = c
+	s.appendAudit(auditTarget(audit, id))
+	return s.withSummary(c), nil
+}
+
+func (s *MemoryStore) AddCaseComment(ctx context.Context, c models.CaseComment, audit models.AuditEntry) (models.CaseComment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.touchCase(c.CaseID); err != nil {
+		return models.CaseComment{}, err
+	}
+	c.ID = int64(len(s.fraud.comments) + 1)
+	c.CreatedAt = time.Now().UTC()
+	s.fraud.comments = append(s.fraud.comments, c)
+	s.appendAudit(auditTarget(audit, c.CaseID))
+	return c, nil
+}
+
+func (s *MemoryStore) AddCaseAttachment(ctx context.Context, a models.CaseAttachment, audit models.AuditEntry) (models.CaseAttachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.touchCase(a.CaseID); err != nil {
+		return models.CaseAttachment{}, err
+	}
+	a.ID = int64(len(s.fraud.attachments) + 1)
+	a.Size = int64(len(a.Data))
+	a.Data = append([]byte(nil), a.Data...)
+	a.CreatedAt = time.Now().UTC()
+	s.fraud.attachments = append(s.fraud.attachments, a)
+	s.appendAudit(auditTarget(audit, a.CaseID))
+
+	a.Data = nil
+	return a, nil
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// memoryPayoutReview holds the MemoryStore's payout review tables.
+type memoryPayoutReview struct {
+	holds   map[int]models.PayoutHold
+	actions []models.PayoutAction
+}
+
+// payout returns p as listed, with its merchant and hold, reporting false
+// when its merchant does not exist; callers hold s.mu.
+func (s *MemoryStore) payout(p models.Payout) (models.Payout, bool) {
+	for _, m := range s.merchants {
+		if m.ID == p.MerchantID {
+			p.MerchantName, p.MerchantCreatedAt, p.Hold = m.BusinessName, m.CreatedAt, nil
+			if h, ok := s.payoutReview.holds[p.ID]; ok {
+				p.Hold = &h
+			}
+			return p, true
+		}
+	}
+	return models.Payout{}, false
+}
+
+// payoutReviewed reports whether any action was taken on a payout; callers
+// hold s.mu.
+func (s *MemoryStore) payoutReviewed(id int) bool {
+	for _, a := range s.payoutReview.actions {
+		if a.PayoutID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) ListPayouts(ctx context.Context, f models.PayoutFilter) ([]models.Payout, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	payouts := []models.Payout{}
+	for _, p := range s.payouts {
+		p, ok := s.payout(p)
+		if !ok ||
+			(f.MerchantID != 0 && p.MerchantID != f.MerchantID) ||
+			(f.Status != "" && p.Status != f.Status) ||
+			(f.Currency != "" && p.Currency != f.Currency) ||
+			p.Amount < f.MinAmount ||
+			(!f.Since.IsZero() && p.CreatedAt.Before(f.Since)) ||
+			(!f.Until.IsZero() && !p.CreatedAt.Before(f.Until)) ||
+			(f.Held && p.Hold == nil) ||
+			(f.Unreviewed && s.payoutReviewed(p.ID)) {
+			continue
+		}
+		payouts = append(payouts, p)
+	}
+	sort.SliceStable(payouts, func(i, j int) bool {
+		if !payouts[i].CreatedAt.Equal(payouts[j].CreatedAt) {
+			return payouts[i].CreatedAt.After(payouts[j].CreatedAt)
+		}
+		return payouts[i].ID > payouts[j].ID
+	})
+	return truncate(payouts, f.Limit), nil
+}
+
+func (s *MemoryStore) GetPayout(ctx context.Context, id int) (models.Payout, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.payouts {
+		if p.ID == id {
+			if p, ok := s.payout(p); ok {
+				return p, nil
+			}
+		}
+	}
+	return models.Payout{}, fmt.Errorf("payout %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) HoldPayout(ctx context.Context, h models.PayoutHold, audit models.AuditEntry) (models.PayoutHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.payoutReview.holds[h.PayoutID]; ok {
+		return models.PayoutHold{}, fmt.Errorf("payout %d is already held: %w", h.PayoutID, ErrConflict)
+	}
+	if s.payoutReview.holds == nil {
+		s.payoutReview.holds = map[int]models.PayoutHold{}
+	}
+	h.CreatedAt = time.Now().UTC()
+	s.payoutReview.holds[h.PayoutID] = h
+	s.appendPayoutAction(models.PayoutAction{PayoutID: h.PayoutID, Action: models.PayoutActionHold, Reason: h.Reason, Actor: h.HeldBy}, h.CreatedAt)
+	s.appendAudit(auditTarget(audit, int64(h.PayoutID)))
+	return h, nil
+}
+
+func (s *MemoryStore) RecordPayoutAction(ctx context.Context, a models.PayoutAction, audit models.AuditEntry) (models.PayoutAction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.Action == models.PayoutActionRelease || a.Action == models.PayoutActionCancel {
+		if _, ok := s.payoutReview.holds[a.PayoutID]; !ok && a.Action == models.PayoutActionRelease {
+			return models.PayoutAction{}, fmt.Errorf("payout %d is not held: %w", a.PayoutID, ErrConflict)
+		}
+		delete(s.payoutReview.holds, a.PayoutID)
+	}
+	a = s.appendPayoutAction(a, time.Now().UTC())
+	s.appendAudit(auditTarget(audit, int64(a.PayoutID)))
+	return a, nil
+}
+
+// appendPayoutAction stores an action taken at a time; callers hold s.mu.
+func (s *MemoryStore) appendPayoutAction(a models.PayoutAction, at time.Time) models.PayoutAction {
+	a.ID = int64(len(s.payoutReview.actions) + 1)
+	a.CreatedAt = at
+	s.payoutReview.actions = append(s.payoutReview.actions, a)
+	return a
+}
+
+func (s *MemoryStore) ListPayoutActions(ctx context.Context, payoutID int) ([]models.PayoutAction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var actions []models.PayoutAction
+	for _, a := range s.payoutReview.actions {
+		if a.PayoutID == payoutID {
+			actions = append(actions, a)
+		}
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		if !actions[i].CreatedAt.Equal(actions[j].CreatedAt) {
+			return actions[i].CreatedAt.After(actions[j].CreatedAt)
+		}
+		return actions[i].ID > actions[j].ID
+	})
+	return actions, nil
+}
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// memoryPolicies holds the MemoryStore's suspension policy tables.
+type memoryPolicies struct {
+	policies      []models.SuspensionPolicy
+	matches       []models.PolicyMatch
+	kycRejections []kycRejection
+}
+
+type kycRejection struct {
+	merchantID int
+	at         time.Time
+}
+
+// findPolicy returns the index of a policy; callers hold s.mu.
+func (s *MemoryStore) findPolicy(id int64) (int, error) {
+	for i, p := range s.policies.policies {
+		if p.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("policy %d: %w", id, ErrNotFound)
+}
+
+// policyNameTaken reports whether a policy other than id has a name; callers
+// hold s.mu.
+func (s *MemoryStore) policyNameTaken(name string, id int64) bool {
+	for _, p := range s.policies.policies {
+		if p.Name == name && p.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) CreatePolicy(ctx context.Context, p models.SuspensionPolicy, audit models.AuditEntry) (models.SuspensionPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.policyNameTaken(p.Name, 0) {
+		return models.SuspensionPolicy{}, fmt.Errorf("policy %q: %w", p.Name, ErrConflict)
+	}
+	now := time.Now().UTC()
+	p.ID = int64(len(s.policies.policies) + 1)
+	p.CreatedAt, p.UpdatedAt = now, now
+	s.policies.policies = append(s.policies.policies, p)
+	s.appendAudit(auditTarget(audit, p.ID))
+	return p, nil
+}
+
+func (s *MemoryStore) UpdatePolicy(ctx context.Context, p models.SuspensionPolicy, audit models.AuditEntry) (models.SuspensionPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findPolicy(p.ID)
+	if err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+	if s.policyNameTaken(p.Name, p.ID) {
+		return models.SuspensionPolicy{}, fmt.Errorf("policy %q: %w", p.Name, ErrConflict)
+	}
+	existing := s.policies.policies[i]
+	p.CreatedBy, p.CreatedAt, p.UpdatedAt = existing.CreatedBy, existing.CreatedAt, time.Now().UTC()
+	s.policies.policies[i] = p
+	s.appendAudit(auditTarget(audit, p.ID))
+	return p, nil
+}
+
+func (s *MemoryStore) GetPolicy(ctx context.Context, id int64) (models.SuspensionPolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, err := s.findPolicy(id)
+	if err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+	return s.policies.policies[i], nil
+}
+
+func (s *MemoryStore) ListPolicies(ctx context.Context, enabledOnly bool) ([]models.SuspensionPolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var policies []models.SuspensionPolicy
+	for _, p := range s.policies.policies {
+		if p.Enabled || !enabledOnly {
+			policies = append(policies, p)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	return policies, nil
+}
+
+func (s *MemoryStore) RecordKYCRejection(ctx context.Context, merchantID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies.kycRejections = append(s.policies.kycRejections, kycRejection{merchantID: merchantID, at: at})
+	return nil
+}
+
+func (s *MemoryStore) RecordPolicyMatch(ctx context.Context, m models.PolicyMatch, quietSince time.Time, audit models.AuditEntry) (models.PolicyMatch, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findPolicy(m.PolicyID)
+	if err != nil {
+		return models.PolicyMatch{}, false, err
+	}
+	for _, existing := range s.policies.matches {
+		if existing.PolicyID != m.PolicyID || existing.MerchantID != m.MerchantID {
+			continue
+		}
+		if existing.Status == models.MatchStatusDryRun && m.Status != models.MatchStatusDryRun {
+			continue
+		}
+		last := existing.CreatedAt
+		if existing.ReviewedAt != nil && existing.ReviewedAt.After(last) {
+			last = *existing.ReviewedAt
+		}
+		if existing.Status == models.MatchStatusPending || !last.Before(quietSince) {
+			return models.PolicyMatch{}, false, nil
+		}
+	}
+	merchant := -1
+	for j := range s.merchants {
+		if s.merchants[j].ID == m.MerchantID {
+			merchant = j
+		}
+	}
+	if m.Status == models.MatchStatusSuspended && merchant < 0 {
+		return models.PolicyMatch{}, false, fmt.Errorf("merchant %d: %w", m.MerchantID, ErrNotFound)
+	}
+	m.ID = int64(len(s.policies.matches) + 1)
+	m.PolicyName = s.policies.policies[i].Name
+	m.ReviewedBy, m.ReviewedAt, m.ReviewNote = "", nil, ""
+	m.CreatedAt = time.Now().UTC()
+	s.policies.matches = append(s.policies.matches, m)
+	if m.Status == models.MatchStatusSuspended {
+		s.merchants[merchant].Status = "suspended"
+		s.merchants[merchant].UpdatedAt = m.CreatedAt
+		s.appendAudit(suspensionAudit(audit, m))
+	}
+	return m, true, nil
+}
+
+// policyMatch returns a match with its policy's current name; callers hold
+// s.mu.
+func (s *MemoryStore) policyMatch(m models.PolicyMatch) models.PolicyMatch {
+	if i, err := s.findPolicy(m.PolicyID); err == nil {
+		m.PolicyName = s.policies.policies[i].Name
+	}
+	return m
+}
+
+func (s *MemoryStore) ListPolicyMatches(ctx context.Context, f models.PolicyMatchFilter) ([]models.PolicyMatch, error) {
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []models.PolicyMatch
+	for _, m := range s.policies.matches {
+		if (f.PolicyID == 0 || m.PolicyID == f.PolicyID) && (f.MerchantID == 0 || m.MerchantID == f.MerchantID) && (f.Status == "" || m.Status == f.Status) {
+			matches = append(matches, s.policyMatch(m))
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.After(matches[j].CreatedAt)
+		}
+		return matches[i].ID > matches[j].ID
+	})
+	return truncate(matches, f.Limit), nil
+}
+
+// findPolicyMatch returns the index of a policy match; callers hold s.mu.
+func (s *MemoryStore) findPolicyMatch(id int64) (int, error) {
+	for i, m := range s.policies.matches {
+		if m.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("policy match %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) GetPolicyMatch(ctx context.Context, id int64) (models.PolicyMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, err := s.findPolicyMatch(id)
+	if err != nil {
+		return models.PolicyMatch{}, err
+	}
+	return s.policyMatch(s.policies.matches[i]), nil
+}
+
+func (s *MemoryStore) ReviewPolicyMatch(ctx context.Context, id int64, status, by, note string, audit models.AuditEntry) (models.PolicyMatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findPolicyMatch(id)
+	if err != nil {
+		return models.PolicyMatch{}, err
+	}
+	m := &s.policies.matches[i]
+	if m.Status != models.MatchStatusPending {
+		return models.PolicyMatch{}, fmt.Errorf("policy match %d is %s: %w", id, m.Status, ErrConflict)
+	}
+	now := time.Now().UTC()
+	m.Status, m.ReviewedBy, m.ReviewedAt, m.ReviewNote = status, by, &now, note
+	s.appendAudit(auditTarget(audit, id))
+	return s.policyMatch(*m), nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func (s *MemoryStore) MerchantActivity(ctx context.Context, q models.ActivityQuery) ([]models.MerchantActivity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ratioFrom := q.Now.Add(-q.RatioWindow)
+	recentFrom := q.Now.Add(-q.RecentWindow)
+	baselineFrom := recentFrom.Add(-q.BaselineWindow)
+	byMerchant := make(map[int]*models.MerchantActivity, len(s.merchants))
+	activity := make([]models.MerchantActivity, len(s.merchants))
+	for i, m := range s.merchants {
+		activity[i] = models.MerchantActivity{MerchantID: m.ID, Status: m.Status, KYCStatus: m.KYCStatus, KYCVerifiedAt: m.KYCVerifiedAt, CreatedAt: m.CreatedAt}
+		byMerchant[m.ID] = &activity[i]
+	}
+	for _, r := range s.policies.kycRejections {
+		if a, ok := byMerchant[r.merchantID]; ok && !r.at.Before(ratioFrom) && r.at.Before(q.Now) {
+			a.KYCRejections++
+		}
+	}
+	for _, t := range s.transactions {
+		a, ok := byMerchant[t.MerchantID]
+		if !ok || !t.CreatedAt.Before(q.Now) {
+			continue
+		}
+		if !t.CreatedAt.Before(ratioFrom) {
+			a.Transactions++
+			switch t.Status {
+			case "failed":
+				a.Failed++
+			case "refunded":
+				a.Refunded++
+			case "chargeback":
+				a.Chargebacks++
+			}
+		}
+		if t.Status != "successful" {
+			continue
+		}
+		if t.CreatedAt.Before(baselineFrom) {
+			continue
+		}
+		i := slices.IndexFunc(a.Volumes, func(v models.CurrencyVolume) bool { return v.Currency == t.Currency })
+		if i < 0 {
+			a.Volumes = append(a.Volumes, models.CurrencyVolume{Currency: t.Currency})
+			i = len(a.Volumes) - 1
+		}
+		if t.CreatedAt.Before(recentFrom) {
+			a.Volumes[i].Baseline += t.Amount
+		} else {
+			a.Volumes[i].Recent += t.Amount
+		}
+	}
+	for _, a := range activity {
+		sort.Slice(a.Volumes, func(i, j int) bool { return a.Volumes[i].Currency < a.Volumes[j].Currency })
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].MerchantID < activity[j].MerchantID })
+	return activity, nil
+}
+
+func (s *MemoryStore) SaveRiskScores(ctx context.Context, scores []models.RiskScore) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.risk == nil {
+		s.risk = make(map[int]models.RiskScore, len(scores))
+	}
+	for _, score := range scores {
+		score.Factors = append([]models.RiskFactor(nil), score.Factors...)
+		s.risk[score.MerchantID] = score
+	}
+	return nil
+}
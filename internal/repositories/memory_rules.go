@@ -0,0 +1,255 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// memoryRules holds the MemoryStore's fraud rule tables.
+type memoryRules struct {
+	rules    []models.FraudRule
+	versions []models.FraudRuleVersion
+	flags    []models.RuleFlag
+}
+
+// findRule returns the index of a rule; callers hold s.mu.
+func (s *MemoryStore) findRule(id int64) (int, error) {
+	for i, r := range s.rules.rules {
+		if r.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("rule %d: %w", id, ErrNotFound)
+}
+
+func (s *MemoryStore) CreateRule(ctx context.Context, rule models.FraudRule, audit models.AuditEntry) (models.FraudRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.rules.rules {
+		if r.Name == rule.Name {
+			return models.FraudRule{}, fmt.Errorf("rule %q: %w", rule.Name, ErrConflict)
+		}
+	}
+	now := time.Now().UTC()
+	rule.ID = int64(len(s.rules.rules) + 1)
+	rule.Version = 1
+	rule.CreatedAt, rule.UpdatedAt = now, now
+	s.rules.rules = append(s.rules.rules, rule)
+	s.rules.versions = append(s.rules.versions, models.FraudRuleVersion{
+		RuleID: rule.ID, Version: 1, Expression: rule.Expression, CreatedBy: rule.CreatedBy, CreatedAt: now,
+	})
+	s.appendAudit(auditTarget(audit, rule.ID))
+	return rule, nil
+}
+
+func (s *MemoryStore) ReviseRule(ctx context.Context, id int64, expression, author string, audit models.AuditEntry) (models.FraudRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findRule(id)
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	now := time.Now().UTC()
+	r := &s.rules.rules[i]
+	r.Version++
+	r.Expression = expression
+	r.UpdatedAt = now
+	s.rules.versions = append(s.rules.versions, models.FraudRuleVersion{
+		RuleID: id, Version: r.Version, Expression: expression, CreatedBy: author, CreatedAt: now,
+	})
+	s.appendAudit(auditTarget(audit, id))
+	return *r, nil
+}
+
+func (s *MemoryStore) SetRuleEnabled(ctx context.Context, id int64, enabled bool, audit models.AuditEntry) (models.FraudRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findRule(id)
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	s.rules.rules[i].Enabled = enabled
+	s.rules.rules[i].UpdatedAt = time.Now().UTC()
+	s.appendAudit(auditTarget(audit, id))
+	return s.rules.rules[i], nil
+}
+
+func (s *MemoryStore) GetRule(ctx context.Context, id int64) (models.FraudRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, err := s.findRule(id)
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	return s.rules.rules[i], nil
+}
+
+func (s *MemoryStore) ListRules(ctx context.Context, enabledOnly bool) ([]models.FraudRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rules := []models.FraudRule{}
+	for _, r := range s.rules.rules {
+		if r.Enabled || !enabledOnly {
+			rules = append(rules, r)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules, nil
+}
+
+func (s *MemoryStore) ListRuleVersions(ctx context.Context, id int64) ([]models.FraudRuleVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := []models.FraudRuleVersion{}
+	for i := len(s.rules.versions) - 1; i >= 0; i-- {
+		if s.rules.versions[i].RuleID == id {
+			versions = append(versions, s.rules.versions[i])
+		}
+	}
+	return versions, nil
+}
+
+// velocityKey returns what transactions must share with t to count towards
+// its velocity in a dimension, or "" when only t itself counts.
+func velocityKey(t models.Transaction, dimension string) string {
+	switch dimension {
+	case models.VelocityCard:
+		return t.CardFingerprint
+	case models.VelocityEmail:
+		return strings.ToLower(t.CustomerEmail)
+	case models.VelocityIP:
+		return t.IPAddress
+	case models.VelocityMerchant:
+		return strconv.Itoa(t.MerchantID)
+	}
+	return ""
+}
+
+// EachTransactionFacts snapshots the facts before calling fn, so fn may use
+// the store.
+func (s *MemoryStore) EachTransactionFacts(ctx context.Context, q models.FactQuery, fn func(models.TransactionFacts) error) error {
+	for _, w := range q.Windows {
+		if _, ok := velocityKeys[w.Dimension]; !ok {
+			return fmt.Errorf("unknown velocity dimension %q", w.Dimension)
+		}
+	}
+
+	s.mu.RLock()
+	merchants := map[int]models.Merchant{}
+	for _, m := range s.merchants {
+		merchants[m.ID] = m
+	}
+	var joined []models.Transaction
+	for _, t := range s.transactions {
+		if _, ok := merchants[t.MerchantID]; ok {
+			joined = append(joined, t)
+		}
+	}
+	// decisions are appended in order, so the last final one wins
+	reviews := map[int]string{}
+	for _, d := range s.decisions {
+		if d.Final() {
+			reviews[d.TransactionID] = d.Decision
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(joined, func(i, j int) bool {
+		if !joined[i].CreatedAt.Equal(joined[j].CreatedAt) {
+			return joined[i].CreatedAt.Before(joined[j].CreatedAt)
+		}
+		return joined[i].ID < joined[j].ID
+	})
+
+	var facts []models.TransactionFacts
+	for _, t := range joined {
+		if t.CreatedAt.Before(q.From) || !t.CreatedAt.Before(q.To) {
+			continue
+		}
+		m := merchants[t.MerchantID]
+		f := models.TransactionFacts{
+			Transaction:       t,
+			MerchantCategory:  m.Category,
+			MerchantCountry:   m.Country,
+			MerchantCreatedAt: m.CreatedAt,
+			Velocity:          make(map[models.VelocityWindow]models.VelocityStat, len(q.Windows)),
+			ReviewDecision:    reviews[t.ID],
+		}
+		for _, w := range q.Windows {
+			key := velocityKey(t, w.Dimension)
+			if key == "" {
+				f.Velocity[w] = models.VelocityStat{Count: 1, Volume: t.Amount}
+				continue
+			}
+			var stat models.VelocityStat
+			for _, u := range joined {
+				if velocityKey(u, w.Dimension) == key && !u.CreatedAt.Before(t.CreatedAt.Add(-w.Window)) && !u.CreatedAt.After(t.CreatedAt) {
+					stat.Count++
+					stat.Volume += u.Amount
+				}
+			}
+			f.Velocity[w] = stat
+		}
+		facts = append(facts, f)
+	}
+
+	for _, f := range facts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) RecordRuleFlags(ctx context.Context, flags []models.RuleFlag) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added := 0
+	for _, f := range flags {
+		duplicate := false
+		for _, existing := range s.rules.flags {
+			if existing.RuleID == f.RuleID && existing.RuleVersion == f.RuleVersion && existing.TransactionID == f.TransactionID {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		f.ID = int64(len(s.rules.flags) + 1)
+		f.CreatedAt = time.Now().UTC()
+		f.RuleName = ""
+		s.rules.flags = append(s.rules.flags, f)
+		added++
+	}
+	return added, nil
+}
+
+func (s *MemoryStore) ListRuleFlags(ctx context.Context, f models.FlagFilter) ([]models.RuleFlag, error) {
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	flags := []models.RuleFlag{}
+	for i := len(s.rules.flags) - 1; i >= 0; i-- {
+		fl := s.rules.flags[i]
+		if (f.RuleID != 0 && fl.RuleID != f.RuleID) || (f.TransactionID != 0 && fl.TransactionID != f.TransactionID) {
+			continue
+		}
+		if ri, err := s.findRule(fl.RuleID); err == nil {
+			fl.RuleName = s.rules.rules[ri].Name
+		}
+		flags = append(flags, fl)
+	}
+	return truncate(flags, f.Limit), nil
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// activeSettlementHold returns the index of a merchant's hold in effect;
+// callers hold s.mu.
+func (s *MemoryStore) activeSettlementHold(merchantID int) (int, bool) {
+	for i, h := range s.settlement {
+		if h.MerchantID == merchantID && h.ReleasedAt == nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// releaseSettlementHold ends the hold at index i; callers hold s.mu.
+func (s *MemoryStore) releaseSettlementHold(i int, by, reason string, at time.Time) {
+	s.settlement[i].ReleasedBy, s.settlement[i].ReleasedAt, s.settlement[i].ReleaseReason = by, &at, reason
+}
+
+func (s *MemoryStore) SetSettlementHold(ctx context.Context, h models.SettlementHold, audit models.AuditEntry) (models.SettlementHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	if i, ok := s.activeSettlementHold(h.MerchantID); ok {
+		s.releaseSettlementHold(i, h.CreatedBy, settlementHoldReplaced, now)
+	}
+	h.ID = int64(len(s.settlement) + 1)
+	h.CreatedAt = now
+	h.ReleasedBy, h.ReleasedAt, h.ReleaseReason = "", nil, ""
+	s.settlement = append(s.settlement, h)
+	s.appendAudit(auditTarget(audit, int64(h.MerchantID)))
+	return h, nil
+}
+
+func (s *MemoryStore) ReleaseSettlementHold(ctx context.Context, merchantID int, by, reason string, audit models.AuditEntry) (models.SettlementHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.activeSettlementHold(merchantID)
+	if !ok {
+		return models.SettlementHold{}, fmt.Errorf("settlement hold for merchant %d: %w", merchantID, ErrNotFound)
+	}
+	s.releaseSettlementHold(i, by, reason, time.Now().UTC())
+	s.appendAudit(auditTarget(audit, int64(merchantID)))
+	return s.settlement[i], nil
+}
+
+func (s *MemoryStore) ListSettlementHolds(ctx context.Context, f models.SettlementHoldFilter) ([]models.SettlementHold, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	var holds []models.SettlementHold
+	for _, h := range s.settlement {
+		if (f.MerchantID != 0 && h.MerchantID != f.MerchantID) ||
+			(f.Type != "" && h.Type != f.Type) ||
+			(f.ActiveOnly && h.ReleasedAt != nil) {
+			continue
+		}
+		holds = append(holds, h)
+	}
+	sort.SliceStable(holds, func(i, j int) bool {
+		if !holds[i].CreatedAt.Equal(holds[j].CreatedAt) {
+			return holds[i].CreatedAt.After(holds[j].CreatedAt)
+		}
+		return holds[i].ID > holds[j].ID
+	})
+	return truncate(holds, f.Limit), nil
+}
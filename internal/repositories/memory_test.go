@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+type memoryFixture struct {
+	*MemoryStore
+}
+
+func (f memoryFixture) addMerchant(t *testing.T, m models.Merchant)        { f.AddMerchant(m) }
+func (f memoryFixture) addTransaction(t *testing.T, tx models.Transaction) { f.AddTransaction(tx) }
+func (f memoryFixture) addPayout(t *testing.T, p models.Payout)            { f.AddPayout(p) }
+func (f memoryFixture) auditLog(t *testing.T) []models.AuditEntry          { return f.AuditLog() }
+
+func TestMemoryStore(t *testing.T) {
+	testAdminStoreContract(t, func(t *testing.T) storeFixture {
+		return memoryFixture{NewMemoryStore()}
+	})
+}
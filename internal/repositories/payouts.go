@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// payoutQuery selects payouts p with their merchant m and hold h; callers
+// append WHERE and ORDER BY clauses.
+const payoutQuery = `
+	SELECT p.id, p.reference, p.merchant_id, m.business_name, p.amount, p.currency, p.status, p.created_at, m.created_at,
+		h.rule, h.reason, h.held_by, h.created_at
+	FROM payouts p
+	JOIN merchants m ON p.merchant_id = m.id
+	LEFT JOIN payout_holds h ON h.payout_id = p.id`
+
+func scanPayout(row rowScanner) (models.Payout, error) {
+	var (
+		p                    models.Payout
+		rule, reason, heldBy sql.NullString
+		heldAt               sql.NullTime
+	)
+	err := row.Scan(&p.ID, &p.Reference, &p.MerchantID, &p.MerchantName, &p.Amount, &p.Currency, &p.Status, &p.CreatedAt,
+		&p.MerchantCreatedAt, &rule, &reason, &heldBy, &heldAt)
+	if rule.Valid {
+		p.Hold = &models.PayoutHold{PayoutID: p.ID, Rule: rule.String, Reason: reason.String, HeldBy: heldBy.String, CreatedAt: heldAt.Time}
+	}
+	return p, err
+}
+
+const payoutActionColumns = `id, payout_id, action, reason, actor, created_at`
+
+func scanPayoutAction(row rowScanner) (models.PayoutAction, error) {
+	var a models.PayoutAction
+	err := row.Scan(&a.ID, &a.PayoutID, &a.Action, &a.Reason, &a.Actor, &a.CreatedAt)
+	return a, err
+}
+
+func (r *AdminRepository) ListPayouts(ctx context.Context, f models.PayoutFilter) (_ []models.Payout, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListPayouts")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.MerchantID != 0 {
+		where = append(where, "p.merchant_id = "+arg(f.MerchantID))
+	}
+	if f.Status != "" {
+		where = append(where, "p.status = "+arg(f.Status))
+	}
+	if f.Currency != "" {
+		where = append(where, "p.currency = "+arg(f.Currency))
+	}
+	if f.MinAmount > 0 {
+		where = append(where, "p.amount >= "+arg(f.MinAmount))
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "p.created_at >= "+arg(f.Since))
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "p.created_at < "+arg(f.Until))
+	}
+	if f.Held {
+		where = append(where, "h.payout_id IS NOT NULL")
+	}
+	if f.Unreviewed {
+		where = append(where, "NOT EXISTS (SELECT 1 FROM payout_actions a WHERE a.payout_id = p.id)")
+	}
+	query := payoutQuery
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY p.created_at DESC, p.id DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	payouts := []models.Payout{}
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+func (r *AdminRepository) GetPayout(ctx context.Context, id int) (_ models.Payout, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetPayout")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.Payout{}, err
+	}
+	p, err := scanPayout(r.db.QueryRowContext(ctx, payoutQuery+` WHERE p.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Payout{}, fmt.Errorf("payout %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.Payout{}, r.wrapErr(err)
+	}
+	return p, nil
+}
+
+func (r *AdminRepository) HoldPayout(ctx context.Context, h models.PayoutHold, audit models.AuditEntry) (_ models.PayoutHold, err error) {
+	ctx, span := tracing.StartDB(ctx, "HoldPayout")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.PayoutHold{}, err
+	}
+
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO payout_holds (payout_id, rule, reason, held_by)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (payout_id) DO NOTHING
+			RETURNING created_at`,
+			h.PayoutID, h.Rule, h.Reason, h.HeldBy).Scan(&h.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("payout %d is already held: %w", h.PayoutID, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO payout_actions (payout_id, action, reason, actor, created_at)
+			VALUES ($1, $2, $3, $4, $5)`,
+			h.PayoutID, models.PayoutActionHold, h.Reason, h.HeldBy, h.CreatedAt); err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, int64(h.PayoutID)))
+	})
+	if err != nil {
+		return models.PayoutHold{}, err
+	}
+	return h, nil
+}
+
+func (r *AdminRepository) RecordPayoutAction(ctx context.Context, a models.PayoutAction, audit models.AuditEntry) (_ models.PayoutAction, err error) {
+	ctx, span := tracing.StartDB(ctx, "RecordPayoutAction")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.PayoutAction{}, err
+	}
+
+	var recorded models.PayoutAction
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if a.Action == models.PayoutActionRelease || a.Action == models.PayoutActionCancel {
+			res, err := tx.ExecContext(ctx, `DELETE FROM payout_holds WHERE payout_id = $1`, a.PayoutID)
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if n == 0 && a.Action == models.PayoutActionRelease {
+				return fmt.Errorf("payout %d is not held: %w", a.PayoutID, ErrConflict)
+			}
+		}
+		var err error
+		recorded, err = scanPayoutAction(tx.QueryRowContext(ctx, `
+			INSERT INTO payout_actions (payout_id, action, reason, actor)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+payoutActionColumns,
+			a.PayoutID, a.Action, a.Reason, a.Actor))
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, int64(a.PayoutID)))
+	})
+	if err != nil {
+		return models.PayoutAction{}, err
+	}
+	return recorded, nil
+}
+
+func (r *AdminRepository) ListPayoutActions(ctx context.Context, payoutID int) (_ []models.PayoutAction, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListPayoutActions")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+payoutActionColumns+`
+		FROM payout_actions
+		WHERE payout_id = $1
+		ORDER BY created_at DESC, id DESC`, payoutID)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var actions []models.PayoutAction
+	for rows.Next() {
+		a, err := scanPayoutAction(rows)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+	return actions, rows.Err()
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testPayoutReviewContract runs the payout review behaviour every AdminStore
+// must share; it is called from testAdminStoreContract.
+func testPayoutReviewContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "payout"}
+	}
+	payout := func(id, merchantID int, amount int64, currency, status string) models.Payout {
+		return models.Payout{
+			ID: id, Reference: "PO-" + strconv.Itoa(id), MerchantID: merchantID,
+			Amount: amount, Currency: currency, Status: status, CreatedAt: base.Add(time.Duration(id) * time.Hour),
+		}
+	}
+	ids := func(t *testing.T, s storeFixture, f models.PayoutFilter) []int {
+		t.Helper()
+		payouts, err := s.ListPayouts(ctx, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int
+		for _, p := range payouts {
+			got = append(got, p.ID)
+		}
+		return got
+	}
+
+	t.Run("ListPayouts filters", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base))
+		s.addMerchant(t, merchant(2, base.Add(-time.Hour)))
+		s.addPayout(t, payout(1, 1, 5000, "NGN", "completed"))
+		s.addPayout(t, payout(2, 2, 90000, "NGN", models.PayoutStatusPending))
+		s.addPayout(t, payout(3, 1, 120000, "USD", models.PayoutStatusPending))
+		s.addPayout(t, payout(4, 99, 120000, "USD", models.PayoutStatusPending))
+
+		tests := []struct {
+			name   string
+			filter models.PayoutFilter
+			want   []int
+		}{
+			{"all", models.PayoutFilter{}, []int{3, 2, 1}},
+			{"merchant", models.PayoutFilter{MerchantID: 1}, []int{3, 1}},
+			{"status", models.PayoutFilter{Status: models.PayoutStatusPending}, []int{3, 2}},
+			{"currency", models.PayoutFilter{Currency: "NGN"}, []int{2, 1}},
+			{"min amount", models.PayoutFilter{MinAmount: 90000}, []int{3, 2}},
+			{"created range", models.PayoutFilter{Since: base.Add(2 * time.Hour), Until: base.Add(3 * time.Hour)}, []int{2}},
+			{"limit", models.PayoutFilter{Limit: 1}, []int{3}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := ids(t, s, tt.filter); !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+
+		p, err := s.GetPayout(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.MerchantName != "Business 2" || !p.MerchantCreatedAt.Equal(base.Add(-time.Hour)) || p.Amount != 90000 || p.Hold != nil {
+			t.Errorf("got %+v", p)
+		}
+		if _, err := s.GetPayout(ctx, 4); !errors.Is(err, ErrNotFound) {
+			t.Errorf("payout of a missing merchant: got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("hold, release and cancel", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base))
+		for id := 1; id <= 3; id++ {
+			s.addPayout(t, payout(id, 1, 100000, "NGN", models.PayoutStatusPending))
+		}
+
+		held, err := s.HoldPayout(ctx, models.PayoutHold{PayoutID: 1, Rule: models.PayoutRuleAmountThreshold, Reason: "above 500000 NGN", HeldBy: "system"}, audit("payout.held"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held.CreatedAt.IsZero() {
+			t.Errorf("hold CreatedAt not set: %+v", held)
+		}
+		if _, err := s.HoldPayout(ctx, models.PayoutHold{PayoutID: 1, Rule: models.PayoutRuleManual, Reason: "again", HeldBy: "analyst-1"}, audit("payout.held")); !errors.Is(err, ErrConflict) {
+			t.Errorf("holding twice: got %v, want ErrConflict", err)
+		}
+		if _, err := s.HoldPayout(ctx, models.PayoutHold{PayoutID: 2, Rule: models.PayoutRuleManual, Reason: "checking bank details", HeldBy: "analyst-1"}, audit("payout.held")); err != nil {
+			t.Fatal(err)
+		}
+		p, err := s.GetPayout(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Hold == nil || p.Hold.Rule != models.PayoutRuleAmountThreshold || p.Hold.HeldBy != "system" || p.Hold.Reason != "above 500000 NGN" {
+			t.Errorf("hold %+v", p.Hold)
+		}
+		if got := ids(t, s, models.PayoutFilter{Held: true}); !slices.Equal(got, []int{2, 1}) {
+			t.Errorf("held payouts = %v", got)
+		}
+		if got := ids(t, s, models.PayoutFilter{Unreviewed: true}); !slices.Equal(got, []int{3}) {
+			t.Errorf("unreviewed payouts = %v", got)
+		}
+
+		released, err := s.RecordPayoutAction(ctx, models.PayoutAction{PayoutID: 1, Action: models.PayoutActionRelease, Reason: "merchant verified", Actor: "analyst-1"}, audit("payout.released"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if released.ID == 0 || released.CreatedAt.IsZero() {
+			t.Errorf("released %+v", released)
+		}
+		if _, err := s.RecordPayoutAction(ctx, models.PayoutAction{PayoutID: 1, Action: models.PayoutActionRelease, Reason: "again", Actor: "analyst-1"}, audit("payout.released")); !errors.Is(err, ErrConflict) {
+			t.Errorf("releasing an unheld payout: got %v, want ErrConflict", err)
+		}
+		if _, err := s.RecordPayoutAction(ctx, models.PayoutAction{PayoutID: 2, Action: models.PayoutActionCancel, Reason: "bank account closed", Actor: "analyst-1"}, audit("payout.cancelled")); err != nil {
+			t.Fatal(err)
+		}
+		// a payout that was never held can still be cancelled or retried
+		if _, err := s.RecordPayoutAction(ctx, models.PayoutAction{PayoutID: 3, Action: models.PayoutActionRetry, Reason: "bank outage over", Actor: "analyst-1"}, audit("payout.retried")); err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(t, s, models.PayoutFilter{Held: true}); len(got) != 0 {
+			t.Errorf("held payouts after release and cancel = %v", got)
+		}
+
+		actions, err := s.ListPayoutActions(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(actions) != 2 || actions[0].ID != released.ID || actions[1].Action != models.PayoutActionHold || actions[1].Actor != "system" || actions[1].Reason != "above 500000 NGN" {
+			t.Errorf("actions on payout 1 %+v", actions)
+		}
+		log := s.auditLog(t)
+		if len(log) != 5 || log[0].Action != "payout.held" || log[0].TargetID != "1" || log[4].Action != "payout.retried" || log[4].TargetID != "3" {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+const policyColumns = `id, name, description, condition, threshold, window_days, min_transactions, action, dry_run, enabled,
+	created_by, created_at, updated_at`
+
+func scanPolicy(row rowScanner) (models.SuspensionPolicy, error) {
+	var p models.SuspensionPolicy
+	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.Condition, &p.Threshold, &p.WindowDays, &p.MinTransactions, &p.Action,
+		&p.DryRun, &p.Enabled, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// matchColumns are the columns of a policy match m joined to its policy p.
+const matchColumns = `m.id, m.policy_id, p.name, m.merchant_id, m.value, m.action, m.status,
+	COALESCE(m.reviewed_by, ''), m.reviewed_at, COALESCE(m.review_note, ''), m.created_at`
+
+// matchQuery selects policy matches with their policy's name; callers append
+// WHERE and ORDER BY clauses.
+const matchQuery = `
+	SELECT ` + matchColumns + `
+	FROM suspension_policy_matches m
+	JOIN suspension_policies p ON p.id = m.policy_id`
+
+func scanMatch(row rowScanner) (models.PolicyMatch, error) {
+	var (
+		m        models.PolicyMatch
+		reviewed sql.NullTime
+	)
+	err := row.Scan(&m.ID, &m.PolicyID, &m.PolicyName, &m.MerchantID, &m.Value, &m.Action, &m.Status,
+		&m.ReviewedBy, &reviewed, &m.ReviewNote, &m.CreatedAt)
+	if reviewed.Valid {
+		m.ReviewedAt = &reviewed.Time
+	}
+	return m, err
+}
+
+func (r *AdminRepository) CreatePolicy(ctx context.Context, p models.SuspensionPolicy, audit models.AuditEntry) (_ models.SuspensionPolicy, err error) {
+	ctx, span := tracing.StartDB(ctx, "CreatePolicy")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+
+	var created models.SuspensionPolicy
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		created, err = scanPolicy(tx.QueryRowContext(ctx, `
+			INSERT INTO suspension_policies (name, description, condition, threshold, window_days, min_transactions, action, dry_run, enabled, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING `+policyColumns,
+			p.Name, p.Description, p.Condition, p.Threshold, p.WindowDays, p.MinTransactions, p.Action, p.DryRun, p.Enabled, p.CreatedBy))
+		if isUniqueViolation(err) {
+			return fmt.Errorf("policy %q: %w", p.Name, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, created.ID))
+	})
+	if err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+	return created, nil
+}
+
+func (r *AdminRepository) UpdatePolicy(ctx context.Context, p models.SuspensionPolicy, audit models.AuditEntry) (_ models.SuspensionPolicy, err error) {
+	ctx, span := tracing.StartDB(ctx, "UpdatePolicy")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+
+	var updated models.SuspensionPolicy
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		updated, err = scanPolicy(tx.QueryRowContext(ctx, `
+			UPDATE suspension_policies SET
+				name = $2,
+				description = $3,
+				condition = $4,
+				threshold = $5,
+				window_days = $6,
+				min_transactions = $7,
+				action = $8,
+				dry_run = $9,
+				enabled = $10,
+				updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+policyColumns,
+			p.ID, p.Name, p.Description, p.Condition, p.Threshold, p.WindowDays, p.MinTransactions, p.Action, p.DryRun, p.Enabled))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("policy %d: %w", p.ID, ErrNotFound)
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("policy %q: %w", p.Name, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, p.ID))
+	})
+	if err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+	return updated, nil
+}
+
+func (r *AdminRepository) GetPolicy(ctx context.Context, id int64) (_ models.SuspensionPolicy, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetPolicy")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.SuspensionPolicy{}, err
+	}
+	p, err := scanPolicy(r.db.QueryRowContext(ctx, `SELECT `+policyColumns+` FROM suspension_policies WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SuspensionPolicy{}, fmt.Errorf("policy %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.SuspensionPolicy{}, r.wrapErr(err)
+	}
+	return p, nil
+}
+
+func (r *AdminRepository) ListPolicies(ctx context.Context, enabledOnly bool) (_ []models.SuspensionPolicy, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListPolicies")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + policyColumns + ` FROM suspension_policies`
+	if enabledOnly {
+		query += ` WHERE enabled`
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY name`)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var policies []models.SuspensionPolicy
+	for rows.Next() {
+		p, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+func (r *AdminRepository) RecordKYCRejection(ctx context.Context, merchantID int, at time.Time) (err error) {
+	ctx, span := tracing.StartDB(ctx, "RecordKYCRejection")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO merchant_kyc_rejections (merchant_id, created_at) VALUES ($1, $2)`, merchantID, at)
+	return r.wrapErr(err)
+}
+
+func (r *AdminRepository) RecordPolicyMatch(ctx context.Context, m models.PolicyMatch, quietSince time.Time, audit models.AuditEntry) (_ models.PolicyMatch, _ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "RecordPolicyMatch")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.PolicyMatch{}, false, err
+	}
+
+	var (
+		recorded models.PolicyMatch
+		ok       bool
+	)
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		recorded, err = scanMatch(tx.QueryRowContext(ctx, `
+			WITH m AS (
+				INSERT INTO suspension_policy_matches (policy_id, merchant_id, value, action, status)
+				SELECT $1::bigint, $2::integer, $3::double precision, $4::text, $5::text
+				WHERE NOT EXISTS (
+					SELECT 1 FROM suspension_policy_matches
+					WHERE policy_id = $1 AND merchant_id = $2
+						AND (status <> 'dry_run' OR $5 = 'dry_run')
+						AND (status = 'pending' OR GREATEST(created_at, reviewed_at) >= $6))
+				ON CONFLICT (policy_id, merchant_id) WHERE status = 'pending' DO NOTHING
+				RETURNING *
+			)
+			SELECT `+matchColumns+` FROM m JOIN suspension_policies p ON p.id = m.policy_id`,
+			m.PolicyID, m.MerchantID, m.Value, m.Action, m.Status, quietSince))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ok = true
+		if m.Status != models.MatchStatusSuspended {
+			return nil
+		}
+		return updateMerchantStatus(ctx, tx, m.MerchantID, "suspended", suspensionAudit(audit, recorded))
+	})
+	if err != nil {
+		return models.PolicyMatch{}, false, err
+	}
+	return recorded, ok, nil
+}
+
+// suspensionAudit describes the suspension of the merchant a policy match is
+// for, adding the match's ID to the audit details.
+func suspensionAudit(audit models.AuditEntry, m models.PolicyMatch) models.AuditEntry {
+	details := make(map[string]interface{}, len(audit.Details)+1)
+	for k, v := range audit.Details {
+		details[k] = v
+	}
+	details["policy_match_id"] = m.ID
+	audit.Details = details
+	return auditTarget(audit, int64(m.MerchantID))
+}
+
+func (r *AdminRepository) ListPolicyMatches(ctx context.Context, f models.PolicyMatchFilter) (_ []models.PolicyMatch, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListPolicyMatches")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.PolicyID != 0 {
+		where = append(where, "m.policy_id = "+arg(f.PolicyID))
+	}
+	if f.MerchantID != 0 {
+		where = append(where, "m.merchant_id = "+arg(f.MerchantID))
+	}
+	if f.Status != "" {
+		where = append(where, "m.status = "+arg(f.Status))
+	}
+	query := matchQuery
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY m.created_at DESC, m.id DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var matches []models.PolicyMatch
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+func (r *AdminRepository) GetPolicyMatch(ctx context.Context, id int64) (_ models.PolicyMatch, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetPolicyMatch")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.PolicyMatch{}, err
+	}
+	m, err := scanMatch(r.db.QueryRowContext(ctx, matchQuery+` WHERE m.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.PolicyMatch{}, fmt.Errorf("policy match %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.PolicyMatch{}, r.wrapErr(err)
+	}
+	return m, nil
+}
+
+func (r *AdminRepository) ReviewPolicyMatch(ctx context.Context, id int64, status, by, note string, audit models.AuditEntry) (_ models.PolicyMatch, err error) {
+	ctx, span := tracing.StartDB(ctx, "ReviewPolicyMatch")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.PolicyMatch{}, err
+	}
+
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE suspension_policy_matches SET
+				status = $2,
+				reviewed_by = $3,
+				reviewed_at = NOW(),
+				review_note = NULLIF($4, '')
+			WHERE id = $1 AND status = 'pending'`, id, status, by, note)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			var current string
+			err := tx.QueryRowContext(ctx, `SELECT status FROM suspension_policy_matches WHERE id = $1`, id).Scan(&current)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("policy match %d: %w", id, ErrNotFound)
+			}
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("policy match %d is %s: %w", id, current, ErrConflict)
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.PolicyMatch{}, err
+	}
+	return r.GetPolicyMatch(ctx, id)
+}
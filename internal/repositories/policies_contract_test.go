@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testSuspensionPolicyContract runs the suspension policy behaviour every
+// AdminStore must share; it is called from testAdminStoreContract.
+func testSuspensionPolicyContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "suspension_policy"}
+	}
+	policy := func(name string) models.SuspensionPolicy {
+		return models.SuspensionPolicy{
+			Name: name, Condition: models.PolicyChargebackRatio, Threshold: 0.015, WindowDays: 30, MinTransactions: 20,
+			Action: models.PolicyActionSuspend, DryRun: true, CreatedBy: "analyst-1",
+		}
+	}
+
+	t.Run("policy lifecycle", func(t *testing.T) {
+		s := newStore(t)
+		p, err := s.CreatePolicy(ctx, policy("chargebacks"), audit("suspension_policy.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.ID == 0 || p.Threshold != 0.015 || !p.DryRun || p.Enabled || p.CreatedAt.IsZero() {
+			t.Errorf("created %+v", p)
+		}
+		if _, err := s.CreatePolicy(ctx, policy("chargebacks"), audit("suspension_policy.created")); !errors.Is(err, ErrConflict) {
+			t.Errorf("duplicate name: got %v, want ErrConflict", err)
+		}
+		other, err := s.CreatePolicy(ctx, policy("another"), audit("suspension_policy.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p.DryRun, p.Enabled, p.Threshold, p.CreatedBy = false, true, 0.02, "someone-else"
+		updated, err := s.UpdatePolicy(ctx, p, audit("suspension_policy.updated"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.DryRun || !updated.Enabled || updated.Threshold != 0.02 || updated.CreatedBy != "analyst-1" || updated.UpdatedAt.Before(updated.CreatedAt) {
+			t.Errorf("updated %+v", updated)
+		}
+		p.Name = "another"
+		if _, err := s.UpdatePolicy(ctx, p, audit("suspension_policy.updated")); !errors.Is(err, ErrConflict) {
+			t.Errorf("renaming onto a taken name: got %v, want ErrConflict", err)
+		}
+		p.ID = other.ID + 100
+		if _, err := s.UpdatePolicy(ctx, p, audit("suspension_policy.updated")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("updating a missing policy: got %v, want ErrNotFound", err)
+		}
+		if _, err := s.GetPolicy(ctx, other.ID+100); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetPolicy on missing policy: got %v, want ErrNotFound", err)
+		}
+
+		all, err := s.ListPolicies(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 2 || all[0].Name != "another" || all[1].Name != "chargebacks" {
+			t.Errorf("all policies %+v", all)
+		}
+		enabled, err := s.ListPolicies(ctx, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(enabled) != 1 || enabled[0].ID != updated.ID {
+			t.Errorf("enabled policies %+v", enabled)
+		}
+
+		log := s.auditLog(t)
+		if len(log) != 3 || log[2].Action != "suspension_policy.updated" || log[2].TargetID != itoa64(updated.ID) {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("policy matches", func(t *testing.T) {
+		s := newStore(t)
+		p, err := s.CreatePolicy(ctx, policy("chargebacks"), audit("suspension_policy.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		match := func(merchantID int, status string) models.PolicyMatch {
+			return models.PolicyMatch{PolicyID: p.ID, MerchantID: merchantID, Value: 0.03, Action: models.PolicyActionRecommend, Status: status}
+		}
+		longAgo := time.Now().Add(-24 * time.Hour)
+
+		pending, recorded, err := s.RecordPolicyMatch(ctx, match(1, models.MatchStatusPending), longAgo, models.AuditEntry{})
+		if err != nil || !recorded {
+			t.Fatalf("RecordPolicyMatch = %+v, %v, %v", pending, recorded, err)
+		}
+		if pending.ID == 0 || pending.PolicyName != "chargebacks" || pending.Value != 0.03 || pending.CreatedAt.IsZero() {
+			t.Errorf("recorded %+v", pending)
+		}
+		// a pending match holds back another however old it is
+		if _, recorded, err := s.RecordPolicyMatch(ctx, match(1, models.MatchStatusPending), time.Now().Add(time.Hour), models.AuditEntry{}); err != nil || recorded {
+			t.Errorf("recording while pending: recorded %v, %v", recorded, err)
+		}
+		dryRun, recorded, err := s.RecordPolicyMatch(ctx, match(2, models.MatchStatusDryRun), longAgo, models.AuditEntry{})
+		if err != nil || !recorded {
+			t.Fatalf("dry run match = %+v, %v, %v", dryRun, recorded, err)
+		}
+		if _, recorded, err := s.RecordPolicyMatch(ctx, match(2, models.MatchStatusDryRun), longAgo, models.AuditEntry{}); err != nil || recorded {
+			t.Errorf("recording within the quiet period: recorded %v, %v", recorded, err)
+		}
+		again, recorded, err := s.RecordPolicyMatch(ctx, match(2, models.MatchStatusDryRun), time.Now().Add(time.Hour), models.AuditEntry{})
+		if err != nil || !recorded || again.ID == dryRun.ID {
+			t.Errorf("recording after the quiet period = %+v, %v, %v", again, recorded, err)
+		}
+
+		reviewed, err := s.ReviewPolicyMatch(ctx, pending.ID, models.MatchStatusDismissed, "analyst-2", "known merchant", audit("suspension_policy.match_dismissed"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reviewed.Status != models.MatchStatusDismissed || reviewed.ReviewedBy != "analyst-2" || reviewed.ReviewedAt == nil || reviewed.ReviewNote != "known merchant" {
+			t.Errorf("reviewed %+v", reviewed)
+		}
+		if _, err := s.ReviewPolicyMatch(ctx, pending.ID, models.MatchStatusApproved, "analyst-2", "", audit("suspension_policy.match_approved")); !errors.Is(err, ErrConflict) {
+			t.Errorf("reviewing twice: got %v, want ErrConflict", err)
+		}
+		if _, err := s.ReviewPolicyMatch(ctx, dryRun.ID, models.MatchStatusApproved, "analyst-2", "", audit("suspension_policy.match_approved")); !errors.Is(err, ErrConflict) {
+			t.Errorf("reviewing a dry run match: got %v, want ErrConflict", err)
+		}
+		if _, err := s.ReviewPolicyMatch(ctx, again.ID+100, models.MatchStatusApproved, "analyst-2", "", audit("suspension_policy.match_approved")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("reviewing a missing match: got %v, want ErrNotFound", err)
+		}
+		if _, err := s.GetPolicyMatch(ctx, again.ID+100); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetPolicyMatch on missing match: got %v, want ErrNotFound", err)
+		}
+		// the review restarts the quiet period
+		if _, recorded, err := s.RecordPolicyMatch(ctx, match(1, models.MatchStatusPending), reviewed.ReviewedAt.Add(-time.Second), models.AuditEntry{}); err != nil || recorded {
+			t.Errorf("recording after a recent review: recorded %v, %v", recorded, err)
+		}
+
+		list := func(f models.PolicyMatchFilter) []int64 {
+			t.Helper()
+			matches, err := s.ListPolicyMatches(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, m := range matches {
+				got = append(got, m.ID)
+			}
+			return got
+		}
+		if got := list(models.PolicyMatchFilter{}); !slices.Equal(got, []int64{again.ID, dryRun.ID, pending.ID}) {
+			t.Errorf("all matches = %v", got)
+		}
+		if got := list(models.PolicyMatchFilter{Status: models.MatchStatusDryRun, MerchantID: 2, PolicyID: p.ID}); !slices.Equal(got, []int64{again.ID, dryRun.ID}) {
+			t.Errorf("dry run matches = %v", got)
+		}
+		if got := list(models.PolicyMatchFilter{Limit: 1}); len(got) != 1 {
+			t.Errorf("limit 1 = %v", got)
+		}
+
+		log := s.auditLog(t)
+		if len(log) != 2 || log[1].Action != "suspension_policy.match_dismissed" || log[1].TargetID != itoa64(pending.ID) {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("suspending match suspends the merchant", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base))
+		p, err := s.CreatePolicy(ctx, policy("chargebacks"), audit("suspension_policy.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		match := func(merchantID int) models.PolicyMatch {
+			return models.PolicyMatch{PolicyID: p.ID, MerchantID: merchantID, Value: 0.03, Action: models.PolicyActionSuspend, Status: models.MatchStatusSuspended}
+		}
+		suspended := models.AuditEntry{Actor: "system", Action: "merchant.suspended", TargetType: "merchant", Details: map[string]interface{}{"policy_id": p.ID}}
+		longAgo := time.Now().Add(-24 * time.Hour)
+
+		m, recorded, err := s.RecordPolicyMatch(ctx, match(1), longAgo, suspended)
+		if err != nil || !recorded {
+			t.Fatalf("RecordPolicyMatch = %+v, %v, %v", m, recorded, err)
+		}
+		got, err := s.GetMerchant(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != "suspended" {
+			t.Errorf("merchant status = %q, want suspended", got.Status)
+		}
+		log := s.auditLog(t)
+		if last := log[len(log)-1]; last.Action != "merchant.suspended" || last.TargetID != "1" || last.Details["policy_match_id"] == nil {
+			t.Errorf("last audit entry %+v", last)
+		}
+
+		// a merchant that does not exist leaves no match behind
+		if _, _, err := s.RecordPolicyMatch(ctx, match(2), longAgo, suspended); !errors.Is(err, ErrNotFound) {
+			t.Errorf("suspending a missing merchant: got %v, want ErrNotFound", err)
+		}
+		matches, err := s.ListPolicyMatches(ctx, models.PolicyMatchFilter{MerchantID: 2})
+		if err != nil || len(matches) != 0 {
+			t.Errorf("matches for the missing merchant = %+v, %v", matches, err)
+		}
+	})
+
+	t.Run("dry run matches only hold back dry run matches", func(t *testing.T) {
+		s := newStore(t)
+		p, err := s.CreatePolicy(ctx, policy("chargebacks"), audit("suspension_policy.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		match := func(status string) models.PolicyMatch {
+			return models.PolicyMatch{PolicyID: p.ID, MerchantID: 1, Value: 0.03, Action: models.PolicyActionRecommend, Status: status}
+		}
+		longAgo := time.Now().Add(-24 * time.Hour)
+
+		if _, recorded, err := s.RecordPolicyMatch(ctx, match(models.MatchStatusDryRun), longAgo, models.AuditEntry{}); err != nil || !recorded {
+			t.Fatalf("dry run match: recorded %v, %v", recorded, err)
+		}
+		if _, recorded, err := s.RecordPolicyMatch(ctx, match(models.MatchStatusPending), longAgo, models.AuditEntry{}); err != nil || !recorded {
+			t.Errorf("match after leaving dry run: recorded %v, %v", recorded, err)
+		}
+		if _, recorded, err := s.RecordPolicyMatch(ctx, match(models.MatchStatusDryRun), longAgo, models.AuditEntry{}); err != nil || recorded {
+			t.Errorf("dry run match while pending: recorded %v, %v", recorded, err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// activityQuery aggregates each merchant's payments over the windows of an
+// ActivityQuery: $1 is its end, $2 the start of the ratio window, $3 the
+// start of the recent window and $4 the start of the baseline window. KYC
+// rejections are counted over the ratio window. Volumes are summed per
+// currency, as a JSON array, since amounts in different currencies can't be
+// added together.
+//
+// merchants.kyc_verified_at is written by the merchant service and may be
+// NULL, like the risk columns read by factsQuery.
+const activityQuery = `
+	SELECT
+		m.id,
+		m.status,
+		m.kyc_status,
+		m.kyc_verified_at,
+		m.created_at,
+		COUNT(t.id) FILTER (WHERE t.created_at >= $2),
+		COUNT(t.id) FILTER (WHERE t.created_at >= $2 AND t.status = 'failed'),
+		COUNT(t.id) FILTER (WHERE t.created_at >= $2 AND t.status = 'refunded'),
+		COUNT(t.id) FILTER (WHERE t.created_at >= $2 AND t.status = 'chargeback'),
+		(SELECT COUNT(*) FROM merchant_kyc_rejections k WHERE k.merchant_id = m.id AND k.created_at >= $2 AND k.created_at < $1),
+		(SELECT COALESCE(json_agg(json_build_object('currency', v.currency, 'recent', v.recent, 'baseline', v.baseline) ORDER BY v.currency), '[]')
+		 FROM (
+			SELECT s.currency,
+				COALESCE(SUM(s.amount) FILTER (WHERE s.created_at >= $3), 0) AS recent,
+				COALESCE(SUM(s.amount) FILTER (WHERE s.created_at < $3), 0) AS baseline
+			FROM transactions s
+			WHERE s.merchant_id = m.id AND s.status = 'successful' AND s.created_at >= $4 AND s.created_at < $1
+			GROUP BY s.currency
+		 ) v)
+	FROM merchants m
+	LEFT JOIN transactions t ON t.merchant_id = m.id AND t.created_at >= $2 AND t.created_at < $1
+	GROUP BY m.id
+	ORDER BY m.id`
+
+// riskFactorJSON is a risk factor as stored in merchant_risk_scores.factors.
+type riskFactorJSON struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+}
+
+// currencyVolumeJSON is an element of activityQuery's volumes column.
+type currencyVolumeJSON struct {
+	Currency string `json:"currency"`
+	Recent   int64  `json:"recent"`
+	Baseline int64  `json:"baseline"`
+}
+
+func encodeRiskFactors(factors []models.RiskFactor) ([]byte, error) {
+	stored := make([]riskFactorJSON, len(factors))
+	for i, f := range factors {
+		stored[i] = riskFactorJSON(f)
+	}
+	return json.Marshal(stored)
+}
+
+func decodeRiskFactors(data []byte) ([]models.RiskFactor, error) {
+	var stored []riskFactorJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	factors := make([]models.RiskFactor, len(stored))
+	for i, f := range stored {
+		factors[i] = models.RiskFactor(f)
+	}
+	return factors, nil
+}
+
+func (r *AdminRepository) MerchantActivity(ctx context.Context, q models.ActivityQuery) (_ []models.MerchantActivity, err error) {
+	ctx, span := tracing.StartDB(ctx, "MerchantActivity")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+
+	recent := q.Now.Add(-q.RecentWindow)
+	rows, err := r.db.QueryContext(ctx, activityQuery, q.Now, q.Now.Add(-q.RatioWindow), recent, recent.Add(-q.BaselineWindow))
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var activity []models.MerchantActivity
+	for rows.Next() {
+		var (
+			a        models.MerchantActivity
+			verified sql.NullTime
+			volumes  []byte
+			stored   []currencyVolumeJSON
+		)
+		if err := rows.Scan(&a.MerchantID, &a.Status, &a.KYCStatus, &verified, &a.CreatedAt,
+			&a.Transactions, &a.Failed, &a.Refunded, &a.Chargebacks, &a.KYCRejections, &volumes); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(volumes, &stored); err != nil {
+			return nil, err
+		}
+		for _, v := range stored {
+			a.Volumes = append(a.Volumes, models.CurrencyVolume(v))
+		}
+		if verified.Valid {
+			a.KYCVerifiedAt = &verified.Time
+		}
+		activity = append(activity, a)
+	}
+	return activity, rows.Err()
+}
+
+func (r *AdminRepository) SaveRiskScores(ctx context.Context, scores []models.RiskScore) (err error) {
+	ctx, span := tracing.StartDB(ctx, "SaveRiskScores")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return err
+	}
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO merchant_risk_scores (merchant_id, score, level, factors, computed_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (merchant_id) DO UPDATE SET
+				score = EXCLUDED.score,
+				level = EXCLUDED.level,
+				factors = EXCLUDED.factors,
+				computed_at = EXCLUDED.computed_at`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, s := range scores {
+			factors, err := encodeRiskFactors(s.Factors)
+			if err != nil {
+				return err
+			}
+			if _, err := stmt.ExecContext(ctx, s.MerchantID, s.Score, s.Level, factors, s.ComputedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
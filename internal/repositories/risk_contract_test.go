@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testMerchantRiskContract runs the merchant risk behaviour every AdminStore
+// must share; it is called from testAdminStoreContract.
+func testMerchantRiskContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	day := 24 * time.Hour
+
+	t.Run("MerchantActivity aggregates windows", func(t *testing.T) {
+		s := newStore(t)
+		verified := base.Add(-10 * day)
+		m := merchant(1, base.Add(-100*day))
+		m.KYCVerifiedAt = &verified
+		s.addMerchant(t, m)
+		s.addMerchant(t, merchant(2, base.Add(-time.Hour)))
+
+		s.addTransaction(t, transaction(1, 1, 100, "successful", base.Add(-time.Hour)))
+		s.addTransaction(t, transaction(2, 1, 200, "failed", base.Add(-2*day)))
+		s.addTransaction(t, transaction(3, 1, 400, "successful", base.Add(-10*day)))
+		s.addTransaction(t, transaction(4, 1, 800, "chargeback", base.Add(-20*day)))
+		s.addTransaction(t, transaction(5, 1, 1600, "refunded", base.Add(-29*day)))
+		usd := transaction(8, 1, 50, "successful", base.Add(-2*time.Hour))
+		usd.Currency = "USD"
+		s.addTransaction(t, usd)
+		// outside every window
+		s.addTransaction(t, transaction(6, 1, 3200, "successful", base.Add(-40*day)))
+		s.addTransaction(t, transaction(7, 1, 6400, "successful", base.Add(time.Minute)))
+		for _, at := range []time.Time{base.Add(-3 * day), base.Add(-40 * day)} {
+			if err := s.RecordKYCRejection(ctx, 1, at); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		activity, err := s.MerchantActivity(ctx, models.ActivityQuery{
+			Now: base, RatioWindow: 30 * day, RecentWindow: 7 * day, BaselineWindow: 28 * day,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(activity) != 2 {
+			t.Fatalf("got %d merchants, want 2", len(activity))
+		}
+		a := activity[0]
+		if a.MerchantID != 1 || a.Status != "active" || a.KYCStatus != "completed" || a.KYCVerifiedAt == nil || !a.KYCVerifiedAt.Equal(verified) || !a.CreatedAt.Equal(m.CreatedAt) {
+			t.Errorf("merchant fields %+v", a)
+		}
+		if a.Transactions != 6 || a.Failed != 1 || a.Chargebacks != 1 || a.Refunded != 1 || a.KYCRejections != 1 {
+			t.Errorf("counts %+v", a)
+		}
+		want := []models.CurrencyVolume{{Currency: "NGN", Recent: 100, Baseline: 400}, {Currency: "USD", Recent: 50, Baseline: 0}}
+		if !slices.Equal(a.Volumes, want) {
+			t.Errorf("volumes %+v, want %+v", a.Volumes, want)
+		}
+		if b := activity[1]; b.MerchantID != 2 || b.Transactions != 0 || b.KYCRejections != 0 || b.KYCVerifiedAt != nil {
+			t.Errorf("idle merchant %+v", b)
+		}
+	})
+
+	t.Run("risk scores filter and sort merchants", func(t *testing.T) {
+		s := newStore(t)
+		for i := 1; i <= 4; i++ {
+			s.addMerchant(t, merchant(i, base.Add(time.Duration(i)*time.Hour)))
+		}
+		factors := []models.RiskFactor{{Name: "chargeback_ratio", Value: 0.02, Weight: 30, Score: 30}}
+		scores := []models.RiskScore{
+			{MerchantID: 1, Score: 70, Level: models.RiskHigh, Factors: factors, ComputedAt: base},
+			{MerchantID: 2, Score: 10, Level: models.RiskLow, ComputedAt: base},
+			{MerchantID: 3, Score: 40, Level: models.RiskMedium, ComputedAt: base},
+		}
+		if err := s.SaveRiskScores(ctx, scores); err != nil {
+			t.Fatal(err)
+		}
+		// a recalculation replaces the score
+		scores[1].Score, scores[1].Level = 35, models.RiskMedium
+		if err := s.SaveRiskScores(ctx, scores[1:2]); err != nil {
+			t.Fatal(err)
+		}
+
+		list := func(f models.MerchantFilter) []int {
+			t.Helper()
+			merchants, err := s.ListMerchants(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return merchantIDs(merchants)
+		}
+		if got := list(models.MerchantFilter{SortByRisk: true}); !slices.Equal(got, []int{1, 3, 2, 4}) {
+			t.Errorf("riskiest first = %v, want [1 3 2 4]", got)
+		}
+		if got := list(models.MerchantFilter{RiskLevel: models.RiskMedium}); !slices.Equal(got, []int{3, 2}) {
+			t.Errorf("medium risk = %v, want [3 2]", got)
+		}
+		if got := list(models.MerchantFilter{MinRiskScore: 36, SortByRisk: true}); !slices.Equal(got, []int{1, 3}) {
+			t.Errorf("score >= 36 = %v, want [1 3]", got)
+		}
+
+		merchants, err := s.ListMerchants(ctx, models.MerchantFilter{Limit: 1, SortByRisk: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := merchants[0].Risk; r == nil || r.Score != 70 || r.Level != models.RiskHigh || !r.ComputedAt.Equal(base) || r.Factors != nil {
+			t.Errorf("listed risk %+v", r)
+		}
+
+		m, err := s.GetMerchant(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Risk == nil || !slices.Equal(m.Risk.Factors, factors) {
+			t.Errorf("GetMerchant risk %+v", m.Risk)
+		}
+		if m, err := s.GetMerchant(ctx, 4); err != nil || m.Risk != nil || m.Name != "Merchant 4" {
+			t.Errorf("GetMerchant(unscored) = %+v, %v", m, err)
+		}
+		if _, err := s.GetMerchant(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetMerchant(missing): got %v, want ErrNotFound", err)
+		}
+	})
+}
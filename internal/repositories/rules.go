@@ -0,0 +1,387 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// ruleQuery selects fraud rules with their current expression; callers
+// append WHERE and ORDER BY clauses.
+const ruleQuery = `
+	SELECT r.id, r.name, r.description, r.enabled, r.version, v.expression, r.created_by, r.created_at, r.updated_at
+	FROM fraud_rules r
+	JOIN fraud_rule_versions v ON v.rule_id = r.id AND v.version = r.version`
+
+func scanRule(row rowScanner) (models.FraudRule, error) {
+	var r models.FraudRule
+	err := row.Scan(&r.ID, &r.Name, &r.Description, &r.Enabled, &r.Version, &r.Expression, &r.CreatedBy, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+func (r *AdminRepository) CreateRule(ctx context.Context, rule models.FraudRule, audit models.AuditEntry) (_ models.FraudRule, err error) {
+	ctx, span := tracing.StartDB(ctx, "CreateRule")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudRule{}, err
+	}
+
+	var id int64
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO fraud_rules (name, description, enabled, created_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id`, rule.Name, rule.Description, rule.Enabled, rule.CreatedBy).Scan(&id)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("rule %q: %w", rule.Name, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO fraud_rule_versions (rule_id, version, expression, created_by)
+			VALUES ($1, 1, $2, $3)`, id, rule.Expression, rule.CreatedBy); err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	return r.GetRule(ctx, id)
+}
+
+func (r *AdminRepository) ReviseRule(ctx context.Context, id int64, expression, author string, audit models.AuditEntry) (_ models.FraudRule, err error) {
+	ctx, span := tracing.StartDB(ctx, "ReviseRule")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudRule{}, err
+	}
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var version int
+		err := tx.QueryRowContext(ctx, `
+			UPDATE fraud_rules SET version = version + 1, updated_at = NOW()
+			WHERE id = $1
+			RETURNING version`, id).Scan(&version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("rule %d: %w", id, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO fraud_rule_versions (rule_id, version, expression, created_by)
+			VALUES ($1, $2, $3, $4)`, id, version, expression, author); err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	return r.GetRule(ctx, id)
+}
+
+func (r *AdminRepository) SetRuleEnabled(ctx context.Context, id int64, enabled bool, audit models.AuditEntry) (_ models.FraudRule, err error) {
+	ctx, span := tracing.StartDB(ctx, "SetRuleEnabled")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudRule{}, err
+	}
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE fraud_rules SET enabled = $2, updated_at = NOW() WHERE id = $1`, id, enabled)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("rule %d: %w", id, ErrNotFound)
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, id))
+	})
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	return r.GetRule(ctx, id)
+}
+
+func (r *AdminRepository) GetRule(ctx context.Context, id int64) (_ models.FraudRule, err error) {
+	ctx, span := tracing.StartDB(ctx, "GetRule")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.FraudRule{}, err
+	}
+	rule, err := scanRule(r.db.QueryRowContext(ctx, ruleQuery+` WHERE r.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.FraudRule{}, fmt.Errorf("rule %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return models.FraudRule{}, r.wrapErr(err)
+	}
+	return rule, nil
+}
+
+func (r *AdminRepository) ListRules(ctx context.Context, enabledOnly bool) (_ []models.FraudRule, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListRules")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	query := ruleQuery
+	if enabledOnly {
+		query += ` WHERE r.enabled`
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY r.name`)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	rules := []models.FraudRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *AdminRepository) ListRuleVersions(ctx context.Context, id int64) (_ []models.FraudRuleVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListRuleVersions")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rule_id, version, expression, created_by, created_at
+		FROM fraud_rule_versions
+		WHERE rule_id = $1
+		ORDER BY version DESC`, id)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	versions := []models.FraudRuleVersion{}
+	for rows.Next() {
+		var v models.FraudRuleVersion
+		if err := rows.Scan(&v.RuleID, &v.Version, &v.Expression, &v.CreatedBy, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// velocityKeys are the SQL expressions transactions must share to count
+// towards each other's velocity. NULL keys only count the transaction itself.
+var velocityKeys = map[string]string{
+	models.VelocityCard:     "NULLIF(t.card_fingerprint, '')",
+	models.VelocityEmail:    "NULLIF(LOWER(t.customer_email), '')",
+	models.VelocityIP:       "NULLIF(t.ip_address, '')",
+	models.VelocityMerchant: "t.merchant_id",
+}
+
+// factsQuery builds the query behind EachTransactionFacts. Velocity windows
+// become window functions over transactions since From less the longest
+// window, so transactions early in the range see their full history. The
+// latest final fraud review decision is joined last.
+//
+// The risk columns on transactions (card_fingerprint, ip_address,
+// card_country, ip_country) and merchants (category, country) are written by
+// the transaction and merchant services and may be NULL.
+func factsQuery(q models.FactQuery) (string, []interface{}, error) {
+	var (
+		cols, windows []string
+		longest       time.Duration
+	)
+	for i, w := range q.Windows {
+		key, ok := velocityKeys[w.Dimension]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown velocity dimension %q", w.Dimension)
+		}
+		name := "w" + strconv.Itoa(i)
+		cols = append(cols,
+			fmt.Sprintf("CASE WHEN %s IS NULL THEN 1 ELSE COUNT(*) OVER %s END", key, name),
+			fmt.Sprintf("CASE WHEN %s IS NULL THEN t.amount ELSE SUM(t.amount) OVER %s END", key, name))
+		windows = append(windows, fmt.Sprintf(
+			"%s AS (PARTITION BY %s ORDER BY t.created_at RANGE BETWEEN INTERVAL '%d seconds' PRECEDING AND CURRENT ROW)",
+			name, key, int64(w.Window/time.Second)))
+		longest = max(longest, w.Window)
+	}
+
+	query := `
+		SELECT f.*, COALESCE(fr.decision, '') FROM (
+			SELECT
+				t.id,
+				t.reference,
+				t.merchant_id,
+				t.customer_email,
+				t.amount,
+				t.currency,
+				t.status,
+				t.payment_method,
+				COALESCE(t.card_fingerprint, ''),
+				COALESCE(t.ip_address, ''),
+				COALESCE(t.card_country, ''),
+				COALESCE(t.ip_country, ''),
+				COALESCE(m.category, ''),
+				COALESCE(m.country, ''),
+				m.created_at AS merchant_created_at,
+				t.created_at`
+	for _, c := range cols {
+		query += ",\n\t\t\t\t" + c
+	}
+	query += `
+			FROM transactions t
+			JOIN merchants m ON m.id = t.merchant_id
+			WHERE t.created_at >= $1 AND t.created_at < $3`
+	if len(windows) > 0 {
+		query += "\n\t\t\tWINDOW " + strings.Join(windows, ",\n\t\t\t\t")
+	}
+	query += `
+		) f
+		LEFT JOIN LATERAL (
+			SELECT decision FROM fraud_reviews r
+			WHERE r.transaction_id = f.id AND r.decision IN ('approved', 'declined')
+			ORDER BY r.created_at DESC, r.id DESC
+			LIMIT 1
+		) fr ON TRUE
+		WHERE f.created_at >= $2
+		ORDER BY f.created_at, f.id`
+	return query, []interface{}{q.From.Add(-longest), q.From, q.To}, nil
+}
+
+func (r *AdminRepository) EachTransactionFacts(ctx context.Context, q models.FactQuery, fn func(models.TransactionFacts) error) (err error) {
+	ctx, span := tracing.StartDB(ctx, "EachTransactionFacts")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return err
+	}
+	query, args, err := factsQuery(q)
+	if err != nil {
+		return err
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	stats := make([]models.VelocityStat, len(q.Windows))
+	for rows.Next() {
+		var (
+			f             models.TransactionFacts
+			paymentMethod sql.NullString
+		)
+		dest := []interface{}{&f.ID, &f.Reference, &f.MerchantID, &f.CustomerEmail, &f.Amount, &f.Currency, &f.Status,
+			&paymentMethod, &f.CardFingerprint, &f.IPAddress, &f.CardCountry, &f.IPCountry,
+			&f.MerchantCategory, &f.MerchantCountry, &f.MerchantCreatedAt, &f.CreatedAt}
+		for i := range stats {
+			dest = append(dest, &stats[i].Count, &stats[i].Volume)
+		}
+		dest = append(dest, &f.ReviewDecision)
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		f.PaymentMethod = nullString(paymentMethod)
+		f.Velocity = make(map[models.VelocityWindow]models.VelocityStat, len(q.Windows))
+		for i, w := range q.Windows {
+			f.Velocity[w] = stats[i]
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return r.wrapErr(rows.Err())
+}
+
+func (r *AdminRepository) RecordRuleFlags(ctx context.Context, flags []models.RuleFlag) (_ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "RecordRuleFlags")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return 0, err
+	}
+	if len(flags) == 0 {
+		return 0, nil
+	}
+
+	ruleIDs := make([]int64, len(flags))
+	versions := make([]int64, len(flags))
+	txIDs := make([]int64, len(flags))
+	for i, f := range flags {
+		ruleIDs[i], versions[i], txIDs[i] = f.RuleID, int64(f.RuleVersion), int64(f.TransactionID)
+	}
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO fraud_rule_flags (rule_id, rule_version, transaction_id)
+		SELECT * FROM unnest($1::bigint[], $2::integer[], $3::integer[])
+		ON CONFLICT (rule_id, rule_version, transaction_id) DO NOTHING`,
+		pq.Array(ruleIDs), pq.Array(versions), pq.Array(txIDs))
+	if err != nil {
+		return 0, r.wrapErr(err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (r *AdminRepository) ListRuleFlags(ctx context.Context, f models.FlagFilter) (_ []models.RuleFlag, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListRuleFlags")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.RuleID != 0 {
+		where = append(where, "f.rule_id = "+arg(f.RuleID))
+	}
+	if f.TransactionID != 0 {
+		where = append(where, "f.transaction_id = "+arg(f.TransactionID))
+	}
+	query := `
+		SELECT f.id, f.rule_id, r.name, f.rule_version, f.transaction_id, f.created_at
+		FROM fraud_rule_flags f
+		JOIN fraud_rules r ON r.id = f.rule_id`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY f.created_at DESC, f.id DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	flags := []models.RuleFlag{}
+	for rows.Next() {
+		var fl models.RuleFlag
+		if err := rows.Scan(&fl.ID, &fl.RuleID, &fl.RuleName, &fl.RuleVersion, &fl.TransactionID, &fl.CreatedAt); err != nil {
+			return nil, err
+		}
+		flags = append(flags, fl)
+	}
+	return flags, rows.Err()
+}
@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testFraudRuleContract runs the fraud rule behaviour every AdminStore must
+// share; it is called from testAdminStoreContract.
+func testFraudRuleContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "fraud_rule"}
+	}
+	newRule := func(name, expression string) models.FraudRule {
+		return models.FraudRule{Name: name, Description: "test rule", Expression: expression, CreatedBy: "analyst-1"}
+	}
+
+	t.Run("rules are versioned", func(t *testing.T) {
+		s := newStore(t)
+		r, err := s.CreateRule(ctx, newRule("card velocity", `velocity("card", "1h") > 5`), audit("fraud_rule.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.ID == 0 || r.Version != 1 || r.Enabled || r.Expression != `velocity("card", "1h") > 5` || r.CreatedAt.IsZero() {
+			t.Fatalf("created %+v", r)
+		}
+		if _, err := s.CreateRule(ctx, newRule("card velocity", `amount > 1`), audit("fraud_rule.created")); !errors.Is(err, ErrConflict) {
+			t.Fatalf("duplicate name: got %v, want ErrConflict", err)
+		}
+
+		r, err = s.ReviseRule(ctx, r.ID, `velocity("card", "1h") > 3`, "analyst-2", audit("fraud_rule.revised"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Version != 2 || r.Expression != `velocity("card", "1h") > 3` {
+			t.Fatalf("revised %+v", r)
+		}
+		versions, err := s.ListRuleVersions(ctx, r.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 || versions[0].Version != 2 || versions[0].CreatedBy != "analyst-2" || versions[1].Expression != `velocity("card", "1h") > 5` {
+			t.Fatalf("versions %+v", versions)
+		}
+
+		if _, err := s.ReviseRule(ctx, r.ID+100, `amount > 1`, "analyst-2", audit("fraud_rule.revised")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("ReviseRule on missing rule: got %v, want ErrNotFound", err)
+		}
+		if _, err := s.GetRule(ctx, r.ID+100); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetRule on missing rule: got %v, want ErrNotFound", err)
+		}
+
+		log := s.auditLog(t)
+		if len(log) != 2 || log[0].Action != "fraud_rule.created" || log[1].TargetID != itoa64(r.ID) {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("ListRules filters enabled rules", func(t *testing.T) {
+		s := newStore(t)
+		b, err := s.CreateRule(ctx, newRule("b", `amount > 1`), audit("fraud_rule.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.CreateRule(ctx, newRule("a", `amount > 2`), audit("fraud_rule.created")); err != nil {
+			t.Fatal(err)
+		}
+		if b, err = s.SetRuleEnabled(ctx, b.ID, true, audit("fraud_rule.enabled")); err != nil || !b.Enabled {
+			t.Fatalf("SetRuleEnabled = %+v, %v", b, err)
+		}
+		if _, err := s.SetRuleEnabled(ctx, b.ID+100, true, audit("fraud_rule.enabled")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("SetRuleEnabled on missing rule: got %v, want ErrNotFound", err)
+		}
+
+		all, err := s.ListRules(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 2 || all[0].Name != "a" || all[1].Name != "b" {
+			t.Errorf("ListRules(all) = %+v", all)
+		}
+		enabled, err := s.ListRules(ctx, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(enabled) != 1 || enabled[0].ID != b.ID {
+			t.Errorf("ListRules(enabled) = %+v", enabled)
+		}
+	})
+
+	t.Run("EachTransactionFacts computes velocity", func(t *testing.T) {
+		s := newStore(t)
+		base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		m := merchant(1, base.Add(-48*time.Hour))
+		m.Category, m.Country = "gambling", "NG"
+		s.addMerchant(t, m)
+		card := func(tx models.Transaction, fp, ip string) models.Transaction {
+			tx.CardFingerprint, tx.IPAddress, tx.CardCountry, tx.IPCountry = fp, ip, "NG", "GB"
+			return tx
+		}
+		// 1 is before the range but inside the window of 2 and 3
+		s.addTransaction(t, card(transaction(1, 1, 100, "successful", base.Add(-30*time.Minute)), "fp-1", "10.0.0.1"))
+		s.addTransaction(t, card(transaction(2, 1, 200, "failed", base), "fp-1", ""))
+		s.addTransaction(t, card(transaction(3, 1, 400, "successful", base.Add(20*time.Minute)), "fp-1", "10.0.0.1"))
+		s.addTransaction(t, card(transaction(4, 1, 800, "successful", base.Add(2*time.Hour)), "", "10.0.0.1"))
+		s.addTransaction(t, card(transaction(5, 1, 1600, "successful", base.Add(5*time.Hour)), "fp-1", ""))
+
+		cardHour := models.VelocityWindow{Dimension: models.VelocityCard, Window: time.Hour}
+		ipDay := models.VelocityWindow{Dimension: models.VelocityIP, Window: 24 * time.Hour}
+		var got []models.TransactionFacts
+		err := s.EachTransactionFacts(ctx, models.FactQuery{
+			From: base, To: base.Add(5 * time.Hour), Windows: []models.VelocityWindow{cardHour, ipDay},
+		}, func(f models.TransactionFacts) error {
+			got = append(got, f)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []struct {
+			id       int
+			card, ip models.VelocityStat
+		}{
+			{2, models.VelocityStat{Count: 2, Volume: 300}, models.VelocityStat{Count: 1, Volume: 200}},
+			{3, models.VelocityStat{Count: 3, Volume: 700}, models.VelocityStat{Count: 2, Volume: 500}},
+			{4, models.VelocityStat{Count: 1, Volume: 800}, models.VelocityStat{Count: 3, Volume: 1300}},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got %d facts, want %d", len(got), len(want))
+		}
+		for i, w := range want {
+			f := got[i]
+			if f.ID != w.id || f.Velocity[cardHour] != w.card || f.Velocity[ipDay] != w.ip {
+				t.Errorf("facts[%d] = id %d card %+v ip %+v, want id %d card %+v ip %+v",
+					i, f.ID, f.Velocity[cardHour], f.Velocity[ipDay], w.id, w.card, w.ip)
+			}
+		}
+		f := got[0]
+		if f.MerchantCategory != "gambling" || f.MerchantCountry != "NG" || !f.MerchantCreatedAt.Equal(m.CreatedAt) ||
+			f.CardCountry != "NG" || f.IPCountry != "GB" || f.CardFingerprint != "fp-1" || f.Status != "failed" {
+			t.Errorf("facts[0] = %+v", f)
+		}
+
+		stop := errors.New("stop")
+		calls := 0
+		err = s.EachTransactionFacts(ctx, models.FactQuery{From: base, To: base.Add(5 * time.Hour)}, func(models.TransactionFacts) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) || calls != 1 {
+			t.Errorf("stopping early: err %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("EachTransactionFacts reports the latest final review", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base.Add(-48*time.Hour)))
+		for i := 1; i <= 3; i++ {
+			s.addTransaction(t, transaction(i, 1, 100, "successful", base.Add(time.Duration(i)*time.Minute)))
+		}
+		for _, d := range []models.FraudDecision{
+			{TransactionID: 1, Decision: models.FraudDecisionEscalated},
+			{TransactionID: 1, Decision: models.FraudDecisionDeclined},
+			{TransactionID: 2, Decision: models.FraudDecisionEscalated},
+			{TransactionID: 2, Decision: models.FraudDecisionApproved},
+			{TransactionID: 3, Decision: models.FraudDecisionEscalated},
+		} {
+			d.Reason, d.Reviewer = "checked", "analyst-1"
+			if _, err := s.RecordFraudDecision(ctx, d, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got := map[int]string{}
+		err := s.EachTransactionFacts(ctx, models.FactQuery{From: base, To: base.Add(time.Hour)}, func(f models.TransactionFacts) error {
+			got[f.ID] = f.ReviewDecision
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[int]string{1: models.FraudDecisionDeclined, 2: models.FraudDecisionApproved, 3: ""}
+		if !maps.Equal(got, want) {
+			t.Errorf("review decisions = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("RecordRuleFlags skips duplicates", func(t *testing.T) {
+		s := newStore(t)
+		r, err := s.CreateRule(ctx, newRule("big", `amount > 1`), audit("fraud_rule.created"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		flags := []models.RuleFlag{
+			{RuleID: r.ID, RuleVersion: 1, TransactionID: 10},
+			{RuleID: r.ID, RuleVersion: 1, TransactionID: 11},
+		}
+		if n, err := s.RecordRuleFlags(ctx, flags); err != nil || n != 2 {
+			t.Fatalf("RecordRuleFlags = %d, %v", n, err)
+		}
+		flags = append(flags, models.RuleFlag{RuleID: r.ID, RuleVersion: 2, TransactionID: 10})
+		if n, err := s.RecordRuleFlags(ctx, flags); err != nil || n != 1 {
+			t.Fatalf("RecordRuleFlags with duplicates = %d, %v", n, err)
+		}
+
+		got, err := s.ListRuleFlags(ctx, models.FlagFilter{TransactionID: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].RuleVersion != 2 || got[0].RuleName != "big" || got[0].CreatedAt.IsZero() {
+			t.Errorf("ListRuleFlags(transaction 10) = %+v", got)
+		}
+		if got, err := s.ListRuleFlags(ctx, models.FlagFilter{RuleID: r.ID, Limit: 1}); err != nil || len(got) != 1 {
+			t.Errorf("ListRuleFlags(limit 1) = %+v, %v", got, err)
+		}
+	})
+}
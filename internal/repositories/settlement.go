@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/tracing"
+)
+
+// settlementHoldReplaced is the release reason of a hold replaced by another.
+const settlementHoldReplaced = "replaced by a new settlement hold"
+
+const settlementHoldColumns = `id, merchant_id, type, reserve_percent, release_days, reserve_amount, currency, reason,
+	created_by, created_at, COALESCE(released_by, ''), released_at, COALESCE(release_reason, '')`
+
+func scanSettlementHold(row rowScanner) (models.SettlementHold, error) {
+	var (
+		h        models.SettlementHold
+		released sql.NullTime
+	)
+	err := row.Scan(&h.ID, &h.MerchantID, &h.Type, &h.ReservePercent, &h.ReleaseDays, &h.ReserveAmount, &h.Currency, &h.Reason,
+		&h.CreatedBy, &h.CreatedAt, &h.ReleasedBy, &released, &h.ReleaseReason)
+	if released.Valid {
+		h.ReleasedAt = &released.Time
+	}
+	return h, err
+}
+
+func (r *AdminRepository) SetSettlementHold(ctx context.Context, h models.SettlementHold, audit models.AuditEntry) (_ models.SettlementHold, err error) {
+	ctx, span := tracing.StartDB(ctx, "SetSettlementHold")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.SettlementHold{}, err
+	}
+
+	var created models.SettlementHold
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE settlement_holds SET
+				released_by = $2,
+				released_at = NOW(),
+				release_reason = $3
+			WHERE merchant_id = $1 AND released_at IS NULL`,
+			h.MerchantID, h.CreatedBy, settlementHoldReplaced); err != nil {
+			return err
+		}
+		var err error
+		created, err = scanSettlementHold(tx.QueryRowContext(ctx, `
+			INSERT INTO settlement_holds (merchant_id, type, reserve_percent, release_days, reserve_amount, currency, reason, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+settlementHoldColumns,
+			h.MerchantID, h.Type, h.ReservePercent, h.ReleaseDays, h.ReserveAmount, h.Currency, h.Reason, h.CreatedBy))
+		if isUniqueViolation(err) {
+			return fmt.Errorf("settlement hold for merchant %d: %w", h.MerchantID, ErrConflict)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, int64(h.MerchantID)))
+	})
+	if err != nil {
+		return models.SettlementHold{}, err
+	}
+	return created, nil
+}
+
+func (r *AdminRepository) ReleaseSettlementHold(ctx context.Context, merchantID int, by, reason string, audit models.AuditEntry) (_ models.SettlementHold, err error) {
+	ctx, span := tracing.StartDB(ctx, "ReleaseSettlementHold")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return models.SettlementHold{}, err
+	}
+
+	var released models.SettlementHold
+	err = r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		released, err = scanSettlementHold(tx.QueryRowContext(ctx, `
+			UPDATE settlement_holds SET
+				released_by = $2,
+				released_at = NOW(),
+				release_reason = $3
+			WHERE merchant_id = $1 AND released_at IS NULL
+			RETURNING `+settlementHoldColumns,
+			merchantID, by, reason))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("settlement hold for merchant %d: %w", merchantID, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, auditTarget(audit, int64(merchantID)))
+	})
+	if err != nil {
+		return models.SettlementHold{}, err
+	}
+	return released, nil
+}
+
+func (r *AdminRepository) ListSettlementHolds(ctx context.Context, f models.SettlementHoldFilter) (_ []models.SettlementHold, err error) {
+	ctx, span := tracing.StartDB(ctx, "ListSettlementHolds")
+	defer tracing.End(span, &err)
+	if err := r.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.MerchantID != 0 {
+		where = append(where, "merchant_id = "+arg(f.MerchantID))
+	}
+	if f.Type != "" {
+		where = append(where, "type = "+arg(f.Type))
+	}
+	if f.ActiveOnly {
+		where = append(where, "released_at IS NULL")
+	}
+	query := `SELECT ` + settlementHoldColumns + ` FROM settlement_holds`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.wrapErr(err)
+	}
+	defer rows.Close()
+
+	var holds []models.SettlementHold
+	for rows.Next() {
+		h, err := scanSettlementHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, h)
+	}
+	return holds, rows.Err()
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// testSettlementHoldContract runs the settlement hold behaviour every
+// AdminStore must share; it is called from testAdminStoreContract.
+func testSettlementHoldContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+	audit := func(action string) models.AuditEntry {
+		return models.AuditEntry{Actor: "analyst-1", Action: action, TargetType: "merchant"}
+	}
+
+	t.Run("set, replace and release", func(t *testing.T) {
+		s := newStore(t)
+		full, err := s.SetSettlementHold(ctx, models.SettlementHold{
+			MerchantID: 1, Type: models.SettlementFullHold, Reason: "chargeback spike", CreatedBy: "analyst-1",
+		}, audit("settlement_hold.set"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if full.ID == 0 || full.CreatedAt.IsZero() || full.ReleasedAt != nil {
+			t.Errorf("created %+v", full)
+		}
+		reserve, err := s.SetSettlementHold(ctx, models.SettlementHold{
+			MerchantID: 1, Type: models.SettlementRollingReserve, ReservePercent: 10, ReleaseDays: 90,
+			Reason: "volume back to normal", CreatedBy: "analyst-2",
+		}, audit("settlement_hold.set"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reserve.ReservePercent != 10 || reserve.ReleaseDays != 90 || reserve.CreatedBy != "analyst-2" {
+			t.Errorf("rolling reserve %+v", reserve)
+		}
+		other, err := s.SetSettlementHold(ctx, models.SettlementHold{
+			MerchantID: 2, Type: models.SettlementFixedReserve, ReserveAmount: 5000000, Currency: "NGN",
+			Reason: "new high-risk category", CreatedBy: "analyst-1",
+		}, audit("settlement_hold.set"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		history, err := s.ListSettlementHolds(ctx, models.SettlementHoldFilter{MerchantID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 2 || history[0].ID != reserve.ID || history[1].ID != full.ID {
+			t.Fatalf("history %+v", history)
+		}
+		if replaced := history[1]; replaced.ReleasedAt == nil || replaced.ReleasedBy != "analyst-2" || replaced.ReleaseReason != settlementHoldReplaced {
+			t.Errorf("replaced hold %+v", replaced)
+		}
+
+		released, err := s.ReleaseSettlementHold(ctx, 1, "analyst-3", "reserve period agreed", audit("settlement_hold.released"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if released.ID != reserve.ID || released.ReleasedAt == nil || released.ReleasedBy != "analyst-3" || released.ReleaseReason != "reserve period agreed" {
+			t.Errorf("released %+v", released)
+		}
+		if _, err := s.ReleaseSettlementHold(ctx, 1, "analyst-3", "again", audit("settlement_hold.released")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("releasing without a hold in effect: got %v, want ErrNotFound", err)
+		}
+
+		ids := func(f models.SettlementHoldFilter) []int64 {
+			t.Helper()
+			holds, err := s.ListSettlementHolds(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, h := range holds {
+				got = append(got, h.ID)
+			}
+			return got
+		}
+		if got := ids(models.SettlementHoldFilter{ActiveOnly: true}); !slices.Equal(got, []int64{other.ID}) {
+			t.Errorf("active holds = %v", got)
+		}
+		if got := ids(models.SettlementHoldFilter{Type: models.SettlementFullHold}); !slices.Equal(got, []int64{full.ID}) {
+			t.Errorf("full holds = %v", got)
+		}
+		if got := ids(models.SettlementHoldFilter{Limit: 1}); len(got) != 1 {
+			t.Errorf("limit 1 = %v", got)
+		}
+
+		log := s.auditLog(t)
+		if len(log) != 4 || log[0].TargetID != "1" || log[2].TargetID != "2" || log[3].Action != "settlement_hold.released" {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+}
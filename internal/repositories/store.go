@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// AdminStore is the persistence the admin service depends on. AdminRepository
+// implements it over Postgres and MemoryStore in memory; both must pass the
+// contract tests in store_contract_test.go.
+type AdminStore interface {
+	// ListMerchants returns up to f.Limit matching merchants with their risk
+	// score summaries. A limit of zero or less returns up to 100.
+	ListMerchants(ctx context.Context, f models.MerchantFilter) ([]models.Merchant, error)
+	// GetMerchant returns a merchant with its risk score and factors, or
+	// ErrNotFound.
+	GetMerchant(ctx context.Context, id int) (models.Merchant, error)
+	// UpdateMerchantStatus sets a merchant's status and writes the audit
+	// entry in the same transaction, its TargetID defaulting to the merchant
+	// ID. It returns ErrNotFound when no merchant has the ID.
+	UpdateMerchantStatus(ctx context.Context, id int, status string, audit models.AuditEntry) error
+	GetStats(ctx context.Context) (models.PlatformStats, error)
+	// ListTransactions returns up to limit payments, newest first.
+	ListTransactions(ctx context.Context, limit int) ([]models.Transaction, error)
+
+	// RecordFraudDecision stores a fraud review decision and its audit log
+	// entry atomically, returning the decision with ID and CreatedAt set. It
+	// returns ErrConflict once the transaction has an approval or decline,
+	// including one being recorded concurrently. apply, when set, runs after
+	// the decision is reserved and before it is stored; its error is
+	// returned as is and nothing is stored.
+	RecordFraudDecision(ctx context.Context, d models.FraudDecision, apply func(ctx context.Context) error) (models.FraudDecision, error)
+	// ListFraudDecisions returns the decisions on a transaction, newest first.
+	ListFraudDecisions(ctx context.Context, transactionID int) ([]models.FraudDecision, error)
+
+	FraudCaseStore
+	FraudRuleStore
+	BlocklistStore
+	MerchantRiskStore
+	MerchantAlertStore
+	SuspensionPolicyStore
+	PayoutReviewStore
+	SettlementHoldStore
+}
+
+// FraudCaseStore persists fraud cases. Methods taking an audit entry write it
+// in the same transaction as the change; its TargetID defaults to the case ID.
+// Methods on a single case return ErrNotFound when it does not exist.
+type FraudCaseStore interface {
+	// FindOpenCase returns the unresolved case for a group, or ErrNotFound.
+	FindOpenCase(ctx context.Context, groupType, groupKey string) (models.FraudCase, error)
+	// OpenCase returns the unresolved case for a group, creating it when
+	// there is none. The audit entry is only written for a new case.
+	OpenCase(ctx context.Context, groupType, groupKey string, audit models.AuditEntry) (c models.FraudCase, created bool, err error)
+	// CaseForTransaction returns the ID of the case holding a transaction, or
+	// ErrNotFound.
+	CaseForTransaction(ctx context.Context, transactionID int) (int64, error)
+	// AddCaseTransaction adds a flagged transaction to a case, reporting
+	// false when it already belongs to one.
+	AddCaseTransaction(ctx context.Context, t models.CaseTransaction) (bool, error)
+	// ListCases returns matching cases, newest first. A limit of zero or less
+	// returns up to 100.
+	ListCases(ctx context.Context, f models.CaseFilter) ([]models.FraudCase, error)
+	GetCase(ctx context.Context, id int64) (models.FraudCase, error)
+	// ListCaseTransactions returns a case's transactions, most recently flagged first.
+	ListCaseTransactions(ctx context.Context, caseID int64) ([]models.CaseTransaction, error)
+	// ListCaseComments returns a case's comments, oldest first.
+	ListCaseComments(ctx context.Context, caseID int64) ([]models.CaseComment, error)
+	// ListCaseAttachments returns a case's attachments without their data, oldest first.
+	ListCaseAttachments(ctx context.Context, caseID int64) ([]models.CaseAttachment, error)
+	GetCaseAttachment(ctx context.Context, caseID, id int64) (models.CaseAttachment, error)
+	AssignCase(ctx context.Context, id int64, assignee string, audit models.AuditEntry) (models.FraudCase, error)
+	// SetCaseStatus changes a case's status. Resolving records the resolution
+	// and time; any other status clears them. It returns ErrConflict when
+	// reopening would leave two unresolved cases for the same group.
+	SetCaseStatus(ctx context.Context, id int64, status, resolution string, audit models.AuditEntry) (models.FraudCase, error)
+	AddCaseComment(ctx context.Context, c models.CaseComment, audit models.AuditEntry) (models.CaseComment, error)
+	AddCaseAttachment(ctx context.Context, a models.CaseAttachment, audit models.AuditEntry) (models.CaseAttachment, error)
+}
+
+// FraudRuleStore persists fraud rules, their versions and the flags they
+// raise, and streams the transaction history rules are evaluated against.
+// Methods taking an audit entry write it in the same transaction as the
+// change; its TargetID defaults to the rule ID. Methods on a single rule
+// return ErrNotFound when it does not exist.
+type FraudRuleStore interface {
+	// CreateRule stores a rule at version 1, returning ErrConflict when its
+	// name is taken.
+	CreateRule(ctx context.Context, r models.FraudRule, audit models.AuditEntry) (models.FraudRule, error)
+	// ReviseRule stores a new version of a rule's expression and makes it current.
+	ReviseRule(ctx context.Context, id int64, expression, author string, audit models.AuditEntry) (models.FraudRule, error)
+	SetRuleEnabled(ctx context.Context, id int64, enabled bool, audit models.AuditEntry) (models.FraudRule, error)
+	GetRule(ctx context.Context, id int64) (models.FraudRule, error)
+	// ListRules returns rules by name, only the enabled ones if enabledOnly is set.
+	ListRules(ctx context.Context, enabledOnly bool) ([]models.FraudRule, error)
+	// ListRuleVersions returns a rule's versions, newest first.
+	ListRuleVersions(ctx context.Context, id int64) ([]models.FraudRuleVersion, error)
+	// EachTransactionFacts streams the transactions q selects to fn, oldest
+	// first, stopping at the first error fn returns. Transactions whose
+	// merchant no longer exists are skipped.
+	EachTransactionFacts(ctx context.Context, q models.FactQuery, fn func(models.TransactionFacts) error) error
+	// RecordRuleFlags stores flags, skipping any already recorded for the same
+	// rule version and transaction, and reports how many were new.
+	RecordRuleFlags(ctx context.Context, flags []models.RuleFlag) (int, error)
+	// ListRuleFlags returns matching flags, newest first.
+	ListRuleFlags(ctx context.Context, f models.FlagFilter) ([]models.RuleFlag, error)
+}
+
+// BlocklistStore persists blocklist entries. Values are stored as given, so
+// callers normalise them first. Methods taking an audit entry write it in the
+// same transaction as the change.
+type BlocklistStore interface {
+	// AddBlocklistEntry blocks a value, returning ErrConflict while an active
+	// entry blocks it. An expired entry for the value is reused, keeping its
+	// ID. The audit entry's TargetID defaults to the entry ID.
+	AddBlocklistEntry(ctx context.Context, e models.BlocklistEntry, audit models.AuditEntry) (models.BlocklistEntry, error)
+	// ImportBlocklistEntries adds entries like AddBlocklistEntry in a single
+	// transaction, skipping values that are already blocked, and reports how
+	// many were added. Entries must not repeat a value.
+	ImportBlocklistEntries(ctx context.Context, entries []models.BlocklistEntry, audit models.AuditEntry) (int, error)
+	// ExpireBlocklistEntry sets when an active entry expires, returning
+	// ErrNotFound when it does not exist and ErrConflict when it has already
+	// expired. The audit entry's TargetID defaults to the entry ID.
+	ExpireBlocklistEntry(ctx context.Context, id int64, at time.Time, audit models.AuditEntry) (models.BlocklistEntry, error)
+	// ListBlocklist returns matching entries, newest first. A limit of zero
+	// or less returns up to 100.
+	ListBlocklist(ctx context.Context, f models.BlocklistFilter) ([]models.BlocklistEntry, error)
+	// ActiveBlocklist returns every entry that has not expired.
+	ActiveBlocklist(ctx context.Context) ([]models.BlocklistEntry, error)
+}
+
+// MerchantRiskStore persists merchant risk scores.
+type MerchantRiskStore interface {
+	// MerchantActivity returns the activity of every merchant over q's
+	// windows, by merchant ID.
+	MerchantActivity(ctx context.Context, q models.ActivityQuery) ([]models.MerchantActivity, error)
+	// SaveRiskScores replaces the stored scores of the scored merchants.
+	SaveRiskScores(ctx context.Context, scores []models.RiskScore) error
+}
+
+// MerchantAlertStore persists the alerts raised on anomalies in merchants'
+// payments. Methods taking an audit entry write it in the same transaction as
+// the change; its TargetID defaults to the alert ID. Methods on a single
+// alert return ErrNotFound when it does not exist.
+type MerchantAlertStore interface {
+	// MerchantMetrics summarises each merchant's payments in every period of
+	// q, leaving out periods without payments.
+	MerchantMetrics(ctx context.Context, q models.MetricsQuery) ([]models.MerchantMetrics, error)
+	// RaiseAlert stores an open alert with ID and timestamps set. While the
+	// merchant has an unresolved alert of the same type it stores nothing and
+	// reports false.
+	RaiseAlert(ctx context.Context, a models.MerchantAlert) (models.MerchantAlert, bool, error)
+	// ListAlerts returns matching alerts, newest first. A limit of zero or
+	// less returns up to 100.
+	ListAlerts(ctx context.Context, f models.AlertFilter) ([]models.MerchantAlert, error)
+	GetAlert(ctx context.Context, id int64) (models.MerchantAlert, error)
+	// AcknowledgeAlert acknowledges an open alert, returning ErrConflict when
+	// it is not open.
+	AcknowledgeAlert(ctx context.Context, id int64, by string, audit models.AuditEntry) (models.MerchantAlert, error)
+	// ResolveAlert resolves an alert, returning ErrConflict when it already
+	// is resolved.
+	ResolveAlert(ctx context.Context, id int64, by, resolution string, audit models.AuditEntry) (models.MerchantAlert, error)
+}
+
+// SuspensionPolicyStore persists suspension policies and what they matched.
+// Methods taking an audit entry write it in the same transaction as the
+// change; its TargetID defaults to the ID of the policy or match changed.
+// Methods on a single policy or match return ErrNotFound when it does not
+// exist.
+type SuspensionPolicyStore interface {
+	// CreatePolicy stores a policy, returning ErrConflict when its name is taken.
+	CreatePolicy(ctx context.Context, p models.SuspensionPolicy, audit models.AuditEntry) (models.SuspensionPolicy, error)
+	// UpdatePolicy replaces the definition of policy p.ID, keeping its
+	// creator. It returns ErrConflict when the new name is taken.
+	UpdatePolicy(ctx context.Context, p models.SuspensionPolicy, audit models.AuditEntry) (models.SuspensionPolicy, error)
+	GetPolicy(ctx context.Context, id int64) (models.SuspensionPolicy, error)
+	// ListPolicies returns policies by name, only the enabled ones if
+	// enabledOnly is set.
+	ListPolicies(ctx context.Context, enabledOnly bool) ([]models.SuspensionPolicy, error)
+	// RecordKYCRejection notes that a merchant's KYC was rejected at a time,
+	// for MerchantActivity to count.
+	RecordKYCRejection(ctx context.Context, merchantID int, at time.Time) error
+	// RecordPolicyMatch stores a match with ID and CreatedAt set. It stores
+	// nothing and reports false while the policy has a pending match for the
+	// merchant, or one created or reviewed at or after quietSince. Dry-run
+	// matches only hold back other dry-run matches, so taking a policy out of
+	// dry-run acts on merchants straight away. A suspended match suspends the
+	// merchant in the same transaction, recording audit with the match's ID
+	// added to its details, and fails with ErrNotFound when the merchant
+	// does not exist.
+	RecordPolicyMatch(ctx context.Context, m models.PolicyMatch, quietSince time.Time, audit models.AuditEntry) (models.PolicyMatch, bool, error)
+	// ListPolicyMatches returns matching policy matches, newest first.
+	ListPolicyMatches(ctx context.Context, f models.PolicyMatchFilter) ([]models.PolicyMatch, error)
+	GetPolicyMatch(ctx context.Context, id int64) (models.PolicyMatch, error)
+	// ReviewPolicyMatch approves or dismisses a pending match, setting its
+	// status, reviewer and note. It returns ErrConflict when the match is not
+	// pending.
+	ReviewPolicyMatch(ctx context.Context, id int64, status, by, note string, audit models.AuditEntry) (models.PolicyMatch, error)
+}
+
+// PayoutReviewStore reads payouts and persists the manual payout review
+// queue and the actions taken on payouts. Methods taking an audit entry write
+// it in the same transaction as the change; its TargetID defaults to the
+// payout ID.
+type PayoutReviewStore interface {
+	// ListPayouts returns matching payouts with their merchant and hold,
+	// newest first. A limit of zero or less returns up to 100.
+	ListPayouts(ctx context.Context, f models.PayoutFilter) ([]models.Payout, error)
+	// GetPayout returns a payout like ListPayouts, or ErrNotFound.
+	GetPayout(ctx context.Context, id int) (models.Payout, error)
+	// HoldPayout places a payout in the review queue and records the hold
+	// as an action, returning ErrConflict when it is already held.
+	HoldPayout(ctx context.Context, h models.PayoutHold, audit models.AuditEntry) (models.PayoutHold, error)
+	// RecordPayoutAction stores an action other than a hold with ID and
+	// CreatedAt set. A release or cancellation takes the payout out of the
+	// review queue; a release returns ErrConflict when it is not held.
+	RecordPayoutAction(ctx context.Context, a models.PayoutAction, audit models.AuditEntry) (models.PayoutAction, error)
+	// ListPayoutActions returns the actions taken on a payout, newest first.
+	ListPayoutActions(ctx context.Context, payoutID int) ([]models.PayoutAction, error)
+}
+
+// SettlementHoldStore persists merchants' settlement holds and their history.
+// Methods taking an audit entry write it in the same transaction as the
+// change; its TargetID defaults to the merchant ID.
+type SettlementHoldStore interface {
+	// SetSettlementHold stores h, with ID and CreatedAt set, as the merchant's
+	// hold in effect, releasing the one it replaces on behalf of h.CreatedBy.
+	// It returns ErrConflict when a concurrent change sets another hold first.
+	SetSettlementHold(ctx context.Context, h models.SettlementHold, audit models.AuditEntry) (models.SettlementHold, error)
+	// ReleaseSettlementHold ends the merchant's hold in effect, returning
+	// ErrNotFound when there is none.
+	ReleaseSettlementHold(ctx context.Context, merchantID int, by, reason string, audit models.AuditEntry) (models.SettlementHold, error)
+	// ListSettlementHolds returns matching holds, newest first. A limit of
+	// zero or less returns up to 100.
+	ListSettlementHolds(ctx context.Context, f models.SettlementHoldFilter) ([]models.SettlementHold, error)
+}
+
+var (
+	_ AdminStore = (*AdminRepository)(nil)
+	_ AdminStore = (*MemoryStore)(nil)
+)
@@ -0,0 +1,389 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// storeFixture is an AdminStore under test together with a way to seed it.
+type storeFixture interface {
+	AdminStore
+	addMerchant(t *testing.T, m models.Merchant)
+	addTransaction(t *testing.T, tx models.Transaction)
+	addPayout(t *testing.T, p models.Payout)
+	auditLog(t *testing.T) []models.AuditEntry
+}
+
+// base is a whole second so timestamps survive a Postgres round trip.
+var base = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func ptr(s string) *string { return &s }
+
+// testAdminStoreContract runs the behaviour every AdminStore must share.
+// newStore returns an empty store for each subtest.
+func testAdminStoreContract(t *testing.T, newStore func(t *testing.T) storeFixture) {
+	ctx := context.Background()
+
+	t.Run("ListMerchants empty", func(t *testing.T) {
+		s := newStore(t)
+		merchants, err := s.ListMerchants(ctx, models.MerchantFilter{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(merchants) != 0 {
+			t.Fatalf("got %d merchants, want 0", len(merchants))
+		}
+	})
+
+	t.Run("ListMerchants newest first with limit", func(t *testing.T) {
+		s := newStore(t)
+		for i := 1; i <= 5; i++ {
+			s.addMerchant(t, merchant(i, base.Add(time.Duration(i)*time.Hour)))
+		}
+		merchants, err := s.ListMerchants(ctx, models.MerchantFilter{Limit: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := merchantIDs(merchants); !slices.Equal(got, []int{5, 4, 3}) {
+			t.Fatalf("got IDs %v, want [5 4 3]", got)
+		}
+
+		all, err := s.ListMerchants(ctx, models.MerchantFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 5 {
+			t.Fatalf("limit 0 returned %d merchants, want all 5", len(all))
+		}
+	})
+
+	t.Run("ListMerchants balances", func(t *testing.T) {
+		s := newStore(t)
+		withBalance := merchant(1, base)
+		withBalance.TotalVolume, withBalance.Currency = 250000, "USD"
+		s.addMerchant(t, withBalance)
+		s.addMerchant(t, merchant(2, base.Add(time.Hour)))
+
+		merchants, err := s.ListMerchants(ctx, models.MerchantFilter{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(merchants) != 2 {
+			t.Fatalf("got %d merchants, want 2", len(merchants))
+		}
+		if m := merchants[0]; m.TotalVolume != 0 || m.Currency != "NGN" {
+			t.Errorf("merchant without balance: got %d %s, want 0 NGN", m.TotalVolume, m.Currency)
+		}
+		m := merchants[1]
+		if m.TotalVolume != 250000 || m.Currency != "USD" {
+			t.Errorf("merchant with balance: got %d %s, want 250000 USD", m.TotalVolume, m.Currency)
+		}
+		if m.Name != withBalance.Name || m.Email != withBalance.Email || m.BusinessName != withBalance.BusinessName ||
+			m.Status != withBalance.Status || m.KYCStatus != withBalance.KYCStatus || !m.CreatedAt.Equal(base) {
+			t.Errorf("got %+v, want fields of %+v", m, withBalance)
+		}
+	})
+
+	t.Run("UpdateMerchantStatus", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(7, base))
+		suspended := models.AuditEntry{Actor: "admin-1", Action: "merchant.suspended", TargetType: "merchant"}
+		if err := s.UpdateMerchantStatus(ctx, 7, "suspended", suspended); err != nil {
+			t.Fatal(err)
+		}
+		merchants, err := s.ListMerchants(ctx, models.MerchantFilter{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if merchants[0].Status != "suspended" {
+			t.Errorf("status = %q, want suspended", merchants[0].Status)
+		}
+		if !merchants[0].UpdatedAt.After(base) {
+			t.Errorf("updated_at %v not advanced past %v", merchants[0].UpdatedAt, base)
+		}
+		if log := s.auditLog(t); len(log) != 1 || log[0].Action != "merchant.suspended" || log[0].TargetID != "7" {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("UpdateMerchantStatus not found", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(7, base))
+		err := s.UpdateMerchantStatus(ctx, 8, "suspended", models.AuditEntry{Actor: "admin-1", Action: "merchant.suspended", TargetType: "merchant"})
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("got %v, want ErrNotFound", err)
+		}
+		if log := s.auditLog(t); len(log) != 0 {
+			t.Errorf("audit log %+v", log)
+		}
+	})
+
+	t.Run("GetStats", func(t *testing.T) {
+		s := newStore(t)
+		active := merchant(1, base)
+		pending := merchant(2, base)
+		pending.Status, pending.KYCStatus = "pending", "pending"
+		inactive := merchant(3, base)
+		inactive.Status, inactive.KYCStatus = "inactive", "completed"
+		for _, m := range []models.Merchant{active, pending, inactive} {
+			s.addMerchant(t, m)
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		s.addTransaction(t, transaction(1, 1, 10000, "successful", now.Add(-time.Hour)))
+		s.addTransaction(t, transaction(2, 1, 5000, "successful", now.Add(-60*24*time.Hour)))
+		s.addTransaction(t, transaction(3, 2, 7000, "failed", now.Add(-time.Hour)))
+		s.addTransaction(t, transaction(4, 3, 3000, "pending", now.Add(-time.Hour)))
+
+		stats, err := s.GetStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := models.PlatformStats{
+			TotalMerchants:    3,
+			ActiveMerchants:   1,
+			PendingKYC:        2,
+			TotalTransactions: 4,
+			TotalVolume:       15000,
+			MonthlyVolume:     10000,
+			SuccessRate:       50,
+		}
+		stats.GeneratedAt = time.Time{}
+		if stats != want {
+			t.Fatalf("got %+v, want %+v", stats, want)
+		}
+	})
+
+	t.Run("GetStats without transactions", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base))
+		stats, err := s.GetStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalTransactions != 0 || stats.SuccessRate != 0 {
+			t.Fatalf("got %+v, want no transactions and a zero success rate", stats)
+		}
+		if stats.GeneratedAt.IsZero() {
+			t.Error("GeneratedAt not set")
+		}
+	})
+
+	t.Run("ListTransactions", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base))
+		withOptional := transaction(1, 1, 1000, "successful", base)
+		withOptional.CustomerName, withOptional.PaymentMethod = ptr("Ada Obi"), ptr("card")
+		s.addTransaction(t, withOptional)
+		s.addTransaction(t, transaction(2, 1, 2000, "failed", base.Add(2*time.Hour)))
+		s.addTransaction(t, transaction(3, 1, 3000, "successful", base.Add(time.Hour)))
+
+		transactions, err := s.ListTransactions(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transactions) != 2 || transactions[0].ID != 2 || transactions[1].ID != 3 {
+			t.Fatalf("got %+v, want IDs 2 and 3", transactions)
+		}
+		if transactions[0].CustomerName != nil || transactions[0].PaymentMethod != nil {
+			t.Errorf("NULL columns returned as %v, %v", transactions[0].CustomerName, transactions[0].PaymentMethod)
+		}
+
+		transactions, err = s.ListTransactions(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := transactions[2]
+		if got.MerchantName != "Business 1" || got.Amount != 1000 || got.Reference != "TXN-1" {
+			t.Errorf("got %+v", got)
+		}
+		if got.CustomerName == nil || *got.CustomerName != "Ada Obi" || got.PaymentMethod == nil || *got.PaymentMethod != "card" {
+			t.Errorf("optional columns: got %v, %v", got.CustomerName, got.PaymentMethod)
+		}
+	})
+
+	t.Run("ListTransactions empty", func(t *testing.T) {
+		s := newStore(t)
+		transactions, err := s.ListTransactions(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transactions == nil || len(transactions) != 0 {
+			t.Fatalf("got %#v, want an empty non-nil slice", transactions)
+		}
+	})
+
+	t.Run("ListPayouts", func(t *testing.T) {
+		s := newStore(t)
+		s.addMerchant(t, merchant(1, base))
+		for i := 1; i <= 3; i++ {
+			s.addPayout(t, models.Payout{
+				ID: i, Reference: "PO-" + strconv.Itoa(i), MerchantID: 1,
+				Amount: int64(i) * 100, Currency: "NGN", Status: "completed", CreatedAt: base.Add(time.Duration(i) * time.Hour),
+			})
+		}
+		payouts, err := s.ListPayouts(ctx, models.PayoutFilter{Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(payouts) != 2 || payouts[0].ID != 3 || payouts[1].ID != 2 {
+			t.Fatalf("got %+v, want IDs 3 and 2", payouts)
+		}
+		if payouts[0].MerchantName != "Business 1" || payouts[0].Amount != 300 {
+			t.Errorf("got %+v", payouts[0])
+		}
+	})
+
+	t.Run("RecordFraudDecision", func(t *testing.T) {
+		s := newStore(t)
+		first, err := s.RecordFraudDecision(ctx, models.FraudDecision{
+			TransactionID: 42, Decision: models.FraudDecisionEscalated, Reason: "needs a second look", Reviewer: "analyst-1", RequestID: "req-1",
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.ID == 0 || first.CreatedAt.IsZero() {
+			t.Fatalf("ID and CreatedAt not set: %+v", first)
+		}
+		second, err := s.RecordFraudDecision(ctx, models.FraudDecision{
+			TransactionID: 42, Decision: models.FraudDecisionApproved, Reason: "customer confirmed", Reviewer: "lead-1",
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.RecordFraudDecision(ctx, models.FraudDecision{
+			TransactionID: 43, Decision: models.FraudDecisionDeclined, Reason: "stolen card", Reviewer: "analyst-1",
+		}, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		decisions, err := s.ListFraudDecisions(ctx, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(decisions) != 2 || decisions[0].ID != second.ID || decisions[1].ID != first.ID {
+			t.Fatalf("got %+v, want decisions %d and %d newest first", decisions, second.ID, first.ID)
+		}
+		if d := decisions[1]; d.Decision != models.FraudDecisionEscalated || d.Reason != "needs a second look" ||
+			d.Reviewer != "analyst-1" || d.RequestID != "req-1" {
+			t.Errorf("got %+v", d)
+		}
+
+		audit := s.auditLog(t)
+		if len(audit) != 3 {
+			t.Fatalf("got %d audit entries, want 3", len(audit))
+		}
+		if e := audit[1]; e.Actor != "lead-1" || e.Action != "fraud_review.approved" || e.TargetType != "transaction" ||
+			e.TargetID != "42" || e.Details["reason"] != "customer confirmed" {
+			t.Errorf("got audit entry %+v", e)
+		}
+	})
+
+	t.Run("RecordFraudDecision allows one final decision", func(t *testing.T) {
+		s := newStore(t)
+		decision := func(d string) models.FraudDecision {
+			return models.FraudDecision{TransactionID: 42, Decision: d, Reason: "checked", Reviewer: "analyst-1"}
+		}
+
+		downstream := errors.New("transaction service unavailable")
+		if _, err := s.RecordFraudDecision(ctx, decision(models.FraudDecisionApproved), func(context.Context) error { return downstream }); err != downstream {
+			t.Fatalf("failing apply: got %v, want its error", err)
+		}
+		if decisions, err := s.ListFraudDecisions(ctx, 42); err != nil || len(decisions) != 0 {
+			t.Fatalf("failing apply stored %+v, %v", decisions, err)
+		}
+
+		// a decision made while another is being applied is rejected
+		concurrent := make(chan error, 1)
+		_, err := s.RecordFraudDecision(ctx, decision(models.FraudDecisionApproved), func(context.Context) error {
+			go func() {
+				_, err := s.RecordFraudDecision(ctx, decision(models.FraudDecisionDeclined), func(context.Context) error {
+					t.Error("concurrent decision was applied")
+					return nil
+				})
+				concurrent <- err
+			}()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := <-concurrent; !errors.Is(err, ErrConflict) {
+			t.Errorf("concurrent decline: got %v, want ErrConflict", err)
+		}
+		if _, err := s.RecordFraudDecision(ctx, decision(models.FraudDecisionEscalated), nil); !errors.Is(err, ErrConflict) {
+			t.Errorf("escalating a decided transaction: got %v, want ErrConflict", err)
+		}
+		decisions, err := s.ListFraudDecisions(ctx, 42)
+		if err != nil || len(decisions) != 1 || decisions[0].Decision != models.FraudDecisionApproved {
+			t.Errorf("decisions = %+v, %v", decisions, err)
+		}
+		if log := s.auditLog(t); len(log) != 1 {
+			t.Errorf("got %d audit entries, want 1", len(log))
+		}
+	})
+
+	t.Run("ListFraudDecisions empty", func(t *testing.T) {
+		s := newStore(t)
+		decisions, err := s.ListFraudDecisions(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decisions == nil || len(decisions) != 0 {
+			t.Fatalf("got %#v, want an empty non-nil slice", decisions)
+		}
+	})
+
+	testFraudCaseContract(t, newStore)
+	testFraudRuleContract(t, newStore)
+	testBlocklistContract(t, newStore)
+	testMerchantRiskContract(t, newStore)
+	testMerchantAlertContract(t, newStore)
+	testSuspensionPolicyContract(t, newStore)
+	testPayoutReviewContract(t, newStore)
+	testSettlementHoldContract(t, newStore)
+}
+
+func merchant(id int, createdAt time.Time) models.Merchant {
+	n := strconv.Itoa(id)
+	return models.Merchant{
+		ID:           id,
+		Name:         "Merchant " + n,
+		Email:        "merchant" + n + "@example.com",
+		BusinessName: "Business " + n,
+		Status:       "active",
+		KYCStatus:    "completed",
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+}
+
+func transaction(id, merchantID int, amount int64, status string, createdAt time.Time) models.Transaction {
+	return models.Transaction{
+		ID:            id,
+		Reference:     "TXN-" + strconv.Itoa(id),
+		MerchantID:    merchantID,
+		CustomerEmail: "customer@example.com",
+		Amount:        amount,
+		Currency:      "NGN",
+		Status:        status,
+		CreatedAt:     createdAt,
+	}
+}
+
+func itoa64(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func merchantIDs(merchants []models.Merchant) []int {
+	ids := make([]int, len(merchants))
+	for i, m := range merchants {
+		ids[i] = m.ID
+	}
+	return ids
+}
@@ -0,0 +1,161 @@
+// Package risk scores merchants from their recent payments, KYC and account
+// age. Each factor has a weight and a severity from 0 to 1 that rises
+// linearly between the value where the factor starts to count and the value
+// where it is at its worst. A merchant's score is the sum of weight times
+// severity, from 0 to 100.
+//
+// Payment outcomes are read from transaction statuses: "failed", "refunded"
+// and "chargeback" as written by the transaction service.
+package risk
+
+import (
+	"math"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+const day = 24 * time.Hour
+
+// Windows merchant activity is measured over.
+const (
+	RatioWindow    = 30 * day
+	RecentWindow   = 7 * day
+	BaselineWindow = 28 * day
+)
+
+// Scores at which merchants become medium and high risk.
+const (
+	MediumScore = 30
+	HighScore   = 60
+)
+
+// Factor names.
+const (
+	FactorChargebacks = "chargeback_ratio"
+	FactorFailures    = "failed_ratio"
+	FactorRefunds     = "refund_ratio"
+	FactorVolumeSpike = "volume_spike"
+	FactorKYC         = "kyc"
+	FactorAccountAge  = "account_age"
+)
+
+// minSample is how many payments the ratio factors need for full weight; with
+// fewer, their severity is scaled down so one chargeback on a merchant's
+// first payment does not make it high risk.
+const minSample = 20
+
+// Activity returns the query for the windows above, ending at now.
+func Activity(now time.Time) models.ActivityQuery {
+	return models.ActivityQuery{Now: now, RatioWindow: RatioWindow, RecentWindow: RecentWindow, BaselineWindow: BaselineWindow}
+}
+
+// Score computes a merchant's risk at now from activity measured by
+// Activity(now).
+func Score(a models.MerchantActivity, now time.Time) models.RiskScore {
+	confidence := math.Min(float64(a.Transactions)/minSample, 1)
+	ratio := func(n int) float64 {
+		if a.Transactions == 0 {
+			return 0
+		}
+		return float64(n) / float64(a.Transactions)
+	}
+	age := now.Sub(a.CreatedAt)
+
+	chargebacks := ratio(a.Chargebacks)
+	failures := ratio(a.Failed)
+	refunds := ratio(a.Refunded)
+	factors := []models.RiskFactor{
+		factor(FactorChargebacks, chargebacks, 30, ramp(chargebacks, 0.002, 0.01)*confidence),
+		factor(FactorFailures, failures, 15, ramp(failures, 0.10, 0.50)*confidence),
+		factor(FactorRefunds, refunds, 15, ramp(refunds, 0.05, 0.20)*confidence),
+		volumeSpike(a, age),
+		kyc(a, now),
+		factor(FactorAccountAge, days(age), 10, 1-ramp(days(age), 0, 180)),
+	}
+
+	var total float64
+	for _, f := range factors {
+		total += f.Score
+	}
+	total = round(total)
+	return models.RiskScore{MerchantID: a.MerchantID, Score: total, Level: Level(total), Factors: factors, ComputedAt: now}
+}
+
+// Level returns the risk level of a score.
+func Level(score float64) string {
+	switch {
+	case score >= HighScore:
+		return models.RiskHigh
+	case score >= MediumScore:
+		return models.RiskMedium
+	}
+	return models.RiskLow
+}
+
+// ValidLevel reports whether l is a risk level.
+func ValidLevel(l string) bool {
+	return l == models.RiskLow || l == models.RiskMedium || l == models.RiskHigh
+}
+
+// volumeSpike compares each currency's volume over the recent window with
+// the average volume of a window that length over the baseline, taking the
+// currency with the largest multiple as its value. A currency with no
+// baseline volume is left out, unless the merchant had no baseline volume in
+// any currency: one old enough to have a baseline that suddenly takes
+// payments after none counts as a full spike.
+func volumeSpike(a models.MerchantActivity, age time.Duration) models.RiskFactor {
+	const weight = 20
+	var multiple float64
+	dormant, woke := true, false
+	for _, v := range a.Volumes {
+		woke = woke || v.Recent > 0
+		if v.Baseline == 0 {
+			continue
+		}
+		dormant = false
+		baseline := float64(v.Baseline) * float64(RecentWindow) / float64(BaselineWindow)
+		multiple = max(multiple, float64(v.Recent)/baseline)
+	}
+	if dormant {
+		if woke && age >= RecentWindow+BaselineWindow {
+			return factor(FactorVolumeSpike, 0, weight, 1)
+		}
+		return factor(FactorVolumeSpike, 0, weight, 0)
+	}
+	return factor(FactorVolumeSpike, multiple, weight, ramp(multiple, 2, 5))
+}
+
+// kyc is at its worst until KYC is completed, then grows as the verification
+// ages. Its value is the days since verification, which falls back to the
+// account's creation when the verification time is not recorded.
+func kyc(a models.MerchantActivity, now time.Time) models.RiskFactor {
+	const weight = 10
+	if a.KYCStatus != "completed" {
+		return factor(FactorKYC, 0, weight, 1)
+	}
+	verified := a.CreatedAt
+	if a.KYCVerifiedAt != nil {
+		verified = *a.KYCVerifiedAt
+	}
+	age := days(now.Sub(verified))
+	return factor(FactorKYC, age, weight, ramp(age, 365, 3*365))
+}
+
+func factor(name string, value, weight, severity float64) models.RiskFactor {
+	return models.RiskFactor{Name: name, Value: round(value), Weight: weight, Score: round(weight * severity)}
+}
+
+// ramp is 0 at or below from, 1 at or above to and linear in between.
+func ramp(v, from, to float64) float64 {
+	return math.Max(0, math.Min(1, (v-from)/(to-from)))
+}
+
+func days(d time.Duration) float64 {
+	return math.Max(0, d.Hours()/24)
+}
+
+// round keeps four decimal places, enough for ratios.
+func round(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
@@ -0,0 +1,103 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	verified := now.Add(-100 * day)
+	established := models.MerchantActivity{
+		MerchantID: 1, KYCStatus: "completed", KYCVerifiedAt: &verified, CreatedAt: now.Add(-400 * day),
+		Transactions: 1000, Failed: 50, Refunded: 10,
+		Volumes: []models.CurrencyVolume{{Currency: "NGN", Recent: 10000, Baseline: 40000}},
+	}
+
+	tests := []struct {
+		name    string
+		change  func(a *models.MerchantActivity)
+		score   float64
+		level   string
+		factors map[string]float64
+	}{
+		{"established merchant", func(*models.MerchantActivity) {}, 0, models.RiskLow, nil},
+		{
+			"chargebacks and a volume spike",
+			func(a *models.MerchantActivity) {
+				a.Chargebacks, a.Volumes = 10, []models.CurrencyVolume{{Currency: "NGN", Recent: 50000, Baseline: 40000}}
+			},
+			50, models.RiskMedium,
+			map[string]float64{FactorChargebacks: 30, FactorVolumeSpike: 20},
+		},
+		{
+			"few payments scale ratios down",
+			func(a *models.MerchantActivity) { a.Transactions, a.Failed, a.Refunded, a.Chargebacks = 2, 0, 0, 1 },
+			3, models.RiskLow,
+			map[string]float64{FactorChargebacks: 3},
+		},
+		{
+			"new merchant without KYC",
+			func(a *models.MerchantActivity) {
+				a.CreatedAt, a.KYCStatus, a.KYCVerifiedAt = now.Add(-30*day), "pending", nil
+				a.Volumes, a.Failed = []models.CurrencyVolume{{Currency: "NGN", Recent: 10000}}, 500
+			},
+			33.3333, models.RiskMedium,
+			map[string]float64{FactorKYC: 10, FactorAccountAge: 8.3333, FactorFailures: 15},
+		},
+		{
+			"dormant merchant wakes up",
+			func(a *models.MerchantActivity) {
+				a.Volumes = []models.CurrencyVolume{{Currency: "NGN", Recent: 10000}}
+			},
+			20, models.RiskLow,
+			map[string]float64{FactorVolumeSpike: 20},
+		},
+		{
+			"spike in one currency is not hidden by another",
+			func(a *models.MerchantActivity) {
+				a.Volumes = []models.CurrencyVolume{{Currency: "NGN", Recent: 50000, Baseline: 40000}, {Currency: "USD", Recent: 100, Baseline: 4000000}}
+			},
+			20, models.RiskLow,
+			map[string]float64{FactorVolumeSpike: 20},
+		},
+		{
+			"new currency without a baseline",
+			func(a *models.MerchantActivity) {
+				a.Volumes = append(a.Volumes[:1:1], models.CurrencyVolume{Currency: "USD", Recent: 90000})
+			},
+			0, models.RiskLow, nil,
+		},
+		{
+			"stale KYC falls back to account age",
+			func(a *models.MerchantActivity) { a.KYCVerifiedAt = nil; a.CreatedAt = now.Add(-2 * 365 * day) },
+			5, models.RiskLow,
+			map[string]float64{FactorKYC: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := established
+			tt.change(&a)
+			got := Score(a, now)
+			if got.Score != tt.score || got.Level != tt.level || got.MerchantID != 1 || !got.ComputedAt.Equal(now) {
+				t.Errorf("Score = %v %s, want %v %s", got.Score, got.Level, tt.score, tt.level)
+			}
+			for _, f := range got.Factors {
+				if f.Score != tt.factors[f.Name] {
+					t.Errorf("factor %s scored %v (value %v), want %v", f.Name, f.Score, f.Value, tt.factors[f.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for score, want := range map[float64]string{0: models.RiskLow, 29.99: models.RiskLow, 30: models.RiskMedium, 60: models.RiskHigh, 100: models.RiskHigh} {
+		if got := Level(score); got != want {
+			t.Errorf("Level(%v) = %s, want %s", score, got, want)
+		}
+	}
+}
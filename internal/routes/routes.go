@@ -1,41 +1,205 @@
 package routes
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/kodra-pay/admin-service/internal/clients" // Import clients
 	"github.com/kodra-pay/admin-service/internal/config"
 	"github.com/kodra-pay/admin-service/internal/handlers"
+	"github.com/kodra-pay/admin-service/internal/health"
+	"github.com/kodra-pay/admin-service/internal/jobs"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/middleware"
+	"github.com/kodra-pay/admin-service/internal/migrations"
 	"github.com/kodra-pay/admin-service/internal/repositories"
 	"github.com/kodra-pay/admin-service/internal/services"
+	"github.com/kodra-pay/admin-service/internal/settings"
 )
 
-func Register(app *fiber.App, serviceName string, merchantServiceURL string) {
-	// Health check
-	health := handlers.NewHealthHandler(serviceName)
-	health.Register(app)
+// Resources are the long-lived components created by Register that must be
+// released when the service stops.
+type Resources struct {
+	health       *handlers.HealthHandler
+	repo         *repositories.AdminRepository
+	txClient     *clients.HTTPTransactionClient
+	healthClient *http.Client
+	stopWatchers context.CancelFunc
+	// workers tracks the background goroutines stopWatchers cancels
+	workers *sync.WaitGroup
+}
+
+// Drain marks the service as not ready so no new traffic is routed to it.
+func (r *Resources) Drain() {
+	r.health.SetDraining()
+}
 
-	// Get database URL from environment
-	cfg := config.Load(serviceName, "7003") // Load config here
+// Close stops background workers, waiting for any job still running to
+// return, and then closes the database pool and clients.
+func (r *Resources) Close() error {
+	r.stopWatchers()
+	r.workers.Wait()
+	r.txClient.Close()
+	r.healthClient.CloseIdleConnections()
+	http.DefaultClient.CloseIdleConnections()
+	return r.repo.Close()
+}
 
-	// Initialize repository
+// Register wires the admin service and registers every route. Routes are
+// registered even while the database is unreachable; DB-backed endpoints
+// answer 503 until it recovers.
+func Register(app *fiber.App, cfg config.Config) (*Resources, error) {
+	// Initialize repository; it connects in the background
 	repo, err := repositories.NewAdminRepository(cfg.PostgresDSN)
 	if err != nil {
-		log.Printf("Warning: Failed to connect to database: %v. Using stub implementation.", err)
-		return
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	metrics.RegisterDBStats(repo.Stats)
+	app.Get("/metrics", metrics.Handler())
+
 	// Initialize clients
 	txClient := clients.NewHTTPTransactionClient(cfg.TransactionServiceURL)
 
+	migrator, err := migrations.New(repo.DB())
+	if err != nil {
+		return nil, err
+	}
+
+	// Health checks; the service is not ready until its schema is migrated
+	healthClient := &http.Client{}
+	checker := health.NewChecker(cfg.ReadinessTimeout, cfg.ReadinessCritical,
+		health.Dependency{Name: "postgres", Check: repo.Ping},
+		health.Dependency{Name: "schema", Critical: true, Check: migrator.Check},
+		health.Dependency{Name: "redis", Check: health.RedisCheck(cfg.RedisAddr)},
+		health.Dependency{Name: "merchant-service", Check: health.HTTPCheck(healthClient, cfg.MerchantServiceURL+"/health")},
+		health.Dependency{Name: "compliance-service", Check: health.HTTPCheck(healthClient, cfg.ComplianceServiceURL+"/health")},
+		health.Dependency{Name: "transaction-service", Check: health.HTTPCheck(healthClient, cfg.TransactionServiceURL+"/health")},
+	)
+	healthHandler := handlers.NewHealthHandler(cfg.ServiceName, checker)
+	healthHandler.Register(app)
+
+	// Runtime settings, reloaded from disk while running
+	settingsStore, err := settings.NewStore(cfg.SettingsFile)
+	if err != nil {
+		return nil, err
+	}
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	workers := &sync.WaitGroup{}
+	goWorker := func(fn func()) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			fn()
+		}()
+	}
+	goWorker(func() { settingsStore.Watch(watchCtx, cfg.SettingsPollInterval) })
+
+	if cfg.AutoMigrate {
+		goWorker(func() { migrateWhenAvailable(watchCtx, repo, migrator) })
+	}
+
 	// Initialize service
-	adminService := services.NewAdminService(repo, cfg.MerchantServiceURL, cfg.ComplianceServiceURL, txClient)
+	adminService := services.NewAdminService(repo, cfg.MerchantServiceURL, cfg.ComplianceServiceURL, txClient, settingsStore)
+
+	// Background jobs run every interval while their feature flag is on
+	every := func(flag string, interval time.Duration, fn func(ctx context.Context) error) {
+		if interval <= 0 {
+			return
+		}
+		goWorker(func() {
+			jobs.Every(watchCtx, flag, interval, func(ctx context.Context) error {
+				if !settingsStore.Enabled(flag) {
+					return nil
+				}
+				return fn(ctx)
+			})
+		})
+	}
+
+	// File flagged transactions into fraud cases in the background
+	every(settings.FlagFraudCaseSync, cfg.FraudCaseSyncInterval, func(ctx context.Context) error {
+		_, err := adminService.SyncFraudCases(ctx)
+		return err
+	})
+
+	// Pick up blocklist entries written by other replicas
+	every(settings.FlagBlocklistRefresh, cfg.BlocklistRefreshInterval, adminService.RefreshBlocklist)
+
+	// Keep merchant risk scores current
+	every(settings.FlagRiskScore, cfg.RiskScoreInterval, func(ctx context.Context) error {
+		_, err := adminService.RecalculateRiskScores(ctx)
+		return err
+	})
+
+	// Raise alerts on sudden changes in merchants' payments
+	every(settings.FlagAnomalyDetection, cfg.AnomalyDetectionInterval, func(ctx context.Context) error {
+		_, err := adminService.DetectAnomalies(ctx)
+		return err
+	})
+
+	// Suspend, or recommend suspending, merchants matched by suspension policies
+	every(settings.FlagSuspensionPolicies, cfg.SuspensionPolicyInterval, func(ctx context.Context) error {
+		_, err := adminService.EvaluatePolicies(ctx)
+		return err
+	})
+
+	// Hold pending payouts the review rules select for manual review
+	every(settings.FlagPayoutReview, cfg.PayoutReviewInterval, func(ctx context.Context) error {
+		_, err := adminService.ScreenPayouts(ctx)
+		return err
+	})
 
 	// Initialize handlers
-	adminHandler := handlers.NewAdminHandler(adminService)
+	adminHandler := handlers.NewAdminHandler(adminService, settingsStore)
+
+	// Replay retried POSTs instead of fanning them out to downstream services
+	// again, whichever replica they reach
+	idempotency := middleware.Idempotency(repositories.NewIdempotencyStore(repo), cfg.IdempotencyTTL)
+
+	// The unversioned /admin routes alias /admin/v1 until the sunset date
+	deprecation := middleware.Deprecation(cfg.LegacyAPIDeprecatedAt, cfg.LegacyAPISunset, func(path string) string {
+		return "/admin/v1" + strings.TrimPrefix(path, "/admin")
+	})
 
 	// Register routes
-	adminHandler.Register(app)
+	adminHandler.Register(app, deprecation, middleware.RateLimit(settingsStore), idempotency)
+
+	return &Resources{
+		health:       healthHandler,
+		repo:         repo,
+		txClient:     txClient,
+		healthClient: healthClient,
+		stopWatchers: stopWatch,
+		workers:      workers,
+	}, nil
+}
+
+// migrateWhenAvailable applies pending migrations as soon as the database is
+// reachable, retrying until they succeed or ctx is cancelled.
+func migrateWhenAvailable(ctx context.Context, repo *repositories.AdminRepository, migrator *migrations.Migrator) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if repo.Available() {
+			applied, err := migrator.Up(ctx)
+			if err == nil {
+				slog.Info("schema migrations up to date", "applied", len(applied))
+				return
+			}
+			slog.Error("auto-migration failed, retrying", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
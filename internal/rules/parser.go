@@ -0,0 +1,342 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// SyntaxError is a problem with a rule expression, at a byte offset into it.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// MaxWindow bounds velocity windows, which decide how much history an
+// evaluation reads.
+const MaxWindow = 30 * 24 * time.Hour
+
+// velocityDimensions are the dimensions velocity() and volume() accept.
+var velocityDimensions = map[string]bool{
+	models.VelocityCard:     true,
+	models.VelocityEmail:    true,
+	models.VelocityIP:       true,
+	models.VelocityMerchant: true,
+}
+
+type parser struct {
+	toks    []token
+	pos     int
+	windows map[models.VelocityWindow]bool
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the operators or keywords.
+func (p *parser) accept(texts ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return t, false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			return p.next(), true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		return p.errorf(p.peek(), "expected %q, found %s", text, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr parses a || b, also written a or b. Precedence from lowest:
+// or, and, not, comparisons and in, + -, * /, unary minus.
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("||", "or")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if l, err = p.logical(op, "||", l, r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("&&", "and")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if l, err = p.logical(op, "&&", l, r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) logical(op token, canonical string, l, r node) (node, error) {
+	if l.kind() != kindBool || r.kind() != kindBool {
+		return nil, p.errorf(op, "%s needs true/false operands, found %s and %s", op.text, l.kind(), r.kind())
+	}
+	return &binary{op: canonical, l: l, r: r, k: kindBool}, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	op, ok := p.accept("!", "not")
+	if !ok {
+		return p.parseComparison()
+	}
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if x.kind() != kindBool {
+		return nil, p.errorf(op, "%s needs a true/false operand, found %s", op.text, x.kind())
+	}
+	return &not{x: x}, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "in")
+	if !ok {
+		return l, nil
+	}
+	r, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.text {
+	case "in":
+		if r.kind() != listOf(l.kind()) {
+			return nil, p.errorf(op, "in needs a list of %s on the right, found %s", l.kind(), r.kind())
+		}
+	case "==", "!=":
+		if l.kind() != r.kind() || l.kind().isList() {
+			return nil, p.errorf(op, "cannot compare %s %s %s", l.kind(), op.text, r.kind())
+		}
+	default:
+		if l.kind() != kindNumber || r.kind() != kindNumber {
+			return nil, p.errorf(op, "%s needs number operands, found %s and %s", op.text, l.kind(), r.kind())
+		}
+	}
+	return &binary{op: op.text, l: l, r: r, k: kindBool}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	l, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		if l, err = p.arithmetic(op, l, r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseProduct() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if l, err = p.arithmetic(op, l, r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) arithmetic(op token, l, r node) (node, error) {
+	if l.kind() != kindNumber || r.kind() != kindNumber {
+		return nil, p.errorf(op, "%s needs number operands, found %s and %s", op.text, l.kind(), r.kind())
+	}
+	return &binary{op: op.text, l: l, r: r, k: kindNumber}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	op, ok := p.accept("-")
+	if !ok {
+		return p.parsePrimary()
+	}
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if x.kind() != kindNumber {
+		return nil, p.errorf(op, "- needs a number operand, found %s", x.kind())
+	}
+	return &binary{op: "-", l: literal{k: kindNumber, v: 0.0}, r: x, k: kindNumber}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return literal{k: kindNumber, v: t.num}, nil
+	case tokString:
+		return literal{k: kindString, v: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return literal{k: kindBool, v: t.text == "true"}, nil
+		case "velocity", "volume":
+			return p.parseVelocity(t)
+		}
+		f, ok := fields[t.text]
+		if !ok {
+			return nil, p.errorf(t, "unknown field %q", t.text)
+		}
+		return f, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			return p.parseList(t)
+		}
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+// parseList parses a non-empty list of literals of one kind.
+func (p *parser) parseList(open token) (node, error) {
+	var l list
+	for {
+		t := p.next()
+		var item literal
+		switch t.kind {
+		case tokNumber:
+			item = literal{k: kindNumber, v: t.num}
+		case tokString:
+			item = literal{k: kindString, v: t.text}
+		default:
+			return nil, p.errorf(t, "lists may only hold numbers or strings, found %s", t)
+		}
+		if len(l.items) > 0 && item.k != l.items[0].k {
+			return nil, p.errorf(t, "list mixes %s and %s", l.items[0].k, item.k)
+		}
+		l.items = append(l.items, item)
+		if _, ok := p.accept(","); !ok {
+			break
+		}
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	l.k = listOf(l.items[0].k)
+	return l, nil
+}
+
+// parseVelocity parses velocity("card", "1h") or volume("email", "7d").
+func (p *parser) parseVelocity(fn token) (node, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	dim := p.next()
+	if dim.kind != tokString || !velocityDimensions[dim.text] {
+		return nil, p.errorf(dim, "%s needs a dimension of \"card\", \"email\", \"ip\" or \"merchant\", found %s", fn.text, dim)
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	win := p.next()
+	if win.kind != tokString {
+		return nil, p.errorf(win, "%s needs a window such as \"1h\" or \"7d\", found %s", fn.text, win)
+	}
+	d, err := parseWindow(win.text)
+	if err != nil {
+		return nil, p.errorf(win, "%v", err)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	w := models.VelocityWindow{Dimension: dim.text, Window: d}
+	p.windows[w] = true
+	return &velocity{window: w, volume: fn.text == "volume"}, nil
+}
+
+// parseWindow parses a Go duration, or a whole number of days such as "7d".
+func parseWindow(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+	}
+	if d < time.Minute || d > MaxWindow {
+		return 0, fmt.Errorf("window %q must be between 1m and 30d", s)
+	}
+	return d, nil
+}
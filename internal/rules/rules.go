@@ -0,0 +1,316 @@
+// Package rules implements the expression language admins write fraud rules
+// in. An expression is evaluated against one transaction and must be true or
+// false, for example:
+//
+//	velocity("card", "1h") > 5
+//	merchant.category in ["gambling", "crypto"] && amount > 2000
+//	card_country != "" && ip_country != "" && card_country != ip_country
+//	merchant.age_days < 30 && amount >= 100000
+//
+// Operators are || (or), && (and), ! (not), == != < <= > >=, in, + - * /
+// and parentheses. Amounts are in major currency units. velocity(dimension,
+// window) counts the transactions sharing the transaction's card, email, ip
+// or merchant over the window before it, including itself; volume() sums
+// their amounts. Windows are Go durations or whole days, such as "30m",
+// "24h" or "7d".
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindString
+	kindBool
+	kindNumberList
+	kindStringList
+)
+
+func (k kind) String() string {
+	return [...]string{"number", "string", "true/false", "list of numbers", "list of strings"}[k]
+}
+
+func (k kind) isList() bool { return k == kindNumberList || k == kindStringList }
+
+// listOf returns the kind of a list of k; lists of lists and of true/false
+// values don't exist, so it returns an impossible kind for those.
+func listOf(k kind) kind {
+	switch k {
+	case kindNumber:
+		return kindNumberList
+	case kindString:
+		return kindStringList
+	default:
+		return -1
+	}
+}
+
+// node is a type-checked expression. eval only returns values of its kind:
+// float64, string, bool or []interface{}.
+type node interface {
+	kind() kind
+	eval(f *models.TransactionFacts) (interface{}, error)
+}
+
+type literal struct {
+	k kind
+	v interface{}
+}
+
+func (l literal) kind() kind                                         { return l.k }
+func (l literal) eval(*models.TransactionFacts) (interface{}, error) { return l.v, nil }
+
+type list struct {
+	k     kind
+	items []literal
+}
+
+func (l list) kind() kind { return l.k }
+
+func (l list) eval(*models.TransactionFacts) (interface{}, error) {
+	vs := make([]interface{}, len(l.items))
+	for i, item := range l.items {
+		vs[i] = item.v
+	}
+	return vs, nil
+}
+
+type field struct {
+	k   kind
+	get func(f *models.TransactionFacts) interface{}
+}
+
+func (fl field) kind() kind { return fl.k }
+
+func (fl field) eval(f *models.TransactionFacts) (interface{}, error) { return fl.get(f), nil }
+
+func stringField(get func(f *models.TransactionFacts) string) field {
+	return field{k: kindString, get: func(f *models.TransactionFacts) interface{} { return get(f) }}
+}
+
+func numberField(get func(f *models.TransactionFacts) float64) field {
+	return field{k: kindNumber, get: func(f *models.TransactionFacts) interface{} { return get(f) }}
+}
+
+// majorUnits converts minor currency units, as stored, to the major units
+// rules are written in.
+func majorUnits(minor int64) float64 {
+	return float64(minor) / 100
+}
+
+// fields are the transaction attributes rules can refer to. Emails are
+// compared lower-cased.
+var fields = map[string]field{
+	"amount":            numberField(func(f *models.TransactionFacts) float64 { return majorUnits(f.Amount) }),
+	"currency":          stringField(func(f *models.TransactionFacts) string { return f.Currency }),
+	"status":            stringField(func(f *models.TransactionFacts) string { return f.Status }),
+	"payment_method":    stringField(func(f *models.TransactionFacts) string { return deref(f.PaymentMethod) }),
+	"customer_email":    stringField(func(f *models.TransactionFacts) string { return strings.ToLower(f.CustomerEmail) }),
+	"card_fingerprint":  stringField(func(f *models.TransactionFacts) string { return f.CardFingerprint }),
+	"ip_address":        stringField(func(f *models.TransactionFacts) string { return f.IPAddress }),
+	"card_country":      stringField(func(f *models.TransactionFacts) string { return f.CardCountry }),
+	"ip_country":        stringField(func(f *models.TransactionFacts) string { return f.IPCountry }),
+	"merchant.id":       numberField(func(f *models.TransactionFacts) float64 { return float64(f.MerchantID) }),
+	"merchant.category": stringField(func(f *models.TransactionFacts) string { return f.MerchantCategory }),
+	"merchant.country":  stringField(func(f *models.TransactionFacts) string { return f.MerchantCountry }),
+	// merchant.age_days is the merchant's age in whole days when the transaction was made
+	"merchant.age_days": numberField(func(f *models.TransactionFacts) float64 {
+		return math.Floor(f.CreatedAt.Sub(f.MerchantCreatedAt).Hours() / 24)
+	}),
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+type velocity struct {
+	window models.VelocityWindow
+	volume bool
+}
+
+func (v *velocity) kind() kind { return kindNumber }
+
+func (v *velocity) eval(f *models.TransactionFacts) (interface{}, error) {
+	stat, ok := f.Velocity[v.window]
+	if !ok {
+		return nil, fmt.Errorf("velocity over %s of %s was not loaded", v.window.Window, v.window.Dimension)
+	}
+	if v.volume {
+		return majorUnits(stat.Volume), nil
+	}
+	return float64(stat.Count), nil
+}
+
+type not struct{ x node }
+
+func (n *not) kind() kind { return kindBool }
+
+func (n *not) eval(f *models.TransactionFacts) (interface{}, error) {
+	v, err := n.x.eval(f)
+	if err != nil {
+		return nil, err
+	}
+	return !v.(bool), nil
+}
+
+type binary struct {
+	op   string
+	l, r node
+	k    kind
+}
+
+func (b *binary) kind() kind { return b.k }
+
+// errDivisionByZero is returned when a rule divides by zero, e.g. by a zero velocity.
+var errDivisionByZero = errors.New("division by zero")
+
+func (b *binary) eval(f *models.TransactionFacts) (interface{}, error) {
+	l, err := b.l.eval(f)
+	if err != nil {
+		return nil, err
+	}
+	// && and || short-circuit
+	switch b.op {
+	case "&&":
+		if !l.(bool) {
+			return false, nil
+		}
+		return b.r.eval(f)
+	case "||":
+		if l.(bool) {
+			return true, nil
+		}
+		return b.r.eval(f)
+	}
+
+	r, err := b.r.eval(f)
+	if err != nil {
+		return nil, err
+	}
+	switch b.op {
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	case "in":
+		for _, item := range r.([]interface{}) {
+			if item == l {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	x, y := l.(float64), r.(float64)
+	switch b.op {
+	case "<":
+		return x < y, nil
+	case "<=":
+		return x <= y, nil
+	case ">":
+		return x > y, nil
+	case ">=":
+		return x >= y, nil
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, errDivisionByZero
+		}
+		return x / y, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", b.op)
+}
+
+// Program is a compiled rule expression.
+type Program struct {
+	root    node
+	windows []models.VelocityWindow
+}
+
+// Compile parses and type-checks a rule expression, which must be true or
+// false. Errors describe the problem and where it is, as a *SyntaxError.
+func Compile(src string) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, windows: map[models.VelocityWindow]bool{}}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	if root.kind() != kindBool {
+		return nil, &SyntaxError{Pos: 0, Msg: fmt.Sprintf("expression must be true or false, found %s", root.kind())}
+	}
+
+	prog := &Program{root: root}
+	for w := range p.windows {
+		prog.windows = append(prog.windows, w)
+	}
+	sortWindows(prog.windows)
+	return prog, nil
+}
+
+// Windows returns the velocity windows the program needs loaded into the
+// facts it is evaluated against.
+func (p *Program) Windows() []models.VelocityWindow {
+	return p.windows
+}
+
+// Match evaluates the program against a transaction. It fails only when a
+// velocity window is missing from the facts or on division by zero.
+func (p *Program) Match(f *models.TransactionFacts) (bool, error) {
+	v, err := p.root.eval(f)
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// Windows returns the distinct velocity windows needed by all the programs.
+func Windows(programs ...*Program) []models.VelocityWindow {
+	seen := map[models.VelocityWindow]bool{}
+	var windows []models.VelocityWindow
+	for _, p := range programs {
+		for _, w := range p.windows {
+			if !seen[w] {
+				seen[w] = true
+				windows = append(windows, w)
+			}
+		}
+	}
+	sortWindows(windows)
+	return windows
+}
+
+func sortWindows(ws []models.VelocityWindow) {
+	sort.Slice(ws, func(i, j int) bool {
+		if ws[i].Dimension != ws[j].Dimension {
+			return ws[i].Dimension < ws[j].Dimension
+		}
+		return ws[i].Window < ws[j].Window
+	})
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func testFacts() *models.TransactionFacts {
+	created := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	return &models.TransactionFacts{
+		Transaction: models.Transaction{
+			ID:              1,
+			MerchantID:      7,
+			CustomerEmail:   "Eve@Example.com",
+			Amount:          250_000,
+			Currency:        "NGN",
+			Status:          "successful",
+			CardFingerprint: "fp-1",
+			CardCountry:     "NG",
+			IPCountry:       "GB",
+			CreatedAt:       created,
+		},
+		MerchantCategory:  "gambling",
+		MerchantCountry:   "NG",
+		MerchantCreatedAt: created.Add(-10*24*time.Hour - time.Hour),
+		Velocity: map[models.VelocityWindow]models.VelocityStat{
+			{Dimension: models.VelocityCard, Window: time.Hour}:           {Count: 6, Volume: 900_000},
+			{Dimension: models.VelocityEmail, Window: 7 * 24 * time.Hour}: {Count: 2, Volume: 300_000},
+		},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`velocity("card", "1h") > 5`, true},
+		{`volume("card", "1h") >= 9000`, true},
+		{`velocity("email", "7d") > 2`, false},
+		{`merchant.category in ["gambling", "crypto"] && amount > 2000`, true},
+		{`merchant.category in ["travel"] or amount > 5000`, false},
+		{`card_country != "" && ip_country != "" && card_country != ip_country`, true},
+		{`merchant.age_days < 30 && amount >= 2500`, true},
+		{`merchant.age_days == 10`, true},
+		{`customer_email == "eve@example.com"`, true},
+		{`not (currency == 'NGN')`, false},
+		{`!(amount * 2 - 1000 / 2 > 4500) || status == "failed"`, true},
+		{`merchant.id in [1, 7]`, true},
+		{`-amount < 0`, true},
+		{`payment_method == ""`, true},
+	}
+	for _, tt := range tests {
+		prog, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%s): %v", tt.expr, err)
+			continue
+		}
+		got, err := prog.Match(testFacts())
+		if err != nil {
+			t.Errorf("Match(%s): %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{``, "empty expression"},
+		{`amount`, "must be true or false"},
+		{`amount > "5"`, "needs number operands"},
+		{`currency == 5`, "cannot compare"},
+		{`merchant.category in [1, 2]`, "list of string"},
+		{`["a", 1]`, "list mixes"},
+		{`risk_score > 5`, `unknown field "risk_score"`},
+		{`velocity("device", "1h") > 1`, "needs a dimension"},
+		{`velocity("card", "90d") > 1`, "between 1m and 30d"},
+		{`velocity("card", soon) > 1`, "needs a window"},
+		{`amount > 5 &&`, "unexpected end of expression"},
+		{`(amount > 5`, `expected ")"`},
+		{`amount > 5 amount`, `unexpected "amount"`},
+		{`currency == "NGN`, "unterminated string"},
+		{`amount > 5 # comment`, "unexpected character"},
+		{`amount > 1 && 2`, "needs true/false operands"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.expr)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Compile(%s) = %v, want a SyntaxError", tt.expr, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%s) = %q, want it to mention %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	a, err := Compile(`velocity("email", "24h") > 3 || velocity("card", "1h") > 5`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Compile(`volume("card", "60m") > 100 && velocity("ip", "1d") > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Windows(a, b)
+	want := []models.VelocityWindow{
+		{Dimension: "card", Window: time.Hour},
+		{Dimension: "email", Window: 24 * time.Hour},
+		{Dimension: "ip", Window: 24 * time.Hour},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Windows = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Windows[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := b.Match(testFacts()); err == nil {
+		t.Error("Match succeeded without the ip window loaded")
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	prog, err := Compile(`amount / (velocity("card", "1h") - 6) > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prog.Match(testFacts()); !errors.Is(err, errDivisionByZero) {
+		t.Errorf("Match = %v, want division by zero", err)
+	}
+}
@@ -0,0 +1,27 @@
+package services
+
+import "github.com/kodra-pay/admin-service/internal/models"
+
+// SystemActor is recorded in the audit log for changes made by background jobs.
+const SystemActor = "system"
+
+// AnonymousActor is recorded in the audit log for changes made through
+// endpoints that predate the X-Admin-ID header when a client omits it.
+const AnonymousActor = "anonymous"
+
+// Actor identifies the admin making a change, for the audit log.
+type Actor struct {
+	ID        string
+	RequestID string
+}
+
+// audit describes a change the actor made to a target for the audit log.
+func (a Actor) audit(targetType, action string, details map[string]interface{}) models.AuditEntry {
+	return models.AuditEntry{
+		Actor:      a.ID,
+		Action:     action,
+		TargetType: targetType,
+		Details:    details,
+		RequestID:  a.RequestID,
+	}
+}
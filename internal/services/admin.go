@@ -4,86 +4,118 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/kodra-pay/admin-service/internal/clients" // Import clients
 	"github.com/kodra-pay/admin-service/internal/dto"     // Import dto
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
 	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/settings"
+	"github.com/kodra-pay/admin-service/internal/tracing"
 )
 
 type AdminService struct {
-	repo                 *repositories.AdminRepository
+	repo                 repositories.AdminStore
 	MerchantServiceURL   string
 	ComplianceServiceURL string
 	TransactionClient    clients.TransactionClient // Add TransactionClient
+	merchantHTTP         *http.Client
+	complianceHTTP       *http.Client
+	settings             *settings.Store
+
+	statsMu      sync.Mutex
+	statsCache   *dto.StatsResponse
+	statsExpires time.Time
+
+	blocklist blocklistCache
 }
 
-func NewAdminService(repo *repositories.AdminRepository, merchantServiceURL, complianceServiceURL string, txClient clients.TransactionClient) *AdminService {
+func NewAdminService(repo repositories.AdminStore, merchantServiceURL, complianceServiceURL string, txClient clients.TransactionClient, settingsStore *settings.Store) *AdminService {
 	return &AdminService{
 		repo:                 repo,
+		settings:             settingsStore,
 		MerchantServiceURL:   merchantServiceURL,
 		ComplianceServiceURL: complianceServiceURL,
 		TransactionClient:    txClient,
+		merchantHTTP:         &http.Client{Transport: metrics.InstrumentTransport("merchant", tracing.Transport("merchant-service", nil))},
+		complianceHTTP:       &http.Client{Transport: metrics.InstrumentTransport("compliance", tracing.Transport("compliance-service", nil))},
 	}
 }
 
+// ListFraudulentTransactions lists transactions held for review. A limit of
+// zero or less uses the configured default; larger limits are capped.
 func (s *AdminService) ListFraudulentTransactions(ctx context.Context, limit int) (dto.TransactionListResponse, error) {
-	return s.TransactionClient.ListFraudulentTransactions(ctx, limit)
-}
+	cfg := s.settings.Get()
+	if limit <= 0 {
+		limit = cfg.FraudListDefaultLimit
+	}
+	limit = min(limit, cfg.FraudListMaxLimit)
 
-func (s *AdminService) ListMerchants(ctx context.Context) ([]map[string]interface{}, error) {
-	log.Println("AdminService: ListMerchants called.")
-	merchants, err := s.repo.ListMerchants(ctx, 200)
+	ctx, cancel := context.WithTimeout(ctx, cfg.DownstreamTimeout.Std())
+	defer cancel()
+	resp, err := s.TransactionClient.ListFraudulentTransactions(ctx, limit)
 	if err != nil {
-		log.Printf("ERROR: AdminService.ListMerchants - error from repository: %v", err)
-		return nil, err // Return nil slice and error
+		return dto.TransactionListResponse{}, newError(ErrDownstreamUnavailable, "downstream_unavailable", "failed to list flagged transactions from transaction service", err)
 	}
-	log.Printf("DEBUG: AdminService.ListMerchants - merchants retrieved from repo: %d", len(merchants))
-	return merchants, nil
+	return resp, nil
 }
 
-func (s *AdminService) ListPendingMerchants(ctx context.Context) ([]map[string]interface{}, error) {
-	url := fmt.Sprintf("%s/merchants/kyc?kyc_status=pending", s.MerchantServiceURL)
-	log.Printf("AdminService: Calling Merchant Service for pending KYC: %s", url)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// ListMerchants lists merchants with their risk scores, newest or riskiest
+// first. Risk filters only match merchants that have been scored.
+func (s *AdminService) ListMerchants(ctx context.Context, q dto.MerchantListQuery) ([]dto.MerchantResponse, error) {
+	filter, err := merchantFilter(q)
 	if err != nil {
-		log.Printf("AdminService: Failed to create request for pending KYC: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-
-	resp, err := http.DefaultClient.Do(req)
+	merchants, err := s.repo.ListMerchants(ctx, filter)
 	if err != nil {
-		log.Printf("AdminService: Failed to call Merchant Service for pending KYC: %v", err)
-		return nil, fmt.Errorf("failed to call merchant service: %w", err)
+		slog.ErrorContext(ctx, "failed to list merchants", "error", err)
+		return nil, repositoryError(err)
 	}
-	defer resp.Body.Close()
+	slog.DebugContext(ctx, "listed merchants", "count", len(merchants))
 
-	if resp.StatusCode != http.StatusOK {
-		// Read and log response body for non-OK status
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			log.Printf("AdminService: Merchant Service returned non-OK status for pending KYC: %d, failed to read body: %v", resp.StatusCode, readErr)
-		} else {
-			log.Printf("AdminService: Merchant Service returned non-OK status for pending KYC: %d, body: %s", resp.StatusCode, string(bodyBytes))
-		}
-		return nil, fmt.Errorf("merchant service returned non-ok status: %d", resp.StatusCode)
+	var resp []dto.MerchantResponse
+	for _, m := range merchants {
+		resp = append(resp, dto.NewMerchantResponse(m))
 	}
+	return resp, nil
+}
 
-	var merchants []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&merchants); err != nil {
-		log.Printf("AdminService: Failed to decode response from Merchant Service for pending KYC: %v", err)
-		return nil, fmt.Errorf("failed to decode response from merchant service: %w", err)
+// GetMerchant returns a merchant with its risk score and the factors behind it.
+func (s *AdminService) GetMerchant(ctx context.Context, id int) (dto.MerchantResponse, error) {
+	m, err := s.repo.GetMerchant(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return dto.MerchantResponse{}, newError(ErrNotFound, "merchant_not_found", fmt.Sprintf("merchant %d not found", id), err)
+	}
+	if err != nil {
+		return dto.MerchantResponse{}, repositoryError(err)
 	}
+	return dto.NewMerchantResponse(m), nil
+}
 
-	log.Printf("AdminService: Successfully retrieved %d pending merchants from Merchant Service", len(merchants))
+// ListPendingMerchants lists merchants awaiting KYC review. The merchant
+// service owns their representation, so each entry is forwarded unchanged.
+func (s *AdminService) ListPendingMerchants(ctx context.Context) ([]json.RawMessage, error) {
+	url := fmt.Sprintf("%s/merchants/kyc?kyc_status=pending", s.MerchantServiceURL)
+	var merchants []json.RawMessage
+	if err := s.callService(ctx, s.merchantHTTP, "merchant service", http.MethodGet, url, nil, &merchants); err != nil {
+		slog.ErrorContext(ctx, "failed to list pending KYC from merchant service", "error", err)
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "listed pending KYC merchants", "count", len(merchants))
 	return merchants, nil
 }
 
-func (s *AdminService) ApproveMerchantKYC(ctx context.Context, id int) map[string]interface{} {
+func (s *AdminService) ApproveMerchantKYC(ctx context.Context, id int) (dto.MerchantStatusResponse, error) {
 	// Call compliance service to update KYC status
 	url := fmt.Sprintf("%s/kyc/update", s.ComplianceServiceURL)
 	body := map[string]interface{}{
@@ -92,50 +124,23 @@ func (s *AdminService) ApproveMerchantKYC(ctx context.Context, id int) map[strin
 		"reviewer_id":  101, // Admin user ID from init-db.sql
 		"review_notes": "Approved by admin",
 	}
-	jsonBody, _ := json.Marshal(body)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to create request: %s", err.Error())}
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to call compliance service: %s", err.Error())}
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("compliance service returned non-ok status: %d", resp.StatusCode)}
+	if err := s.callService(ctx, s.complianceHTTP, "compliance service", http.MethodPost, url, body, nil); err != nil {
+		return dto.MerchantStatusResponse{}, err
 	}
 
 	// The compliance service will automatically sync the merchant KYC status
 	// Additionally, activate the merchant account after KYC approval
 	activateURL := fmt.Sprintf("%s/merchants/%d/status", s.MerchantServiceURL, id)
-	activateBody := map[string]string{"status": "active"}
-	jsonActivateBody, _ := json.Marshal(activateBody)
-
-	activateReq, err := http.NewRequestWithContext(ctx, "PUT", activateURL, bytes.NewBuffer(jsonActivateBody))
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to create activation request: %s", err.Error())}
-	}
-	activateReq.Header.Set("Content-Type", "application/json")
-
-	activateResp, err := http.DefaultClient.Do(activateReq)
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to activate merchant: %s", err.Error())}
+	if err := s.callService(ctx, s.merchantHTTP, "merchant service", http.MethodPut, activateURL, map[string]string{"status": "active"}, nil); err != nil {
+		return dto.MerchantStatusResponse{}, err
 	}
-	defer activateResp.Body.Close()
 
-	if activateResp.StatusCode != http.StatusOK {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("merchant service activation returned non-ok status: %d", activateResp.StatusCode)}
-	}
-
-	return map[string]interface{}{"id": id, "status": "approved"}
+	metrics.KYCDecisions.WithLabelValues("approved").Inc()
+	slog.InfoContext(ctx, "merchant KYC approved")
+	return dto.MerchantStatusResponse{ID: id, Status: "approved"}, nil
 }
 
-func (s *AdminService) RejectMerchantKYC(ctx context.Context, id int) map[string]interface{} {
+func (s *AdminService) RejectMerchantKYC(ctx context.Context, id int) (dto.MerchantStatusResponse, error) {
 	// Call compliance service to update KYC status
 	url := fmt.Sprintf("%s/kyc/update", s.ComplianceServiceURL)
 	body := map[string]interface{}{
@@ -144,150 +149,162 @@ func (s *AdminService) RejectMerchantKYC(ctx context.Context, id int) map[string
 		"reviewer_id":  101, // Admin user ID from init-db.sql
 		"review_notes": "Rejected by admin",
 	}
-	jsonBody, _ := json.Marshal(body)
+	if err := s.callService(ctx, s.complianceHTTP, "compliance service", http.MethodPost, url, body, nil); err != nil {
+		return dto.MerchantStatusResponse{}, err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to create request: %s", err.Error())}
+	// The compliance service will automatically sync the merchant KYC status;
+	// the rejection is also kept here for suspension policies to count
+	if err := s.repo.RecordKYCRejection(ctx, id, time.Now().UTC()); err != nil {
+		slog.WarnContext(ctx, "failed to record KYC rejection", "error", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	metrics.KYCDecisions.WithLabelValues("rejected").Inc()
+	slog.InfoContext(ctx, "merchant KYC rejected")
+	return dto.MerchantStatusResponse{ID: id, Status: "rejected"}, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to call compliance service: %s", err.Error())}
+func (s *AdminService) EnableMerchantKYC(ctx context.Context, id int) (dto.MerchantStatusResponse, error) {
+	// Update KYC status to pending to allow merchant to proceed with KYC
+	url := fmt.Sprintf("%s/merchants/%d/kyc-status", s.MerchantServiceURL, id)
+	if err := s.callService(ctx, s.merchantHTTP, "merchant service", http.MethodPut, url, map[string]string{"kyc_status": "pending"}, nil); err != nil {
+		return dto.MerchantStatusResponse{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("compliance service returned non-ok status: %d", resp.StatusCode)}
+	// Also update merchant status to pending if inactive
+	statusURL := fmt.Sprintf("%s/merchants/%d/status", s.MerchantServiceURL, id)
+	if err := s.callService(ctx, s.merchantHTTP, "merchant service", http.MethodPut, statusURL, map[string]string{"status": "pending"}, nil); err != nil {
+		slog.WarnContext(ctx, "failed to reset merchant status after enabling KYC", "error", err)
 	}
 
-	// The compliance service will automatically sync the merchant KYC status
-	return map[string]interface{}{"id": id, "status": "rejected"}
+	metrics.KYCDecisions.WithLabelValues("enabled").Inc()
+	slog.InfoContext(ctx, "merchant KYC enabled")
+	return dto.MerchantStatusResponse{ID: id, Status: "enabled"}, nil
 }
 
-func (s *AdminService) EnableMerchantKYC(ctx context.Context, id int) map[string]interface{} {
-	// Update KYC status to pending to allow merchant to proceed with KYC
+func (s *AdminService) ApproveMerchant(ctx context.Context, id int) (dto.MerchantStatusResponse, error) {
+	// First, update KYC status to completed
 	url := fmt.Sprintf("%s/merchants/%d/kyc-status", s.MerchantServiceURL, id)
-	body := map[string]string{"kyc_status": "pending"}
-	jsonBody, _ := json.Marshal(body)
-
-	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to create request: %s", err.Error())}
+	if err := s.callService(ctx, s.merchantHTTP, "merchant service", http.MethodPut, url, map[string]string{"kyc_status": "completed"}, nil); err != nil {
+		return dto.MerchantStatusResponse{}, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to call merchant service: %s", err.Error())}
+	// Then, update merchant status to active
+	statusURL := fmt.Sprintf("%s/merchants/%d/status", s.MerchantServiceURL, id)
+	if err := s.callService(ctx, s.merchantHTTP, "merchant service", http.MethodPut, statusURL, map[string]string{"status": "active"}, nil); err != nil {
+		return dto.MerchantStatusResponse{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("merchant service returned non-ok status: %d", resp.StatusCode)}
-	}
+	metrics.MerchantStatusChanges.WithLabelValues("active").Inc()
+	slog.InfoContext(ctx, "merchant approved")
+	return dto.MerchantStatusResponse{ID: id, Status: "active"}, nil
+}
 
-	// Also update merchant status to pending if inactive
-	statusURL := fmt.Sprintf("%s/merchants/%d/status", s.MerchantServiceURL, id)
-	statusBody := map[string]string{"status": "pending"}
-	jsonStatusBody, _ := json.Marshal(statusBody)
+// SuspendMerchant suspends a merchant, recording the actor in the audit log.
+func (s *AdminService) SuspendMerchant(ctx context.Context, id int, actor Actor) (dto.MerchantStatusResponse, error) {
+	return s.suspendMerchant(ctx, id, actor.audit("merchant", "merchant.suspended", nil))
+}
 
-	statusReq, err := http.NewRequestWithContext(ctx, "PUT", statusURL, bytes.NewBuffer(jsonStatusBody))
-	if err != nil {
-		log.Printf("Warning: Failed to create status update request: %v", err)
-	} else {
-		statusReq.Header.Set("Content-Type", "application/json")
-		statusResp, err := http.DefaultClient.Do(statusReq)
-		if err != nil {
-			log.Printf("Warning: Failed to call merchant service for status update: %v", err)
-		} else {
-			defer statusResp.Body.Close()
-			if statusResp.StatusCode != http.StatusOK {
-				log.Printf("Warning: Merchant service status update returned: %d", statusResp.StatusCode)
-			}
+// suspendMerchant suspends a merchant with the audit entry describing why.
+func (s *AdminService) suspendMerchant(ctx context.Context, id int, audit models.AuditEntry) (dto.MerchantStatusResponse, error) {
+	if err := s.repo.UpdateMerchantStatus(ctx, id, "suspended", audit); err != nil {
+		slog.ErrorContext(ctx, "failed to suspend merchant", "error", err)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return dto.MerchantStatusResponse{}, newError(ErrNotFound, "merchant_not_found", fmt.Sprintf("merchant %d not found", id), err)
 		}
+		return dto.MerchantStatusResponse{}, repositoryError(err)
 	}
-
-	return map[string]interface{}{"id": id, "status": "enabled"}
+	slog.InfoContext(ctx, "merchant suspended", "actor", audit.Actor)
+	metrics.MerchantStatusChanges.WithLabelValues("suspended").Inc()
+	return dto.MerchantStatusResponse{ID: id, Status: "suspended"}, nil
 }
 
-func (s *AdminService) ApproveMerchant(ctx context.Context, id int) map[string]interface{} {
-	// First, update KYC status to completed
-	url := fmt.Sprintf("%s/merchants/%d/kyc-status", s.MerchantServiceURL, id)
-	body := map[string]string{"kyc_status": "completed"}
-	jsonBody, _ := json.Marshal(body)
-
-	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonBody))
+// Transactions returns the most recent payments and payouts, newest first.
+func (s *AdminService) Transactions(ctx context.Context) ([]dto.ActivityResponse, error) {
+	const limit = 100
+	transactions, err := s.repo.ListTransactions(ctx, limit)
 	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to create KYC request: %s", err.Error())}
+		return nil, repositoryError(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
+	payouts, err := s.repo.ListPayouts(ctx, models.PayoutFilter{Limit: limit})
 	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to update KYC status: %s", err.Error())}
+		return nil, repositoryError(err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("merchant service KYC update returned status: %d", resp.StatusCode)}
+	feed := make([]dto.ActivityResponse, 0, len(transactions)+len(payouts))
+	for _, t := range transactions {
+		feed = append(feed, dto.NewPaymentActivity(t))
+	}
+	for _, p := range payouts {
+		feed = append(feed, dto.NewPayoutActivity(p))
+	}
+	sort.SliceStable(feed, func(i, j int) bool { return feed[i].CreatedAt.After(feed[j].CreatedAt) })
+	if len(feed) > limit {
+		feed = feed[:limit]
 	}
+	return feed, nil
+}
 
-	// Then, update merchant status to active
-	statusURL := fmt.Sprintf("%s/merchants/%d/status", s.MerchantServiceURL, id)
-	statusBody := map[string]string{"status": "active"}
-	jsonStatusBody, _ := json.Marshal(statusBody)
+// Stats returns platform statistics, reusing a recent result for the
+// configured cache TTL since the query scans every transaction.
+func (s *AdminService) Stats(ctx context.Context) (dto.StatsResponse, error) {
+	ttl := s.settings.Get().StatsCacheTTL.Std()
 
-	statusReq, err := http.NewRequestWithContext(ctx, "PUT", statusURL, bytes.NewBuffer(jsonStatusBody))
-	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to create status request: %s", err.Error())}
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	if ttl > 0 && s.statsCache != nil && time.Now().Before(s.statsExpires) {
+		return *s.statsCache, nil
 	}
-	statusReq.Header.Set("Content-Type", "application/json")
 
-	statusResp, err := http.DefaultClient.Do(statusReq)
+	stats, err := s.repo.GetStats(ctx)
 	if err != nil {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("failed to update status: %s", err.Error())}
+		return dto.StatsResponse{}, repositoryError(err)
 	}
-	defer statusResp.Body.Close()
+	resp := dto.NewStatsResponse(stats)
+	s.statsCache = &resp
+	s.statsExpires = time.Now().Add(ttl)
+	return resp, nil
+}
 
-	if statusResp.StatusCode != http.StatusOK {
-		return map[string]interface{}{"id": id, "status": "error", "message": fmt.Sprintf("merchant service status update returned: %d", statusResp.StatusCode)}
+// callService sends a JSON request to another platform service, decoding the
+// response into out when it is non-nil. Failures are returned as *Error.
+func (s *AdminService) callService(ctx context.Context, client *http.Client, service, method, url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", service, err)
+		}
+		reader = bytes.NewReader(jsonBody)
 	}
 
-	return map[string]interface{}{"id": id, "status": "active"}
-}
+	ctx, cancel := context.WithTimeout(ctx, s.settings.Get().DownstreamTimeout.Std())
+	defer cancel()
 
-func (s *AdminService) SuspendMerchant(ctx context.Context, id int) map[string]interface{} {
-	log.Printf("AdminService: Attempting to suspend merchant with ID: %d", id)
-	if err := s.repo.UpdateMerchantStatus(ctx, id, "suspended"); err != nil {
-		log.Printf("AdminService: Failed to suspend merchant %d: %v", id, err)
-		return map[string]interface{}{"id": id, "status": "error", "message": err.Error()}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", service, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
-	log.Printf("AdminService: Successfully suspended merchant with ID: %d", id)
-	return map[string]interface{}{"id": id, "status": "suspended"}
-}
 
-func (s *AdminService) Transactions(ctx context.Context) ([]map[string]interface{}, error) {
-	transactions, err := s.repo.GetTransactions(ctx, 100)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return newError(ErrDownstreamUnavailable, "downstream_unavailable", fmt.Sprintf("failed to call %s", service), err)
 	}
-	return transactions, nil
-}
+	defer resp.Body.Close()
 
-func (s *AdminService) Stats(ctx context.Context) map[string]interface{} {
-	stats, err := s.repo.GetStats(ctx)
-	if err != nil {
-		return map[string]interface{}{
-			"total_merchants":    0,
-			"active_merchants":   0,
-			"pending_kyc":        0,
-			"total_transactions": 0,
-			"total_volume":       0,
-			"monthly_volume":     0,
-			"success_rate":       0.0,
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		slog.WarnContext(ctx, "downstream request failed",
+			"service", service, "method", method, "url", url, "status", resp.StatusCode, "body", string(respBody))
+		return downstreamStatusError(service, resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return newError(ErrDownstreamUnavailable, "downstream_unavailable", fmt.Sprintf("failed to decode %s response", service), err)
 		}
 	}
-	return stats
+	return nil
 }
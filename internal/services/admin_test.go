@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+func newTestService(t *testing.T, store repositories.AdminStore) *AdminService {
+	t.Helper()
+	settingsStore, err := settings.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAdminService(store, "", "", nil, settingsStore)
+}
+
+func TestTransactionsMergesPaymentsAndPayouts(t *testing.T) {
+	store := repositories.NewMemoryStore()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.AddMerchant(models.Merchant{ID: 1, BusinessName: "Acme", CreatedAt: base})
+	store.AddTransaction(models.Transaction{ID: 1, MerchantID: 1, Amount: 1050, Status: "successful", CreatedAt: base.Add(time.Hour)})
+	store.AddPayout(models.Payout{ID: 1, MerchantID: 1, Amount: 500, Status: "completed", CreatedAt: base.Add(2 * time.Hour)})
+	store.AddTransaction(models.Transaction{ID: 2, MerchantID: 1, Amount: 200, Status: "failed", CreatedAt: base.Add(3 * time.Hour)})
+
+	feed, err := newTestService(t, store).Transactions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		id     int
+		typ    string
+		amount float64
+	}{{2, "payment", 2}, {1, "payout", 5}, {1, "payment", 10.5}}
+	if len(feed) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(feed), len(want))
+	}
+	for i, w := range want {
+		if got := feed[i]; got.ID != w.id || got.Type != w.typ || got.Amount != w.amount {
+			t.Errorf("entry %d = %d %s %v, want %d %s %v", i, got.ID, got.Type, got.Amount, w.id, w.typ, w.amount)
+		}
+	}
+	if m := feed[1].PaymentMethod; m == nil || *m != "payout" {
+		t.Errorf("payout payment_method = %v, want payout", m)
+	}
+	if feed[0].CustomerName != nil {
+		t.Errorf("payment without a customer name got %q", *feed[0].CustomerName)
+	}
+}
+
+func TestSuspendMerchantNotFound(t *testing.T) {
+	svc := newTestService(t, repositories.NewMemoryStore())
+	_, err := svc.SuspendMerchant(context.Background(), 42, Actor{ID: "admin-1"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != "merchant_not_found" {
+		t.Fatalf("got %#v, want code merchant_not_found", err)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/anomaly"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+var alertStatuses = map[string]bool{
+	models.AlertStatusOpen:         true,
+	models.AlertStatusAcknowledged: true,
+	models.AlertStatusResolved:     true,
+}
+
+var alertTypes = map[string]bool{
+	models.AlertVolumeSpike:     true,
+	models.AlertSuccessRateDrop: true,
+}
+
+// DetectAnomalies compares each merchant's payments over the last day with
+// its baseline and raises an alert for every anomaly, unless the merchant
+// already has an unresolved alert of that type.
+func (s *AdminService) DetectAnomalies(ctx context.Context) (dto.AnomalyDetectionResponse, error) {
+	now := time.Now().UTC()
+	series, err := s.repo.MerchantMetrics(ctx, anomaly.Query(now))
+	if err != nil {
+		return dto.AnomalyDetectionResponse{}, repositoryError(err)
+	}
+	merchants := map[int]bool{}
+	for _, m := range series {
+		merchants[m.MerchantID] = true
+	}
+
+	anomalies := anomaly.Detect(series)
+	resp := dto.AnomalyDetectionResponse{Merchants: len(merchants), Anomalies: len(anomalies)}
+	for _, a := range anomalies {
+		alert, raised, err := s.repo.RaiseAlert(ctx, models.MerchantAlert{
+			MerchantID:  a.MerchantID,
+			Type:        a.Type,
+			Currency:    a.Currency,
+			Observed:    a.Observed,
+			Expected:    a.Expected,
+			Deviation:   a.Deviation,
+			PeriodStart: now.Add(-anomaly.Period),
+			PeriodEnd:   now,
+		})
+		if err != nil {
+			return resp, repositoryError(err)
+		}
+		if raised {
+			resp.AlertsRaised++
+			metrics.MerchantAlerts.WithLabelValues(a.Type).Inc()
+			slog.InfoContext(ctx, "merchant alert raised", "alert_id", alert.ID, "merchant_id", a.MerchantID, "type", a.Type, "currency", a.Currency, "deviation", a.Deviation)
+		}
+	}
+	slog.InfoContext(ctx, "detected merchant anomalies", "merchants", resp.Merchants, "anomalies", resp.Anomalies, "alerts_raised", resp.AlertsRaised)
+	return resp, nil
+}
+
+// ListAlerts lists merchant alerts, newest first.
+func (s *AdminService) ListAlerts(ctx context.Context, q dto.AlertListQuery) (dto.AlertListResponse, error) {
+	if q.Status != "" && !alertStatuses[q.Status] {
+		return dto.AlertListResponse{}, newError(ErrValidation, "invalid_alert_status",
+			fmt.Sprintf("status %q must be one of open, acknowledged, resolved", q.Status), nil)
+	}
+	if q.Type != "" && !alertTypes[q.Type] {
+		return dto.AlertListResponse{}, newError(ErrValidation, "invalid_alert_type",
+			fmt.Sprintf("type %q must be one of volume_spike, success_rate_drop", q.Type), nil)
+	}
+	alerts, err := s.repo.ListAlerts(ctx, models.AlertFilter{Status: q.Status, Type: q.Type, MerchantID: q.MerchantID, Limit: q.Limit})
+	if err != nil {
+		return dto.AlertListResponse{}, repositoryError(err)
+	}
+	resp := dto.AlertListResponse{Alerts: []dto.AlertResponse{}, Total: len(alerts)}
+	for _, a := range alerts {
+		resp.Alerts = append(resp.Alerts, dto.NewAlertResponse(a))
+	}
+	return resp, nil
+}
+
+func (s *AdminService) GetAlert(ctx context.Context, id int64) (dto.AlertResponse, error) {
+	a, err := s.repo.GetAlert(ctx, id)
+	if err != nil {
+		return dto.AlertResponse{}, alertError(err, id)
+	}
+	return dto.NewAlertResponse(a), nil
+}
+
+// AcknowledgeAlert marks an open alert as being looked into.
+func (s *AdminService) AcknowledgeAlert(ctx context.Context, id int64, actor Actor) (dto.AlertResponse, error) {
+	a, err := s.repo.AcknowledgeAlert(ctx, id, actor.ID, actor.audit("merchant_alert", "merchant_alert.acknowledged", nil))
+	if err != nil {
+		return dto.AlertResponse{}, alertError(err, id)
+	}
+	slog.InfoContext(ctx, "merchant alert acknowledged", "alert_id", id)
+	return dto.NewAlertResponse(a), nil
+}
+
+// ResolveAlert closes an alert with a resolution explaining what was done.
+func (s *AdminService) ResolveAlert(ctx context.Context, id int64, req dto.ResolveAlertRequest, actor Actor) (dto.AlertResponse, error) {
+	resolution := strings.TrimSpace(req.Resolution)
+	if resolution == "" {
+		return dto.AlertResponse{}, newError(ErrValidation, "resolution_required", "a resolution is required to resolve an alert", nil)
+	}
+	if len(resolution) > maxReasonLength {
+		return dto.AlertResponse{}, newError(ErrValidation, "resolution_too_long", fmt.Sprintf("resolution must be at most %d characters", maxReasonLength), nil)
+	}
+	a, err := s.repo.ResolveAlert(ctx, id, actor.ID, resolution,
+		actor.audit("merchant_alert", "merchant_alert.resolved", map[string]interface{}{"resolution": resolution}))
+	if err != nil {
+		return dto.AlertResponse{}, alertError(err, id)
+	}
+	slog.InfoContext(ctx, "merchant alert resolved", "alert_id", id)
+	return dto.NewAlertResponse(a), nil
+}
+
+func alertError(err error, id int64) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return newError(ErrNotFound, "alert_not_found", fmt.Sprintf("alert %d not found", id), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return newError(ErrConflict, "alert_status_conflict", err.Error(), err)
+	default:
+		return repositoryError(err)
+	}
+}
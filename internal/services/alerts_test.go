@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestDetectAnomaliesRaisesAlertsOnce(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	now := time.Now().UTC()
+	store.AddMerchant(models.Merchant{ID: 1, Name: "Steady", CreatedAt: now.Add(-60 * 24 * time.Hour)})
+	id := 0
+	pay := func(at time.Time, amount int64) {
+		id++
+		store.AddTransaction(models.Transaction{ID: id, MerchantID: 1, Amount: amount, Status: "successful", CreatedAt: at})
+	}
+	for day := 1; day <= 28; day++ {
+		pay(now.Add(-time.Duration(day)*24*time.Hour-time.Hour), 10000+int64(day%3)*200)
+	}
+	for i := 0; i < 5; i++ {
+		pay(now.Add(-time.Duration(i+1)*time.Hour), 10000)
+	}
+	svc := newTestService(t, store)
+
+	result, err := svc.DetectAnomalies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (dto.AnomalyDetectionResponse{Merchants: 1, Anomalies: 1, AlertsRaised: 1}) {
+		t.Fatalf("detection = %+v", result)
+	}
+	if result, err = svc.DetectAnomalies(ctx); err != nil || result.AlertsRaised != 0 {
+		t.Fatalf("second detection = %+v, %v", result, err)
+	}
+
+	alerts, err := svc.ListAlerts(ctx, dto.AlertListQuery{Status: models.AlertStatusOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts.Total != 1 || alerts.Alerts[0].Type != models.AlertVolumeSpike || alerts.Alerts[0].Observed != 50000 {
+		t.Fatalf("open alerts = %+v", alerts)
+	}
+	alertID := alerts.Alerts[0].ID
+
+	actor := Actor{ID: "analyst-1"}
+	if _, err := svc.ResolveAlert(ctx, alertID, dto.ResolveAlertRequest{Resolution: " "}, actor); !errors.Is(err, ErrValidation) {
+		t.Errorf("resolving without a resolution: err = %v, want ErrValidation", err)
+	}
+	if a, err := svc.AcknowledgeAlert(ctx, alertID, actor); err != nil || a.Status != models.AlertStatusAcknowledged {
+		t.Fatalf("acknowledge = %+v, %v", a, err)
+	}
+	if _, err := svc.AcknowledgeAlert(ctx, alertID, actor); !errors.Is(err, ErrConflict) {
+		t.Errorf("acknowledging twice: err = %v, want ErrConflict", err)
+	}
+	if a, err := svc.ResolveAlert(ctx, alertID, dto.ResolveAlertRequest{Resolution: "flash sale"}, actor); err != nil || a.Resolution != "flash sale" {
+		t.Fatalf("resolve = %+v, %v", a, err)
+	}
+	if _, err := svc.GetAlert(ctx, alertID+1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing alert: err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.ListAlerts(ctx, dto.AlertListQuery{Type: "outage"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown type: err = %v, want ErrValidation", err)
+	}
+}
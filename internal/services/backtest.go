@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/rules"
+)
+
+// maxBacktestRange bounds how much history one backtest reads. Backtests
+// stream transactions and keep only totals, so they may look further back
+// than dry runs.
+const maxBacktestRange = 366 * 24 * time.Hour
+
+// BacktestExpression measures how an unsaved rule expression would have
+// performed over past transactions.
+func (s *AdminService) BacktestExpression(ctx context.Context, req dto.BacktestRequest) (dto.BacktestResponse, error) {
+	prog, err := compileRule(strings.TrimSpace(req.Expression))
+	if err != nil {
+		return dto.BacktestResponse{}, err
+	}
+	return s.backtest(ctx, prog, req.RuleRangeRequest)
+}
+
+// BacktestRule measures how a saved rule's current version would have
+// performed over past transactions.
+func (s *AdminService) BacktestRule(ctx context.Context, id int64, req dto.BacktestRequest) (dto.BacktestResponse, error) {
+	rule, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return dto.BacktestResponse{}, ruleError(err, id)
+	}
+	prog, err := compileRule(rule.Expression)
+	if err != nil {
+		return dto.BacktestResponse{}, err
+	}
+	resp, err := s.backtest(ctx, prog, req.RuleRangeRequest)
+	if err == nil {
+		slog.InfoContext(ctx, "fraud rule backtested", "rule_id", id, "version", rule.Version,
+			"evaluated", resp.Evaluated, "matched", resp.Matched)
+	}
+	return resp, err
+}
+
+func (s *AdminService) backtest(ctx context.Context, prog *rules.Program, r dto.RuleRangeRequest) (dto.BacktestResponse, error) {
+	q, err := factQuery(r, maxBacktestRange, prog.Windows())
+	if err != nil {
+		return dto.BacktestResponse{}, err
+	}
+
+	resp := dto.BacktestResponse{MatchedVolume: map[string]int64{}}
+	err = s.repo.EachTransactionFacts(ctx, q, func(f models.TransactionFacts) error {
+		resp.Evaluated++
+		switch f.ReviewDecision {
+		case models.FraudDecisionDeclined:
+			resp.ConfirmedFraud++
+		case models.FraudDecisionApproved:
+			resp.ConfirmedLegitimate++
+		}
+		matched, err := prog.Match(&f)
+		if err != nil {
+			resp.Errors++
+			return nil
+		}
+		if !matched {
+			return nil
+		}
+		resp.Matched++
+		resp.MatchedVolume[f.Currency] += f.Amount
+		switch f.ReviewDecision {
+		case models.FraudDecisionDeclined:
+			resp.FraudMatched++
+		case models.FraudDecisionApproved:
+			resp.LegitimateMatched++
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.BacktestResponse{}, repositoryError(err)
+	}
+
+	if resp.Evaluated > 0 {
+		resp.HitRate = float64(resp.Matched) / float64(resp.Evaluated)
+	}
+	if resp.ConfirmedFraud > 0 {
+		caught := float64(resp.FraudMatched) / float64(resp.ConfirmedFraud)
+		resp.FraudCaught = &caught
+	}
+	if reviewed := resp.FraudMatched + resp.LegitimateMatched; reviewed > 0 {
+		fp := float64(resp.LegitimateMatched) / float64(reviewed)
+		resp.EstimatedFalsePositiveRate = &fp
+	}
+	return resp, nil
+}
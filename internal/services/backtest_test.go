@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestBacktestComparesWithReviews(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	svc := newTestService(t, store)
+	now := time.Now().UTC()
+	store.AddMerchant(models.Merchant{ID: 1, Name: "Acme", CreatedAt: now.Add(-48 * time.Hour)})
+	txs := []struct {
+		amount   int64
+		currency string
+		decision string
+	}{
+		{150000, "USD", models.FraudDecisionDeclined},
+		{200000, "EUR", models.FraudDecisionApproved},
+		{300000, "USD", ""},
+		{100, "USD", models.FraudDecisionDeclined},
+		{200, "USD", models.FraudDecisionApproved},
+	}
+	for i, tx := range txs {
+		id := i + 1
+		store.AddTransaction(models.Transaction{ID: id, MerchantID: 1, Amount: tx.amount, Currency: tx.currency, CreatedAt: now.Add(-time.Hour)})
+		if tx.decision != "" {
+			if _, err := store.RecordFraudDecision(ctx, models.FraudDecision{TransactionID: id, Decision: tx.decision, Reviewer: "analyst-1"}, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	resp, err := svc.BacktestExpression(ctx, dto.BacktestRequest{
+		RuleRangeRequest: dto.RuleRangeRequest{From: now.Add(-24 * time.Hour)},
+		Expression:       "amount >= 1000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Evaluated != 5 || resp.Matched != 3 || resp.ConfirmedFraud != 2 || resp.FraudMatched != 1 ||
+		resp.ConfirmedLegitimate != 2 || resp.LegitimateMatched != 1 || resp.HitRate != 0.6 {
+		t.Errorf("backtest = %+v", resp)
+	}
+	if want := map[string]int64{"USD": 450000, "EUR": 200000}; !maps.Equal(resp.MatchedVolume, want) {
+		t.Errorf("matched volume = %v, want %v", resp.MatchedVolume, want)
+	}
+	if resp.FraudCaught == nil || *resp.FraudCaught != 0.5 {
+		t.Errorf("fraud caught = %v, want 0.5", resp.FraudCaught)
+	}
+	if resp.EstimatedFalsePositiveRate == nil || *resp.EstimatedFalsePositiveRate != 0.5 {
+		t.Errorf("estimated false positive rate = %v, want 0.5", resp.EstimatedFalsePositiveRate)
+	}
+
+	// A year of history is allowed, unlike a dry run.
+	_, err = svc.BacktestExpression(ctx, dto.BacktestRequest{
+		RuleRangeRequest: dto.RuleRangeRequest{From: now.Add(-200 * 24 * time.Hour)},
+		Expression:       "amount >= 1000",
+	})
+	if err != nil {
+		t.Errorf("200 day backtest: %v", err)
+	}
+}
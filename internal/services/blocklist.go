@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/blocklist"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+const (
+	// MaxBlocklistImportSize bounds the CSV file a blocklist import reads.
+	MaxBlocklistImportSize = 2 << 20
+	maxBlocklistImportRows = 10000
+	// maxImportErrors bounds how many invalid rows an import failure lists.
+	maxImportErrors = 5
+)
+
+// blocklistCache holds the index blocklist checks are answered from. Writes
+// through this service reload it, and RefreshBlocklist picks up entries
+// written by other replicas. Expiry is checked at lookup time, so expired
+// entries stop matching without a reload.
+type blocklistCache struct {
+	mu    sync.Mutex // serialises reloads so an older snapshot never replaces a newer one
+	index atomic.Pointer[blocklist.Index]
+}
+
+// RefreshBlocklist reloads the blocklist lookup cache from the database.
+func (s *AdminService) RefreshBlocklist(ctx context.Context) error {
+	_, err := s.loadBlocklist(ctx)
+	return err
+}
+
+func (s *AdminService) loadBlocklist(ctx context.Context) (*blocklist.Index, error) {
+	s.blocklist.mu.Lock()
+	defer s.blocklist.mu.Unlock()
+	entries, err := s.repo.ActiveBlocklist(ctx)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	ix := blocklist.NewIndex(entries)
+	s.blocklist.index.Store(ix)
+	metrics.BlocklistEntries.Set(float64(ix.Len()))
+	return ix, nil
+}
+
+// blocklistChanged reloads the cache after a write. If that fails the cache
+// is dropped, so the next check loads it rather than missing the change.
+func (s *AdminService) blocklistChanged(ctx context.Context) {
+	if err := s.RefreshBlocklist(ctx); err != nil {
+		slog.WarnContext(ctx, "failed to reload blocklist cache", "error", err)
+		s.blocklist.index.Store(nil)
+	}
+}
+
+// newBlocklistEntry validates an entry to block at now.
+func newBlocklistEntry(typ, value, reason string, expiresAt *time.Time, now time.Time, actor Actor) (models.BlocklistEntry, error) {
+	typ = strings.TrimSpace(typ)
+	if !blocklist.ValidType(typ) {
+		return models.BlocklistEntry{}, newError(ErrValidation, "invalid_blocklist_type",
+			fmt.Sprintf("type must be one of %s", strings.Join(blocklist.Types, ", ")), nil)
+	}
+	value, err := blocklist.Normalize(typ, value)
+	if err != nil {
+		return models.BlocklistEntry{}, newError(ErrValidation, "invalid_blocklist_value", err.Error(), err)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return models.BlocklistEntry{}, newError(ErrValidation, "reason_required", "a reason is required", nil)
+	}
+	if len(reason) > maxReasonLength {
+		return models.BlocklistEntry{}, newError(ErrValidation, "reason_too_long", fmt.Sprintf("reason must be at most %d characters", maxReasonLength), nil)
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return models.BlocklistEntry{}, newError(ErrValidation, "expires_at_in_past", "expires_at must be in the future", nil)
+	}
+	return models.BlocklistEntry{Type: typ, Value: value, Reason: reason, CreatedBy: actor.ID, ExpiresAt: expiresAt}, nil
+}
+
+// CreateBlocklistEntry blocks a customer identifier across all merchants.
+func (s *AdminService) CreateBlocklistEntry(ctx context.Context, req dto.CreateBlocklistEntryRequest, actor Actor) (dto.BlocklistEntryResponse, error) {
+	now := time.Now()
+	e, err := newBlocklistEntry(req.Type, req.Value, req.Reason, req.ExpiresAt, now, actor)
+	if err != nil {
+		return dto.BlocklistEntryResponse{}, err
+	}
+	details := map[string]interface{}{"type": e.Type, "value": e.Value, "reason": e.Reason}
+	if e.ExpiresAt != nil {
+		details["expires_at"] = e.ExpiresAt
+	}
+	added, err := s.repo.AddBlocklistEntry(ctx, e, actor.audit("blocklist_entry", "blocklist.added", details))
+	if errors.Is(err, repositories.ErrConflict) {
+		return dto.BlocklistEntryResponse{}, newError(ErrConflict, "already_blocklisted", fmt.Sprintf("%s %s is already blocklisted", e.Type, e.Value), err)
+	}
+	if err != nil {
+		return dto.BlocklistEntryResponse{}, repositoryError(err)
+	}
+	s.blocklistChanged(ctx)
+	slog.InfoContext(ctx, "blocklist entry added", "entry_id", added.ID, "type", added.Type)
+	return dto.NewBlocklistEntryResponse(added, now), nil
+}
+
+// ExpireBlocklistEntry ends a block, now unless the request sets a time.
+func (s *AdminService) ExpireBlocklistEntry(ctx context.Context, id int64, req dto.ExpireBlocklistEntryRequest, actor Actor) (dto.BlocklistEntryResponse, error) {
+	now := time.Now()
+	at := now
+	if req.ExpiresAt != nil {
+		at = *req.ExpiresAt
+	}
+	e, err := s.repo.ExpireBlocklistEntry(ctx, id, at, actor.audit("blocklist_entry", "blocklist.expired", map[string]interface{}{"expires_at": at}))
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return dto.BlocklistEntryResponse{}, newError(ErrNotFound, "blocklist_entry_not_found", fmt.Sprintf("blocklist entry %d not found", id), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return dto.BlocklistEntryResponse{}, newError(ErrConflict, "blocklist_entry_expired", fmt.Sprintf("blocklist entry %d has already expired", id), err)
+	case err != nil:
+		return dto.BlocklistEntryResponse{}, repositoryError(err)
+	}
+	s.blocklistChanged(ctx)
+	slog.InfoContext(ctx, "blocklist entry expired", "entry_id", id, "expires_at", at)
+	return dto.NewBlocklistEntryResponse(e, now), nil
+}
+
+// ImportBlocklist blocks the identifiers in a CSV file. The first row names
+// the columns: type and value are required, reason and expires_at (RFC 3339
+// or YYYY-MM-DD) optional. Rows without a reason use defaultReason. The
+// import is all or nothing: if any row is invalid nothing is imported.
+func (s *AdminService) ImportBlocklist(ctx context.Context, r io.Reader, defaultReason string, actor Actor) (dto.BlocklistImportResponse, error) {
+	entries, resp, err := parseBlocklistCSV(r, defaultReason, time.Now(), actor)
+	if err != nil {
+		return dto.BlocklistImportResponse{}, err
+	}
+	counts := map[string]int{}
+	for _, e := range entries {
+		counts[e.Type]++
+	}
+	audit := actor.audit("blocklist", "blocklist.imported", map[string]interface{}{"rows": resp.Rows, "types": counts})
+	audit.TargetID = "import"
+	resp.Imported, err = s.repo.ImportBlocklistEntries(ctx, entries, audit)
+	if err != nil {
+		return dto.BlocklistImportResponse{}, repositoryError(err)
+	}
+	resp.AlreadyBlocked = len(entries) - resp.Imported
+	s.blocklistChanged(ctx)
+	slog.InfoContext(ctx, "blocklist imported", "rows", resp.Rows, "imported", resp.Imported)
+	return resp, nil
+}
+
+// parseBlocklistCSV validates a blocklist import, dropping rows that repeat
+// an earlier row's value.
+func parseBlocklistCSV(r io.Reader, defaultReason string, now time.Time, actor Actor) ([]models.BlocklistEntry, dto.BlocklistImportResponse, error) {
+	var resp dto.BlocklistImportResponse
+	invalid := func(msg string) error {
+		return newError(ErrValidation, "invalid_import", msg, nil)
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, resp, invalid("the file is empty")
+	}
+	if err != nil {
+		return nil, resp, invalid("unreadable CSV: " + err.Error())
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := col["type"]; !ok {
+		return nil, resp, invalid("the header row must name a type column")
+	}
+	if _, ok := col["value"]; !ok {
+		return nil, resp, invalid("the header row must name a value column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := col[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var (
+		entries  []models.BlocklistEntry
+		problems []string
+		seen     = map[[2]string]bool{}
+	)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, resp, invalid("unreadable CSV: " + err.Error())
+		}
+		resp.Rows++
+		if resp.Rows > maxBlocklistImportRows {
+			return nil, resp, newError(ErrValidation, "import_too_large", fmt.Sprintf("an import may have at most %d rows", maxBlocklistImportRows), nil)
+		}
+		line, _ := cr.FieldPos(0)
+
+		reason := field(record, "reason")
+		if strings.TrimSpace(reason) == "" {
+			reason = defaultReason
+		}
+		var expiresAt *time.Time
+		if v := strings.TrimSpace(field(record, "expires_at")); v != "" {
+			t, err := parseImportTime(v)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: invalid expires_at %q", line, v))
+				continue
+			}
+			expiresAt = &t
+		}
+		e, err := newBlocklistEntry(field(record, "type"), field(record, "value"), reason, expiresAt, now, actor)
+		if err != nil {
+			var svcErr *Error
+			errors.As(err, &svcErr)
+			problems = append(problems, fmt.Sprintf("line %d: %s", line, svcErr.Message))
+			continue
+		}
+		k := [2]string{e.Type, e.Value}
+		if seen[k] {
+			resp.Duplicates++
+			continue
+		}
+		seen[k] = true
+		entries = append(entries, e)
+	}
+
+	if len(problems) > 0 {
+		msg := fmt.Sprintf("%d invalid row(s): %s", len(problems), strings.Join(problems[:min(len(problems), maxImportErrors)], "; "))
+		if len(problems) > maxImportErrors {
+			msg += "; ..."
+		}
+		return nil, resp, invalid(msg)
+	}
+	if resp.Rows == 0 {
+		return nil, resp, invalid("the file has no rows after the header")
+	}
+	return entries, resp, nil
+}
+
+// parseImportTime parses an RFC 3339 timestamp or a date, taken as midnight UTC.
+func parseImportTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+// ListBlocklist searches the blocklist, newest entries first.
+func (s *AdminService) ListBlocklist(ctx context.Context, q dto.BlocklistQuery) (dto.BlocklistListResponse, error) {
+	if q.Type != "" && !blocklist.ValidType(q.Type) {
+		return dto.BlocklistListResponse{}, newError(ErrValidation, "invalid_blocklist_type",
+			fmt.Sprintf("type must be one of %s", strings.Join(blocklist.Types, ", ")), nil)
+	}
+	entries, err := s.repo.ListBlocklist(ctx, models.BlocklistFilter{
+		Type: q.Type, Search: strings.TrimSpace(q.Search), IncludeExpired: q.IncludeExpired, Limit: q.Limit,
+	})
+	if err != nil {
+		return dto.BlocklistListResponse{}, repositoryError(err)
+	}
+	now := time.Now()
+	resp := dto.BlocklistListResponse{Entries: make([]dto.BlocklistEntryResponse, len(entries)), Total: len(entries)}
+	for i, e := range entries {
+		resp.Entries[i] = dto.NewBlocklistEntryResponse(e, now)
+	}
+	return resp, nil
+}
+
+// CheckBlocklist reports which active entries block a payment's
+// identifiers. It is answered from the in-memory cache, which is loaded on
+// first use.
+func (s *AdminService) CheckBlocklist(ctx context.Context, q dto.BlocklistCheckQuery) (dto.BlocklistCheckResponse, error) {
+	query := blocklist.Query{Email: q.Email, CardFingerprint: q.CardFingerprint, CardBIN: q.CardBIN, IP: q.IP, DeviceID: q.DeviceID}
+	if err := query.Validate(); err != nil {
+		return dto.BlocklistCheckResponse{}, newError(ErrValidation, "invalid_blocklist_check", err.Error(), err)
+	}
+	ix := s.blocklist.index.Load()
+	if ix == nil {
+		var err error
+		if ix, err = s.loadBlocklist(ctx); err != nil {
+			return dto.BlocklistCheckResponse{}, err
+		}
+	}
+
+	resp := dto.BlocklistCheckResponse{Matches: []dto.BlocklistMatchResponse{}}
+	for _, e := range ix.Match(query, time.Now()) {
+		resp.Matches = append(resp.Matches, dto.BlocklistMatchResponse{ID: e.ID, Type: e.Type, Value: e.Value, Reason: e.Reason})
+	}
+	resp.Blocked = len(resp.Matches) > 0
+	result := "clear"
+	if resp.Blocked {
+		result = "blocked"
+	}
+	metrics.BlocklistChecks.WithLabelValues(result).Inc()
+	return resp, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestBlocklistLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, repositories.NewMemoryStore())
+	actor := Actor{ID: "analyst-1"}
+
+	check := func(q dto.BlocklistCheckQuery) dto.BlocklistCheckResponse {
+		t.Helper()
+		resp, err := svc.CheckBlocklist(ctx, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// The cache loads on first use.
+	if resp := check(dto.BlocklistCheckQuery{Email: "eve@example.com"}); resp.Blocked {
+		t.Fatalf("empty blocklist blocked %+v", resp)
+	}
+
+	entry, err := svc.CreateBlocklistEntry(ctx, dto.CreateBlocklistEntryRequest{Type: "email", Value: "Eve@Example.com", Reason: "chargebacks"}, actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Value != "eve@example.com" || !entry.Active || entry.CreatedBy != "analyst-1" {
+		t.Errorf("created %+v", entry)
+	}
+	_, err = svc.CreateBlocklistEntry(ctx, dto.CreateBlocklistEntryRequest{Type: "email", Value: "eve@example.com", Reason: "again"}, actor)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("blocking twice: err = %v, want ErrConflict", err)
+	}
+	if resp := check(dto.BlocklistCheckQuery{Email: "EVE@example.com"}); !resp.Blocked || resp.Matches[0].ID != entry.ID {
+		t.Errorf("check after create = %+v", resp)
+	}
+
+	csvFile := "type,value,reason,expires_at\n" +
+		"card_bin,424242,,\n" +
+		"ip,203.0.113.0/24,botnet,2099-01-01\n" +
+		"email,eve@example.com,,\n" +
+		"card_bin,424242,,\n"
+	imported, err := svc.ImportBlocklist(ctx, strings.NewReader(csvFile), "bulk import", actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != (dto.BlocklistImportResponse{Rows: 4, Imported: 2, Duplicates: 1, AlreadyBlocked: 1}) {
+		t.Errorf("import = %+v", imported)
+	}
+	resp := check(dto.BlocklistCheckQuery{CardBIN: "42424242", IP: "203.0.113.9"})
+	if len(resp.Matches) != 2 || resp.Matches[0].Reason != "bulk import" || resp.Matches[1].Reason != "botnet" {
+		t.Errorf("check after import = %+v", resp)
+	}
+
+	if _, err := svc.ExpireBlocklistEntry(ctx, entry.ID, dto.ExpireBlocklistEntryRequest{}, actor); err != nil {
+		t.Fatal(err)
+	}
+	if resp := check(dto.BlocklistCheckQuery{Email: "eve@example.com"}); resp.Blocked {
+		t.Errorf("check after expiry = %+v", resp)
+	}
+	if _, err := svc.ExpireBlocklistEntry(ctx, entry.ID, dto.ExpireBlocklistEntryRequest{}, actor); !errors.Is(err, ErrConflict) {
+		t.Errorf("expiring twice: err = %v, want ErrConflict", err)
+	}
+
+	list, err := svc.ListBlocklist(ctx, dto.BlocklistQuery{IncludeExpired: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 3 {
+		t.Errorf("listed %d entries, want 3", list.Total)
+	}
+}
+
+func TestImportBlocklistRejectsInvalidRows(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	svc := newTestService(t, store)
+
+	csvFile := "type,value\n" +
+		"email,eve@example.com\n" +
+		"ip,10.0.0.0/8\n" +
+		"phone,555\n"
+	_, err := svc.ImportBlocklist(ctx, strings.NewReader(csvFile), "bulk import", Actor{ID: "analyst-1"})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != "invalid_import" ||
+		!strings.Contains(svcErr.Message, "line 3") || !strings.Contains(svcErr.Message, "line 4") {
+		t.Fatalf("import with invalid rows: err = %v", err)
+	}
+	if entries, _ := store.ActiveBlocklist(ctx); len(entries) != 0 {
+		t.Errorf("a failed import stored %d entries", len(entries))
+	}
+}
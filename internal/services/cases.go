@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+const (
+	// MaxAttachmentSize bounds case attachments, leaving room for the
+	// multipart envelope under the server's request body limit.
+	MaxAttachmentSize = 3 << 20
+	maxCommentLength  = 5000
+	maxAssigneeLength = 200
+)
+
+var caseStatuses = map[string]bool{
+	models.CaseStatusOpen:          true,
+	models.CaseStatusInvestigating: true,
+	models.CaseStatusResolved:      true,
+}
+
+// SyncFraudCases files every flagged transaction not yet in a case. A
+// transaction joins the open case for its card fingerprint, customer email or
+// merchant, checked in that order; when there is none a case is opened for
+// the most specific of them.
+func (s *AdminService) SyncFraudCases(ctx context.Context) (dto.CaseSyncResponse, error) {
+	cfg := s.settings.Get()
+	callCtx, cancel := context.WithTimeout(ctx, cfg.DownstreamTimeout.Std())
+	flagged, err := s.TransactionClient.ListFraudulentTransactions(callCtx, cfg.FraudListMaxLimit)
+	cancel()
+	if err != nil {
+		return dto.CaseSyncResponse{}, newError(ErrDownstreamUnavailable, "downstream_unavailable", "failed to list flagged transactions from transaction service", err)
+	}
+
+	resp := dto.CaseSyncResponse{Flagged: len(flagged.Transactions)}
+	for _, t := range flagged.Transactions {
+		if _, err := s.repo.CaseForTransaction(ctx, t.ID); err == nil {
+			continue
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			return resp, repositoryError(err)
+		}
+
+		c, created, err := s.caseFor(ctx, t)
+		if err != nil {
+			return resp, repositoryError(err)
+		}
+		added, err := s.repo.AddCaseTransaction(ctx, models.CaseTransaction{
+			TransactionID:   t.ID,
+			CaseID:          c.ID,
+			MerchantID:      t.MerchantID,
+			Amount:          t.Amount,
+			Currency:        t.Currency,
+			CustomerEmail:   t.CustomerEmail,
+			CardFingerprint: t.CardFingerprint,
+			FlaggedAt:       t.CreatedAt,
+		})
+		if err != nil {
+			return resp, repositoryError(err)
+		}
+		if created {
+			resp.CasesOpened++
+		}
+		if added {
+			resp.TransactionsAdded++
+		}
+	}
+
+	if resp.TransactionsAdded > 0 {
+		slog.InfoContext(ctx, "filed flagged transactions into fraud cases",
+			"cases_opened", resp.CasesOpened, "transactions_added", resp.TransactionsAdded)
+	}
+	return resp, nil
+}
+
+// caseFor returns the case a flagged transaction belongs in, opening one if needed.
+func (s *AdminService) caseFor(ctx context.Context, t dto.TransactionResponse) (models.FraudCase, bool, error) {
+	groups := caseGroups(t)
+	for _, g := range groups {
+		c, err := s.repo.FindOpenCase(ctx, g[0], g[1])
+		if err == nil {
+			return c, false, nil
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return models.FraudCase{}, false, err
+		}
+	}
+	system := Actor{ID: SystemActor}
+	return s.repo.OpenCase(ctx, groups[0][0], groups[0][1], system.audit("fraud_case", "fraud_case.opened",
+		map[string]interface{}{"group_type": groups[0][0], "transaction_id": t.ID}))
+}
+
+// caseGroups returns the groups a transaction could be filed under as
+// (type, key) pairs, most specific first. Every transaction has a merchant.
+func caseGroups(t dto.TransactionResponse) [][2]string {
+	var groups [][2]string
+	if t.CardFingerprint != "" {
+		groups = append(groups, [2]string{models.CaseGroupCardFingerprint, t.CardFingerprint})
+	}
+	if email := strings.ToLower(strings.TrimSpace(t.CustomerEmail)); email != "" {
+		groups = append(groups, [2]string{models.CaseGroupCustomerEmail, email})
+	}
+	return append(groups, [2]string{models.CaseGroupMerchant, strconv.Itoa(t.MerchantID)})
+}
+
+// ListCases returns fraud cases matching q, newest first.
+func (s *AdminService) ListCases(ctx context.Context, q dto.CaseListQuery) (dto.CaseListResponse, error) {
+	if q.Status != "" && !caseStatuses[q.Status] {
+		return dto.CaseListResponse{}, invalidCaseStatus(q.Status)
+	}
+	sla := s.settings.Get().FraudCaseSLA.Std()
+	now := time.Now()
+	filter := models.CaseFilter{Status: q.Status, Assignee: q.Assignee, Limit: q.Limit}
+	if q.Breached {
+		filter.OpenedBefore = now.Add(-sla)
+	}
+
+	cases, err := s.repo.ListCases(ctx, filter)
+	if err != nil {
+		return dto.CaseListResponse{}, repositoryError(err)
+	}
+	resp := dto.CaseListResponse{Cases: []dto.CaseResponse{}, Total: len(cases)}
+	for _, c := range cases {
+		resp.Cases = append(resp.Cases, dto.NewCaseResponse(c, sla, now))
+	}
+	return resp, nil
+}
+
+// GetCase returns a fraud case with its transactions, comments and attachment metadata.
+func (s *AdminService) GetCase(ctx context.Context, id int64) (dto.CaseDetailResponse, error) {
+	c, err := s.repo.GetCase(ctx, id)
+	if err != nil {
+		return dto.CaseDetailResponse{}, caseError(err, id)
+	}
+	txs, err := s.repo.ListCaseTransactions(ctx, id)
+	if err != nil {
+		return dto.CaseDetailResponse{}, repositoryError(err)
+	}
+	comments, err := s.repo.ListCaseComments(ctx, id)
+	if err != nil {
+		return dto.CaseDetailResponse{}, repositoryError(err)
+	}
+	attachments, err := s.repo.ListCaseAttachments(ctx, id)
+	if err != nil {
+		return dto.CaseDetailResponse{}, repositoryError(err)
+	}
+
+	resp := dto.CaseDetailResponse{
+		CaseResponse: dto.NewCaseResponse(c, s.settings.Get().FraudCaseSLA.Std(), time.Now()),
+		Transactions: []dto.CaseTransactionResponse{},
+		Comments:     []dto.CaseCommentResponse{},
+		Attachments:  []dto.CaseAttachmentResponse{},
+	}
+	for _, t := range txs {
+		resp.Transactions = append(resp.Transactions, dto.CaseTransactionResponse{
+			TransactionID:   t.TransactionID,
+			MerchantID:      t.MerchantID,
+			Amount:          t.Amount,
+			Currency:        t.Currency,
+			CustomerEmail:   t.CustomerEmail,
+			CardFingerprint: t.CardFingerprint,
+			FlaggedAt:       t.FlaggedAt,
+			AddedAt:         t.AddedAt,
+		})
+	}
+	for _, cm := range comments {
+		resp.Comments = append(resp.Comments, dto.NewCaseCommentResponse(cm))
+	}
+	for _, a := range attachments {
+		resp.Attachments = append(resp.Attachments, dto.NewCaseAttachmentResponse(a))
+	}
+	return resp, nil
+}
+
+// AssignCase assigns a fraud case to an analyst. An empty assignee unassigns it.
+func (s *AdminService) AssignCase(ctx context.Context, id int64, assignee string, actor Actor) (dto.CaseResponse, error) {
+	assignee = strings.TrimSpace(assignee)
+	if len(assignee) > maxAssigneeLength {
+		return dto.CaseResponse{}, newError(ErrValidation, "assignee_too_long", fmt.Sprintf("assignee must be at most %d characters", maxAssigneeLength), nil)
+	}
+	action := "fraud_case.assigned"
+	if assignee == "" {
+		action = "fraud_case.unassigned"
+	}
+	c, err := s.repo.AssignCase(ctx, id, assignee, actor.audit("fraud_case", action, map[string]interface{}{"assignee": assignee}))
+	if err != nil {
+		return dto.CaseResponse{}, caseError(err, id)
+	}
+	return dto.NewCaseResponse(c, s.settings.Get().FraudCaseSLA.Std(), time.Now()), nil
+}
+
+// SetCaseStatus moves a fraud case to a new status. Resolving a case requires
+// a resolution; reopening one fails while its group has another open case.
+func (s *AdminService) SetCaseStatus(ctx context.Context, id int64, req dto.CaseStatusRequest, actor Actor) (dto.CaseResponse, error) {
+	if !caseStatuses[req.Status] {
+		return dto.CaseResponse{}, invalidCaseStatus(req.Status)
+	}
+	resolution := strings.TrimSpace(req.Resolution)
+	if req.Status == models.CaseStatusResolved {
+		if resolution == "" {
+			return dto.CaseResponse{}, newError(ErrValidation, "resolution_required", "a resolution is required to resolve a case", nil)
+		}
+		if len(resolution) > maxReasonLength {
+			return dto.CaseResponse{}, newError(ErrValidation, "resolution_too_long", fmt.Sprintf("resolution must be at most %d characters", maxReasonLength), nil)
+		}
+	} else {
+		resolution = ""
+	}
+
+	details := map[string]interface{}{"status": req.Status}
+	if resolution != "" {
+		details["resolution"] = resolution
+	}
+	c, err := s.repo.SetCaseStatus(ctx, id, req.Status, resolution, actor.audit("fraud_case", "fraud_case."+req.Status, details))
+	if err != nil {
+		return dto.CaseResponse{}, caseError(err, id)
+	}
+	return dto.NewCaseResponse(c, s.settings.Get().FraudCaseSLA.Std(), time.Now()), nil
+}
+
+// AddCaseComment adds an analyst's comment to a fraud case.
+func (s *AdminService) AddCaseComment(ctx context.Context, id int64, body string, actor Actor) (dto.CaseCommentResponse, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return dto.CaseCommentResponse{}, newError(ErrValidation, "comment_required", "a comment body is required", nil)
+	}
+	if len(body) > maxCommentLength {
+		return dto.CaseCommentResponse{}, newError(ErrValidation, "comment_too_long", fmt.Sprintf("comment must be at most %d characters", maxCommentLength), nil)
+	}
+	cm, err := s.repo.AddCaseComment(ctx, models.CaseComment{CaseID: id, Author: actor.ID, Body: body},
+		actor.audit("fraud_case", "fraud_case.commented", nil))
+	if err != nil {
+		return dto.CaseCommentResponse{}, caseError(err, id)
+	}
+	return dto.NewCaseCommentResponse(cm), nil
+}
+
+// AddCaseAttachment stores a file on a fraud case.
+func (s *AdminService) AddCaseAttachment(ctx context.Context, id int64, filename, contentType string, data []byte, actor Actor) (dto.CaseAttachmentResponse, error) {
+	filename = filepath.Base(strings.TrimSpace(filename))
+	if filename == "." || filename == string(filepath.Separator) {
+		return dto.CaseAttachmentResponse{}, newError(ErrValidation, "filename_required", "the attachment needs a filename", nil)
+	}
+	if len(data) == 0 {
+		return dto.CaseAttachmentResponse{}, newError(ErrValidation, "attachment_empty", "the attachment is empty", nil)
+	}
+	if len(data) > MaxAttachmentSize {
+		return dto.CaseAttachmentResponse{}, newError(ErrValidation, "attachment_too_large",
+			fmt.Sprintf("attachments must be at most %d bytes", MaxAttachmentSize), nil)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	a, err := s.repo.AddCaseAttachment(ctx, models.CaseAttachment{
+		CaseID:      id,
+		Filename:    filename,
+		ContentType: contentType,
+		UploadedBy:  actor.ID,
+		Data:        data,
+	}, actor.audit("fraud_case", "fraud_case.attachment_added", map[string]interface{}{"filename": filename, "size": len(data)}))
+	if err != nil {
+		return dto.CaseAttachmentResponse{}, caseError(err, id)
+	}
+	return dto.NewCaseAttachmentResponse(a), nil
+}
+
+// GetCaseAttachment returns an attachment on a fraud case including its data.
+func (s *AdminService) GetCaseAttachment(ctx context.Context, caseID, id int64) (models.CaseAttachment, error) {
+	a, err := s.repo.GetCaseAttachment(ctx, caseID, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CaseAttachment{}, newError(ErrNotFound, "attachment_not_found",
+			fmt.Sprintf("case %d has no attachment %d", caseID, id), err)
+	}
+	if err != nil {
+		return models.CaseAttachment{}, repositoryError(err)
+	}
+	return a, nil
+}
+
+// caseError classifies a repository failure acting on a single case.
+func caseError(err error, id int64) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return newError(ErrNotFound, "case_not_found", fmt.Sprintf("fraud case %d not found", id), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return newError(ErrConflict, "case_group_already_open", "another case for the same group is already open", err)
+	default:
+		return repositoryError(err)
+	}
+}
+
+func invalidCaseStatus(status string) error {
+	return newError(ErrValidation, "invalid_case_status",
+		fmt.Sprintf("status %q must be one of open, investigating, resolved", status), nil)
+}
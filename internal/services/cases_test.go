@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestSyncFraudCasesGroupsTransactions(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	svc := newTestService(t, store)
+	now := time.Now()
+	txClient := &fakeTransactionClient{flagged: []dto.TransactionResponse{
+		{ID: 1, MerchantID: 7, Amount: 1000, CustomerEmail: "Eve@example.com", CardFingerprint: "fp-1", CreatedAt: now},
+		{ID: 2, MerchantID: 8, Amount: 2000, CustomerEmail: "eve@example.com", CreatedAt: now},
+		{ID: 3, MerchantID: 7, Amount: 500, CreatedAt: now},
+	}}
+	svc.TransactionClient = txClient
+
+	resp, err := svc.SyncFraudCases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != (dto.CaseSyncResponse{Flagged: 3, CasesOpened: 3, TransactionsAdded: 3}) {
+		t.Errorf("first sync = %+v", resp)
+	}
+
+	// Later transactions join the open case for their most specific shared key.
+	txClient.flagged = append(txClient.flagged,
+		dto.TransactionResponse{ID: 4, MerchantID: 9, Amount: 300, CustomerEmail: "eve@example.com", CardFingerprint: "fp-1", CreatedAt: now},
+		dto.TransactionResponse{ID: 5, MerchantID: 9, Amount: 400, CustomerEmail: "EVE@example.com", CardFingerprint: "fp-2", CreatedAt: now},
+	)
+	resp, err = svc.SyncFraudCases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != (dto.CaseSyncResponse{Flagged: 5, CasesOpened: 0, TransactionsAdded: 2}) {
+		t.Errorf("second sync = %+v", resp)
+	}
+
+	cases, err := svc.ListCases(ctx, dto.CaseListQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, c := range cases.Cases {
+		counts[c.GroupType+":"+c.GroupKey] = c.TransactionCount
+	}
+	want := map[string]int{"card_fingerprint:fp-1": 2, "customer_email:eve@example.com": 2, "merchant:7": 1}
+	for k, n := range want {
+		if counts[k] != n {
+			t.Errorf("case %s has %d transactions, want %d (all: %v)", k, counts[k], n, counts)
+		}
+	}
+}
+
+func TestSetCaseStatus(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	svc := newTestService(t, store)
+	c, _, err := store.OpenCase(ctx, models.CaseGroupMerchant, "7", models.AuditEntry{Actor: SystemActor, Action: "fraud_case.opened", TargetType: "fraud_case"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor := Actor{ID: "analyst-1", RequestID: "req-1"}
+
+	_, err = svc.SetCaseStatus(ctx, c.ID, dto.CaseStatusRequest{Status: models.CaseStatusResolved}, actor)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("resolving without a resolution: err = %v, want validation error", err)
+	}
+	_, err = svc.SetCaseStatus(ctx, c.ID, dto.CaseStatusRequest{Status: "closed"}, actor)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown status: err = %v, want validation error", err)
+	}
+	_, err = svc.SetCaseStatus(ctx, c.ID+100, dto.CaseStatusRequest{Status: models.CaseStatusInvestigating}, actor)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing case: err = %v, want not found", err)
+	}
+
+	resp, err := svc.SetCaseStatus(ctx, c.ID, dto.CaseStatusRequest{Status: models.CaseStatusResolved, Resolution: "chargeback confirmed"}, actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != models.CaseStatusResolved || resp.ResolvedAt == nil || resp.SLABreached {
+		t.Errorf("resolved case = %+v", resp)
+	}
+
+	log := store.AuditLog()
+	last := log[len(log)-1]
+	if last.Action != "fraud_case.resolved" || last.Actor != "analyst-1" || last.TargetID != strconv.FormatInt(c.ID, 10) {
+		t.Errorf("audit entry = %+v", last)
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kodra-pay/admin-service/internal/clients"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+// Error kinds returned by AdminService. Use errors.Is to test for them.
+var (
+	ErrNotFound              = errors.New("not found")
+	ErrConflict              = errors.New("conflict")
+	ErrValidation            = errors.New("validation failed")
+	ErrDownstreamUnavailable = errors.New("downstream service unavailable")
+	ErrUnavailable           = errors.New("service unavailable")
+)
+
+// Error is a classified service failure carrying a machine readable code for API clients.
+type Error struct {
+	Kind    error  // one of the Err* kinds above
+	Code    string // stable identifier such as "merchant_not_found"
+	Message string // human readable summary, safe to show to admins
+	Err     error  // underlying cause, if any
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func newError(kind error, code, message string, cause error) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: cause}
+}
+
+// repositoryError classifies a failure returned by the repository.
+func repositoryError(err error) error {
+	if errors.Is(err, repositories.ErrUnavailable) {
+		return newError(ErrUnavailable, "database_unavailable", "the admin database is temporarily unavailable", err)
+	}
+	return err
+}
+
+// downstreamStatusError classifies a non-OK status returned by another service.
+func downstreamStatusError(service string, status int) *Error {
+	switch status {
+	case http.StatusNotFound:
+		return newError(ErrNotFound, "merchant_not_found", fmt.Sprintf("%s could not find the merchant", service), nil)
+	case http.StatusConflict:
+		return newError(ErrConflict, "conflict", fmt.Sprintf("%s rejected the change as conflicting", service), nil)
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return newError(ErrValidation, "validation_failed", fmt.Sprintf("%s rejected the request as invalid", service), nil)
+	default:
+		return newError(ErrDownstreamUnavailable, "downstream_unavailable",
+			fmt.Sprintf("%s returned status %d", service, status), nil)
+	}
+}
+
+// transactionServiceError classifies a failure returned by the transaction client.
+func transactionServiceError(err error) error {
+	var statusErr *clients.StatusError
+	if !errors.As(err, &statusErr) {
+		return newError(ErrDownstreamUnavailable, "downstream_unavailable", "failed to call transaction service", err)
+	}
+	switch statusErr.StatusCode {
+	case http.StatusNotFound:
+		return newError(ErrNotFound, "transaction_not_found", "transaction service could not find the transaction", err)
+	case http.StatusConflict:
+		return newError(ErrConflict, "transaction_not_pending_review", "the transaction is not held for review", err)
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return newError(ErrValidation, "validation_failed", "transaction service rejected the request as invalid", err)
+	default:
+		return newError(ErrDownstreamUnavailable, "downstream_unavailable",
+			fmt.Sprintf("transaction service returned status %d", statusErr.StatusCode), err)
+	}
+}
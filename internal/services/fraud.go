@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kodra-pay/admin-service/internal/clients"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+// maxReasonLength bounds the free-text reasons reviewers give for decisions.
+const maxReasonLength = 1000
+
+// fraudResolutions maps final decisions to how the transaction service ends
+// the hold. Escalations leave the transaction held.
+var fraudResolutions = map[string]clients.ReviewResolution{
+	models.FraudDecisionApproved: clients.ReviewRelease,
+	models.FraudDecisionDeclined: clients.ReviewReverse,
+}
+
+// DecideFraudReview applies a reviewer's decision to a transaction held for
+// fraud review: approved transactions are released, declined ones reversed,
+// and escalations recorded for a senior reviewer. The decision is written to
+// the audit log. A transaction can't be decided again once approved or
+// declined.
+func (s *AdminService) DecideFraudReview(ctx context.Context, d models.FraudDecision) (dto.FraudDecisionResponse, error) {
+	d.Reason = strings.TrimSpace(d.Reason)
+	if err := validateReason(d.Reason); err != nil {
+		return dto.FraudDecisionResponse{}, err
+	}
+
+	history, err := s.repo.ListFraudDecisions(ctx, d.TransactionID)
+	if err != nil {
+		return dto.FraudDecisionResponse{}, repositoryError(err)
+	}
+	if len(history) > 0 && history[0].Final() {
+		return dto.FraudDecisionResponse{}, fraudReviewClosed(d.TransactionID, history[0].Decision)
+	}
+
+	// The decision is reserved before the transaction service is called, so
+	// of two concurrent approvals or declines only one reaches it.
+	var (
+		resolve    func(ctx context.Context) error
+		resolved   bool
+		resolveErr error
+	)
+	if resolution, ok := fraudResolutions[d.Decision]; ok {
+		resolve = func(ctx context.Context) error {
+			callCtx, cancel := context.WithTimeout(ctx, s.settings.Get().DownstreamTimeout.Std())
+			defer cancel()
+			if resolveErr = s.TransactionClient.ResolveReview(callCtx, d.TransactionID, resolution, d.Reason); resolveErr != nil {
+				return resolveErr
+			}
+			resolved = true
+			return nil
+		}
+	}
+
+	recorded, err := s.repo.RecordFraudDecision(ctx, d, resolve)
+	switch {
+	case resolveErr != nil:
+		slog.ErrorContext(ctx, "failed to resolve fraud review", "decision", d.Decision, "error", resolveErr)
+		return dto.FraudDecisionResponse{}, transactionServiceError(resolveErr)
+	case errors.Is(err, repositories.ErrConflict):
+		return dto.FraudDecisionResponse{}, fraudReviewClosed(d.TransactionID, "")
+	case err != nil && resolved:
+		// The transaction service has already acted, so this needs reconciling by hand.
+		slog.ErrorContext(ctx, "fraud review resolved but decision not recorded",
+			"decision", d.Decision, "reviewer", d.Reviewer, "error", err)
+		return dto.FraudDecisionResponse{}, repositoryError(err)
+	case err != nil:
+		return dto.FraudDecisionResponse{}, repositoryError(err)
+	}
+
+	metrics.FraudDecisions.WithLabelValues(d.Decision).Inc()
+	slog.InfoContext(ctx, "fraud review decided", "decision", d.Decision, "decision_id", recorded.ID)
+	return dto.NewFraudDecisionResponse(recorded), nil
+}
+
+// fraudReviewClosed reports that a transaction was already approved or
+// declined, naming the decision when it is known.
+func fraudReviewClosed(transactionID int, decision string) error {
+	msg := fmt.Sprintf("transaction %d was already approved or declined", transactionID)
+	if decision != "" {
+		msg = fmt.Sprintf("transaction %d was already %s", transactionID, decision)
+	}
+	return newError(ErrConflict, "fraud_review_closed", msg, nil)
+}
+
+// ListFraudDecisions returns the review decisions on a transaction, newest first.
+func (s *AdminService) ListFraudDecisions(ctx context.Context, transactionID int) (dto.FraudDecisionListResponse, error) {
+	decisions, err := s.repo.ListFraudDecisions(ctx, transactionID)
+	if err != nil {
+		return dto.FraudDecisionListResponse{}, repositoryError(err)
+	}
+	resp := dto.FraudDecisionListResponse{Decisions: []dto.FraudDecisionResponse{}, Total: len(decisions)}
+	for _, d := range decisions {
+		resp.Decisions = append(resp.Decisions, dto.NewFraudDecisionResponse(d))
+	}
+	return resp, nil
+}
+
+func validateReason(reason string) error {
+	if reason == "" {
+		return newError(ErrValidation, "reason_required", "a reason is required", nil)
+	}
+	if len(reason) > maxReasonLength {
+		return newError(ErrValidation, "reason_too_long", fmt.Sprintf("reason must be at most %d characters", maxReasonLength), nil)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kodra-pay/admin-service/internal/clients"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+// fakeTransactionClient lists flagged transactions, records review
+// resolutions and payout actions, and fails with err.
+type fakeTransactionClient struct {
+	flagged  []dto.TransactionResponse
+	resolved map[int]clients.ReviewResolution
+	payouts  map[int][]clients.PayoutAction
+	err      error
+}
+
+func (f *fakeTransactionClient) ListFraudulentTransactions(ctx context.Context, limit int) (dto.TransactionListResponse, error) {
+	if f.err != nil {
+		return dto.TransactionListResponse{}, f.err
+	}
+	return dto.TransactionListResponse{Transactions: f.flagged, Total: len(f.flagged)}, nil
+}
+
+func (f *fakeTransactionClient) ResolveReview(ctx context.Context, id int, resolution clients.ReviewResolution, reason string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.resolved == nil {
+		f.resolved = map[int]clients.ReviewResolution{}
+	}
+	f.resolved[id] = resolution
+	return nil
+}
+
+func (f *fakeTransactionClient) UpdatePayout(ctx context.Context, id int, action clients.PayoutAction, reason string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.payouts == nil {
+		f.payouts = map[int][]clients.PayoutAction{}
+	}
+	f.payouts[id] = append(f.payouts[id], action)
+	return nil
+}
+
+func TestDecideFraudReview(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	txClient := &fakeTransactionClient{}
+	svc := newTestService(t, store)
+	svc.TransactionClient = txClient
+
+	decide := func(id int, decision, reason string) (dto.FraudDecisionResponse, error) {
+		return svc.DecideFraudReview(ctx, models.FraudDecision{TransactionID: id, Decision: decision, Reason: reason, Reviewer: "analyst-1"})
+	}
+
+	if _, err := decide(1, models.FraudDecisionEscalated, " unusual velocity "); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := txClient.resolved[1]; ok {
+		t.Error("escalation resolved the transaction")
+	}
+
+	resp, err := decide(1, models.FraudDecisionApproved, "customer confirmed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txClient.resolved[1] != clients.ReviewRelease {
+		t.Errorf("approval resolved with %q, want release", txClient.resolved[1])
+	}
+	if resp.Decision != models.FraudDecisionApproved || resp.Reviewer != "analyst-1" {
+		t.Errorf("got %+v", resp)
+	}
+
+	if _, err := decide(1, models.FraudDecisionDeclined, "changed my mind"); !errors.Is(err, ErrConflict) {
+		t.Errorf("deciding a closed review: got %v, want ErrConflict", err)
+	}
+
+	if _, err := decide(2, models.FraudDecisionDeclined, "stolen card"); err != nil {
+		t.Fatal(err)
+	}
+	if txClient.resolved[2] != clients.ReviewReverse {
+		t.Errorf("decline resolved with %q, want reverse", txClient.resolved[2])
+	}
+
+	history, err := svc.ListFraudDecisions(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.Total != 2 || history.Decisions[1].Reason != "unusual velocity" {
+		t.Errorf("got history %+v", history)
+	}
+	if audit := store.AuditLog(); len(audit) != 3 {
+		t.Errorf("got %d audit entries, want 3", len(audit))
+	}
+}
+
+func TestDecideFraudReviewFailures(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		reason    string
+		clientErr error
+		wantKind  error
+		wantCode  string
+	}{
+		{"missing reason", "  ", nil, ErrValidation, "reason_required"},
+		{"unknown transaction", "fraud", &clients.StatusError{StatusCode: 404}, ErrNotFound, "transaction_not_found"},
+		{"not held", "fraud", &clients.StatusError{StatusCode: 409}, ErrConflict, "transaction_not_pending_review"},
+		{"transport failure", "fraud", errors.New("connection refused"), ErrDownstreamUnavailable, "downstream_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repositories.NewMemoryStore()
+			svc := newTestService(t, store)
+			svc.TransactionClient = &fakeTransactionClient{err: tt.clientErr}
+
+			_, err := svc.DecideFraudReview(ctx, models.FraudDecision{
+				TransactionID: 1, Decision: models.FraudDecisionDeclined, Reason: tt.reason, Reviewer: "analyst-1",
+			})
+			var svcErr *Error
+			if !errors.Is(err, tt.wantKind) || !errors.As(err, &svcErr) || svcErr.Code != tt.wantCode {
+				t.Fatalf("got %v, want %v with code %s", err, tt.wantKind, tt.wantCode)
+			}
+			if audit := store.AuditLog(); len(audit) != 0 {
+				t.Errorf("failed decision was audited: %+v", audit)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/clients"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+// maxScreenedPayouts bounds how many pending payouts one screening checks,
+// newest first.
+const maxScreenedPayouts = 1000
+
+// payoutActionAudit names each payout action in the audit log.
+var payoutActionAudit = map[string]string{
+	models.PayoutActionHold:    "payout.held",
+	models.PayoutActionRelease: "payout.released",
+	models.PayoutActionCancel:  "payout.cancelled",
+	models.PayoutActionRetry:   "payout.retried",
+}
+
+// ListPayouts lists payouts with their review holds, newest first.
+func (s *AdminService) ListPayouts(ctx context.Context, q dto.PayoutListQuery) (dto.PayoutListResponse, error) {
+	f := models.PayoutFilter{
+		MerchantID: q.MerchantID,
+		Status:     q.Status,
+		Currency:   strings.ToUpper(q.Currency),
+		MinAmount:  int64(math.Round(q.MinAmount * 100)),
+		Held:       q.Held,
+		Limit:      q.Limit,
+	}
+	var err error
+	if f.Since, err = payoutTime("since", q.Since); err != nil {
+		return dto.PayoutListResponse{}, err
+	}
+	if f.Until, err = payoutTime("until", q.Until); err != nil {
+		return dto.PayoutListResponse{}, err
+	}
+	payouts, err := s.repo.ListPayouts(ctx, f)
+	if err != nil {
+		return dto.PayoutListResponse{}, repositoryError(err)
+	}
+	resp := dto.PayoutListResponse{Payouts: []dto.PayoutResponse{}, Total: len(payouts)}
+	for _, p := range payouts {
+		resp.Payouts = append(resp.Payouts, dto.NewPayoutResponse(p))
+	}
+	return resp, nil
+}
+
+func payoutTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, newError(ErrValidation, "invalid_"+name, fmt.Sprintf("%s must be an RFC 3339 time", name), err)
+	}
+	return t, nil
+}
+
+// GetPayout returns a payout with the actions taken on it.
+func (s *AdminService) GetPayout(ctx context.Context, id int) (dto.PayoutDetailResponse, error) {
+	p, err := s.repo.GetPayout(ctx, id)
+	if err != nil {
+		return dto.PayoutDetailResponse{}, payoutError(err, id)
+	}
+	actions, err := s.repo.ListPayoutActions(ctx, id)
+	if err != nil {
+		return dto.PayoutDetailResponse{}, repositoryError(err)
+	}
+	return dto.NewPayoutDetailResponse(p, actions), nil
+}
+
+// HoldPayout has the transaction service hold a payout and places it in the
+// manual review queue.
+func (s *AdminService) HoldPayout(ctx context.Context, id int, req dto.PayoutActionRequest, actor Actor) (dto.PayoutResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if err := validateReason(reason); err != nil {
+		return dto.PayoutResponse{}, err
+	}
+	p, err := s.repo.GetPayout(ctx, id)
+	if err != nil {
+		return dto.PayoutResponse{}, payoutError(err, id)
+	}
+	if p.Hold != nil {
+		return dto.PayoutResponse{}, newError(ErrConflict, "payout_already_held", fmt.Sprintf("payout %d is already held", id), nil)
+	}
+	if err := s.holdPayout(ctx, p, models.PayoutRuleManual, reason, actor); err != nil {
+		return dto.PayoutResponse{}, err
+	}
+	return s.payoutResponse(ctx, id)
+}
+
+// holdPayout has the transaction service hold a payout and records the hold.
+func (s *AdminService) holdPayout(ctx context.Context, p models.Payout, rule, reason string, actor Actor) error {
+	if err := s.updatePayout(ctx, p.ID, models.PayoutActionHold, reason); err != nil {
+		return err
+	}
+	_, err := s.repo.HoldPayout(ctx, models.PayoutHold{PayoutID: p.ID, Rule: rule, Reason: reason, HeldBy: actor.ID},
+		actor.audit("payout", payoutActionAudit[models.PayoutActionHold], map[string]interface{}{
+			"merchant_id": p.MerchantID, "rule": rule, "reason": reason,
+		}))
+	if err != nil {
+		// The transaction service has already held the payout, so this needs reconciling by hand.
+		slog.ErrorContext(ctx, "payout held but hold not recorded", "payout_id", p.ID, "rule", rule, "error", err)
+		return payoutError(err, p.ID)
+	}
+	metrics.PayoutActions.WithLabelValues(models.PayoutActionHold).Inc()
+	slog.InfoContext(ctx, "payout held for review", "payout_id", p.ID, "merchant_id", p.MerchantID, "rule", rule)
+	return nil
+}
+
+// ReleasePayout has the transaction service send a held payout and takes it
+// out of the manual review queue.
+func (s *AdminService) ReleasePayout(ctx context.Context, id int, req dto.PayoutActionRequest, actor Actor) (dto.PayoutResponse, error) {
+	return s.actOnPayout(ctx, id, models.PayoutActionRelease, req, actor)
+}
+
+// CancelPayout has the transaction service cancel a payout, taking it out of
+// the manual review queue if it is held.
+func (s *AdminService) CancelPayout(ctx context.Context, id int, req dto.PayoutActionRequest, actor Actor) (dto.PayoutResponse, error) {
+	return s.actOnPayout(ctx, id, models.PayoutActionCancel, req, actor)
+}
+
+// RetryPayout has the transaction service try sending a payout again. A
+// payout held for review must be released before it can be retried.
+func (s *AdminService) RetryPayout(ctx context.Context, id int, req dto.PayoutActionRequest, actor Actor) (dto.PayoutResponse, error) {
+	return s.actOnPayout(ctx, id, models.PayoutActionRetry, req, actor)
+}
+
+func (s *AdminService) actOnPayout(ctx context.Context, id int, action string, req dto.PayoutActionRequest, actor Actor) (dto.PayoutResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if err := validateReason(reason); err != nil {
+		return dto.PayoutResponse{}, err
+	}
+	p, err := s.repo.GetPayout(ctx, id)
+	if err != nil {
+		return dto.PayoutResponse{}, payoutError(err, id)
+	}
+	if action == models.PayoutActionRelease && p.Hold == nil {
+		return dto.PayoutResponse{}, newError(ErrConflict, "payout_not_held", fmt.Sprintf("payout %d is not held", id), nil)
+	}
+	if action == models.PayoutActionRetry && p.Hold != nil {
+		return dto.PayoutResponse{}, newError(ErrConflict, "payout_held", fmt.Sprintf("payout %d is held for review; release it first", id), nil)
+	}
+	if err := s.updatePayout(ctx, id, action, reason); err != nil {
+		return dto.PayoutResponse{}, err
+	}
+	details := map[string]interface{}{"merchant_id": p.MerchantID, "reason": reason}
+	if p.Hold != nil {
+		details["rule"] = p.Hold.Rule
+	}
+	if _, err := s.repo.RecordPayoutAction(ctx, models.PayoutAction{PayoutID: id, Action: action, Reason: reason, Actor: actor.ID},
+		actor.audit("payout", payoutActionAudit[action], details)); err != nil {
+		// The transaction service has already acted, so this needs reconciling by hand.
+		slog.ErrorContext(ctx, "payout updated but action not recorded", "payout_id", id, "action", action, "error", err)
+		return dto.PayoutResponse{}, payoutError(err, id)
+	}
+	metrics.PayoutActions.WithLabelValues(action).Inc()
+	slog.InfoContext(ctx, "payout action taken", "payout_id", id, "action", action)
+	return s.payoutResponse(ctx, id)
+}
+
+// updatePayout asks the transaction service to apply an action to a payout.
+func (s *AdminService) updatePayout(ctx context.Context, id int, action, reason string) error {
+	callCtx, cancel := context.WithTimeout(ctx, s.settings.Get().DownstreamTimeout.Std())
+	defer cancel()
+	if err := s.TransactionClient.UpdatePayout(callCtx, id, clients.PayoutAction(action), reason); err != nil {
+		slog.ErrorContext(ctx, "failed to update payout", "payout_id", id, "action", action, "error", err)
+		return payoutServiceError(err, action)
+	}
+	return nil
+}
+
+func (s *AdminService) payoutResponse(ctx context.Context, id int) (dto.PayoutResponse, error) {
+	p, err := s.repo.GetPayout(ctx, id)
+	if err != nil {
+		return dto.PayoutResponse{}, payoutError(err, id)
+	}
+	return dto.NewPayoutResponse(p), nil
+}
+
+// ScreenPayouts holds for manual review the pending payouts of merchants
+// under a full settlement hold, and those the review rules in the runtime
+// settings select. Payouts someone has already held, released, cancelled or
+// retried are left alone.
+func (s *AdminService) ScreenPayouts(ctx context.Context) (dto.PayoutScreeningResponse, error) {
+	cfg := s.settings.Get().PayoutReview
+	var resp dto.PayoutScreeningResponse
+	fullHolds, err := s.fullSettlementHolds(ctx)
+	if err != nil {
+		return resp, err
+	}
+	if len(fullHolds) == 0 && len(cfg.Thresholds) == 0 && cfg.NewMerchantAge <= 0 {
+		return resp, nil
+	}
+	payouts, err := s.repo.ListPayouts(ctx, models.PayoutFilter{
+		Status: models.PayoutStatusPending, Unreviewed: true, Limit: maxScreenedPayouts,
+	})
+	if err != nil {
+		return resp, repositoryError(err)
+	}
+	system := Actor{ID: SystemActor}
+	for _, p := range payouts {
+		resp.Screened++
+		rule, reason, matched := payoutReviewRule(cfg, p)
+		if h, ok := fullHolds[p.MerchantID]; ok {
+			rule, reason, matched = models.PayoutRuleSettlementHold, "merchant's settlement is on full hold: "+h.Reason, true
+		}
+		if !matched {
+			continue
+		}
+		if err := s.holdPayout(ctx, p, rule, reason, system); err != nil {
+			// A payout sent or cancelled since it was listed can't be held.
+			if errors.Is(err, ErrConflict) || errors.Is(err, ErrNotFound) {
+				slog.WarnContext(ctx, "skipped payout selected for review", "payout_id", p.ID, "rule", rule, "error", err)
+				continue
+			}
+			return resp, err
+		}
+		resp.Held++
+	}
+	slog.InfoContext(ctx, "screened pending payouts", "screened", resp.Screened, "held", resp.Held)
+	return resp, nil
+}
+
+// payoutReviewRule reports which review rule, if any, selects a payout and
+// why.
+func payoutReviewRule(cfg settings.PayoutReview, p models.Payout) (rule, reason string, matched bool) {
+	if threshold, ok := cfg.Thresholds[p.Currency]; ok && p.Amount >= threshold {
+		return models.PayoutRuleAmountThreshold,
+			fmt.Sprintf("amount %.2f %s is at least the review threshold of %.2f %s",
+				float64(p.Amount)/100, p.Currency, float64(threshold)/100, p.Currency), true
+	}
+	if age := cfg.NewMerchantAge.Std(); age > 0 && p.CreatedAt.Sub(p.MerchantCreatedAt) < age {
+		return models.PayoutRuleNewMerchant,
+			fmt.Sprintf("merchant signed up less than %s before the payout", days(age)), true
+	}
+	return "", "", false
+}
+
+// days writes whole days as such, and any other duration as Go does.
+func days(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d == day:
+		return "1 day"
+	case d > day && d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	default:
+		return d.String()
+	}
+}
+
+func payoutError(err error, id int) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return newError(ErrNotFound, "payout_not_found", fmt.Sprintf("payout %d not found", id), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return newError(ErrConflict, "payout_hold_conflict", err.Error(), err)
+	default:
+		return repositoryError(err)
+	}
+}
+
+// payoutServiceError classifies a failure the transaction client returned
+// for a payout action.
+func payoutServiceError(err error, action string) error {
+	var statusErr *clients.StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusNotFound:
+			return newError(ErrNotFound, "payout_not_found", "transaction service could not find the payout", err)
+		case http.StatusConflict:
+			return newError(ErrConflict, "payout_status_conflict",
+				fmt.Sprintf("transaction service can't %s the payout in its current status", action), err)
+		}
+	}
+	return transactionServiceError(err)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/clients"
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/settings"
+)
+
+func TestPayoutReview(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	now := time.Now().UTC()
+	store.AddMerchant(models.Merchant{ID: 1, BusinessName: "Acme", Status: "active", CreatedAt: now.Add(-90 * 24 * time.Hour)})
+	store.AddMerchant(models.Merchant{ID: 2, BusinessName: "Newco", Status: "active", CreatedAt: now.Add(-48 * time.Hour)})
+	payout := func(id, merchantID int, amount int64, status string) {
+		store.AddPayout(models.Payout{ID: id, MerchantID: merchantID, Amount: amount, Currency: "NGN", Status: status, CreatedAt: now.Add(time.Duration(id-10) * time.Minute)})
+	}
+	payout(1, 1, 600000, models.PayoutStatusPending)
+	payout(2, 2, 1000, models.PayoutStatusPending)
+	payout(3, 1, 1000, models.PayoutStatusPending)
+	payout(4, 1, 900000, "failed")
+
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	rules := "payout_review:\n  thresholds:\n    NGN: 500000\n  new_merchant_age: 168h\n"
+	if err := os.WriteFile(path, []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestService(t, store)
+	var err error
+	if svc.settings, err = settings.NewStore(path); err != nil {
+		t.Fatal(err)
+	}
+	txClient := &fakeTransactionClient{}
+	svc.TransactionClient = txClient
+	analyst := Actor{ID: "analyst-1"}
+	reason := func(r string) dto.PayoutActionRequest { return dto.PayoutActionRequest{Reason: r} }
+
+	result, err := svc.ScreenPayouts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (dto.PayoutScreeningResponse{Screened: 3, Held: 2}) {
+		t.Fatalf("screening = %+v", result)
+	}
+	if result, err = svc.ScreenPayouts(ctx); err != nil || result != (dto.PayoutScreeningResponse{Screened: 1}) {
+		t.Fatalf("second screening = %+v, %v", result, err)
+	}
+	queue, err := svc.ListPayouts(ctx, dto.PayoutListQuery{Held: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queue.Total != 2 || queue.Payouts[0].ID != 2 || queue.Payouts[1].ID != 1 {
+		t.Fatalf("review queue %+v", queue)
+	}
+	if h := queue.Payouts[0].Hold; h.Rule != models.PayoutRuleNewMerchant || h.HeldBy != SystemActor || h.Reason != "merchant signed up less than 7 days before the payout" {
+		t.Errorf("new merchant hold %+v", h)
+	}
+	if h := queue.Payouts[1].Hold; h.Rule != models.PayoutRuleAmountThreshold || h.Reason != "amount 6000.00 NGN is at least the review threshold of 5000.00 NGN" {
+		t.Errorf("threshold hold %+v", h)
+	}
+
+	if _, err := svc.ReleasePayout(ctx, 1, reason("bank details verified"), analyst); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CancelPayout(ctx, 2, reason("merchant under investigation"), analyst); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RetryPayout(ctx, 4, reason("bank outage over"), analyst); err != nil {
+		t.Fatal(err)
+	}
+	held, err := svc.HoldPayout(ctx, 3, reason("customer complaint"), analyst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if held.Hold == nil || held.Hold.Rule != models.PayoutRuleManual || held.Hold.HeldBy != "analyst-1" {
+		t.Errorf("manual hold %+v", held.Hold)
+	}
+	want := map[int][]clients.PayoutAction{
+		1: {clients.PayoutHold, clients.PayoutRelease},
+		2: {clients.PayoutHold, clients.PayoutCancel},
+		3: {clients.PayoutHold},
+		4: {clients.PayoutRetry},
+	}
+	for id, actions := range want {
+		if !slices.Equal(txClient.payouts[id], actions) {
+			t.Errorf("payout %d: transaction service got %v, want %v", id, txClient.payouts[id], actions)
+		}
+	}
+
+	detail, err := svc.GetPayout(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Hold != nil || len(detail.Actions) != 2 || detail.Actions[0].Action != models.PayoutActionRelease || detail.Actions[0].Reason != "bank details verified" {
+		t.Errorf("released payout %+v", detail)
+	}
+	log := store.AuditLog()
+	if last := log[len(log)-1]; last.Actor != "analyst-1" || last.Action != "payout.held" || last.TargetType != "payout" || last.TargetID != "3" {
+		t.Errorf("last audit entry %+v", last)
+	}
+
+	if _, err := svc.ReleasePayout(ctx, 1, reason("again"), analyst); !errors.Is(err, ErrConflict) {
+		t.Errorf("releasing an unheld payout: err = %v, want ErrConflict", err)
+	}
+	if _, err := svc.HoldPayout(ctx, 3, reason("again"), analyst); !errors.Is(err, ErrConflict) {
+		t.Errorf("holding a held payout: err = %v, want ErrConflict", err)
+	}
+	var heldErr *Error
+	if _, err := svc.RetryPayout(ctx, 3, reason("retry"), analyst); !errors.As(err, &heldErr) || heldErr.Code != "payout_held" {
+		t.Errorf("retrying a held payout: err = %v, want payout_held", err)
+	}
+	if _, err := svc.HoldPayout(ctx, 99, reason("unknown"), analyst); !errors.Is(err, ErrNotFound) {
+		t.Errorf("holding a missing payout: err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.CancelPayout(ctx, 3, reason(" "), analyst); !errors.Is(err, ErrValidation) {
+		t.Errorf("cancelling without a reason: err = %v, want ErrValidation", err)
+	}
+	if _, err := svc.ListPayouts(ctx, dto.PayoutListQuery{Since: "yesterday"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("invalid since: err = %v, want ErrValidation", err)
+	}
+
+	txClient.err = &clients.StatusError{StatusCode: 409}
+	_, err = svc.RetryPayout(ctx, 1, reason("try again"), analyst)
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != "payout_status_conflict" {
+		t.Errorf("refused retry: err = %v, want payout_status_conflict", err)
+	}
+}
+
+func TestListPayoutsFilters(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.AddMerchant(models.Merchant{ID: 1, BusinessName: "Acme", CreatedAt: base})
+	store.AddPayout(models.Payout{ID: 1, MerchantID: 1, Amount: 150000, Currency: "NGN", Status: "completed", CreatedAt: base.Add(time.Hour)})
+	store.AddPayout(models.Payout{ID: 2, MerchantID: 1, Amount: 50000, Currency: "NGN", Status: "completed", CreatedAt: base.Add(2 * time.Hour)})
+	store.AddPayout(models.Payout{ID: 3, MerchantID: 1, Amount: 150000, Currency: "USD", Status: "completed", CreatedAt: base.Add(3 * time.Hour)})
+	svc := newTestService(t, store)
+
+	result, err := svc.ListPayouts(ctx, dto.PayoutListQuery{Currency: "ngn", MinAmount: 1000, Until: base.Add(3 * time.Hour).Format(time.RFC3339)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Payouts[0].ID != 1 || result.Payouts[0].Amount != 1500 || result.Payouts[0].MerchantName != "Acme" {
+		t.Errorf("got %+v", result)
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/suspension"
+)
+
+const maxPolicyNameLength = 100
+
+var matchStatuses = map[string]bool{
+	models.MatchStatusSuspended: true,
+	models.MatchStatusPending:   true,
+	models.MatchStatusApproved:  true,
+	models.MatchStatusDismissed: true,
+	models.MatchStatusDryRun:    true,
+}
+
+// policyDefinition validates a suspension policy definition.
+func policyDefinition(req dto.PolicyRequest) (models.SuspensionPolicy, error) {
+	p := models.SuspensionPolicy{
+		Name:            strings.TrimSpace(req.Name),
+		Description:     strings.TrimSpace(req.Description),
+		Condition:       req.Condition,
+		Threshold:       req.Threshold,
+		WindowDays:      req.WindowDays,
+		MinTransactions: req.MinTransactions,
+		Action:          req.Action,
+		DryRun:          req.DryRun == nil || *req.DryRun,
+		Enabled:         req.Enabled,
+	}
+	switch {
+	case p.Name == "":
+		return p, newError(ErrValidation, "name_required", "a policy name is required", nil)
+	case len(p.Name) > maxPolicyNameLength:
+		return p, newError(ErrValidation, "name_too_long", fmt.Sprintf("name must be at most %d characters", maxPolicyNameLength), nil)
+	case !suspension.ValidCondition(p.Condition):
+		return p, newError(ErrValidation, "invalid_condition",
+			fmt.Sprintf("condition %q must be one of chargeback_ratio, failed_ratio, refund_ratio, kyc_rejections", p.Condition), nil)
+	case !suspension.ValidAction(p.Action):
+		return p, newError(ErrValidation, "invalid_action", fmt.Sprintf("action %q must be one of suspend, recommend", p.Action), nil)
+	case suspension.IsRatio(p.Condition) && (p.Threshold <= 0 || p.Threshold > 1):
+		return p, newError(ErrValidation, "invalid_threshold", "a ratio threshold must be above 0 and at most 1", nil)
+	case !suspension.IsRatio(p.Condition) && p.Threshold < 1:
+		return p, newError(ErrValidation, "invalid_threshold", "a kyc_rejections threshold must be at least 1", nil)
+	case p.WindowDays < 1 || p.WindowDays > suspension.MaxWindowDays:
+		return p, newError(ErrValidation, "invalid_window", fmt.Sprintf("window_days must be from 1 to %d", suspension.MaxWindowDays), nil)
+	case p.MinTransactions < 0:
+		return p, newError(ErrValidation, "invalid_min_transactions", "min_transactions must not be negative", nil)
+	}
+	return p, nil
+}
+
+// policyAuditDetails describes a policy definition for the audit log.
+func policyAuditDetails(p models.SuspensionPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		"name": p.Name, "condition": p.Condition, "threshold": p.Threshold, "window_days": p.WindowDays,
+		"min_transactions": p.MinTransactions, "action": p.Action, "dry_run": p.DryRun, "enabled": p.Enabled,
+	}
+}
+
+// CreatePolicy defines a suspension policy.
+func (s *AdminService) CreatePolicy(ctx context.Context, req dto.PolicyRequest, actor Actor) (dto.PolicyResponse, error) {
+	p, err := policyDefinition(req)
+	if err != nil {
+		return dto.PolicyResponse{}, err
+	}
+	p.CreatedBy = actor.ID
+	created, err := s.repo.CreatePolicy(ctx, p, actor.audit("suspension_policy", "suspension_policy.created", policyAuditDetails(p)))
+	if err != nil {
+		return dto.PolicyResponse{}, policyError(err, 0, p.Name)
+	}
+	slog.InfoContext(ctx, "suspension policy created", "policy_id", created.ID, "enabled", created.Enabled, "dry_run", created.DryRun)
+	return dto.NewPolicyResponse(created), nil
+}
+
+// UpdatePolicy replaces a suspension policy's definition, including whether
+// it is enabled and in dry-run mode.
+func (s *AdminService) UpdatePolicy(ctx context.Context, id int64, req dto.PolicyRequest, actor Actor) (dto.PolicyResponse, error) {
+	p, err := policyDefinition(req)
+	if err != nil {
+		return dto.PolicyResponse{}, err
+	}
+	p.ID = id
+	updated, err := s.repo.UpdatePolicy(ctx, p, actor.audit("suspension_policy", "suspension_policy.updated", policyAuditDetails(p)))
+	if err != nil {
+		return dto.PolicyResponse{}, policyError(err, id, p.Name)
+	}
+	slog.InfoContext(ctx, "suspension policy updated", "policy_id", id, "enabled", updated.Enabled, "dry_run", updated.DryRun)
+	return dto.NewPolicyResponse(updated), nil
+}
+
+// ListPolicies returns every suspension policy by name.
+func (s *AdminService) ListPolicies(ctx context.Context) (dto.PolicyListResponse, error) {
+	policies, err := s.repo.ListPolicies(ctx, false)
+	if err != nil {
+		return dto.PolicyListResponse{}, repositoryError(err)
+	}
+	resp := dto.PolicyListResponse{Policies: []dto.PolicyResponse{}, Total: len(policies)}
+	for _, p := range policies {
+		resp.Policies = append(resp.Policies, dto.NewPolicyResponse(p))
+	}
+	return resp, nil
+}
+
+func (s *AdminService) GetPolicy(ctx context.Context, id int64) (dto.PolicyResponse, error) {
+	p, err := s.repo.GetPolicy(ctx, id)
+	if err != nil {
+		return dto.PolicyResponse{}, policyError(err, id, "")
+	}
+	return dto.NewPolicyResponse(p), nil
+}
+
+// EvaluatePolicies evaluates the enabled suspension policies against every
+// merchant that is not already suspended. Policies in dry-run mode record
+// their matches; the others suspend the merchant as the system actor or
+// leave a recommendation for an admin. A merchant is not matched again by
+// the same policy while a recommendation is pending, nor within the policy
+// window of its last match or review.
+func (s *AdminService) EvaluatePolicies(ctx context.Context) (dto.PolicyEvaluationResponse, error) {
+	policies, err := s.repo.ListPolicies(ctx, true)
+	if err != nil {
+		return dto.PolicyEvaluationResponse{}, repositoryError(err)
+	}
+	now := time.Now().UTC()
+	resp := dto.PolicyEvaluationResponse{Policies: len(policies)}
+	activity := map[int][]models.MerchantActivity{}
+	suspended := map[int]bool{}
+	for _, p := range policies {
+		merchants, ok := activity[p.WindowDays]
+		if !ok {
+			if merchants, err = s.repo.MerchantActivity(ctx, suspension.Activity(p, now)); err != nil {
+				return resp, repositoryError(err)
+			}
+			activity[p.WindowDays] = merchants
+		}
+		for _, a := range merchants {
+			if a.Status == "suspended" || suspended[a.MerchantID] {
+				continue
+			}
+			value, matched := suspension.Evaluate(p, a)
+			if !matched {
+				continue
+			}
+			status, err := s.applyPolicy(ctx, p, a.MerchantID, value, now)
+			if err != nil {
+				return resp, err
+			}
+			switch status {
+			case models.MatchStatusSuspended:
+				resp.Suspended++
+				suspended[a.MerchantID] = true
+			case models.MatchStatusPending:
+				resp.Recommended++
+			case models.MatchStatusDryRun:
+				resp.DryRun++
+			}
+		}
+	}
+	slog.InfoContext(ctx, "evaluated suspension policies",
+		"policies", resp.Policies, "suspended", resp.Suspended, "recommended", resp.Recommended, "dry_run", resp.DryRun)
+	return resp, nil
+}
+
+// applyPolicy records that p matched a merchant and acts on it, returning
+// the new match's status, or "" when the match was held back. A suspending
+// match is recorded in the same transaction as the suspension, so a failed
+// suspension leaves no match behind to hold back the next evaluation.
+func (s *AdminService) applyPolicy(ctx context.Context, p models.SuspensionPolicy, merchantID int, value float64, now time.Time) (string, error) {
+	status := models.MatchStatusPending
+	switch {
+	case p.DryRun:
+		status = models.MatchStatusDryRun
+	case p.Action == models.PolicyActionSuspend:
+		status = models.MatchStatusSuspended
+	}
+	system := Actor{ID: SystemActor}
+	m, recorded, err := s.repo.RecordPolicyMatch(ctx, models.PolicyMatch{
+		PolicyID: p.ID, MerchantID: merchantID, Value: value, Action: p.Action, Status: status,
+	}, now.Add(-suspension.Window(p)), system.audit("merchant", "merchant.suspended", map[string]interface{}{
+		"policy_id": p.ID, "condition": p.Condition, "value": value,
+	}))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to apply suspension policy", "policy_id", p.ID, "merchant_id", merchantID, "error", err)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return "", newError(ErrNotFound, "merchant_not_found", fmt.Sprintf("merchant %d not found", merchantID), err)
+		}
+		return "", repositoryError(err)
+	}
+	if !recorded {
+		return "", nil
+	}
+	metrics.SuspensionPolicyMatches.WithLabelValues(status).Inc()
+	slog.InfoContext(ctx, "suspension policy matched merchant",
+		"policy_id", p.ID, "merchant_id", merchantID, "policy_match_id", m.ID, "value", value, "status", status)
+	if status == models.MatchStatusSuspended {
+		slog.InfoContext(ctx, "merchant suspended", "merchant_id", merchantID, "actor", SystemActor)
+		metrics.MerchantStatusChanges.WithLabelValues("suspended").Inc()
+	}
+	return status, nil
+}
+
+// ListPolicyMatches lists what suspension policies matched, newest first.
+func (s *AdminService) ListPolicyMatches(ctx context.Context, q dto.PolicyMatchListQuery) (dto.PolicyMatchListResponse, error) {
+	if q.Status != "" && !matchStatuses[q.Status] {
+		return dto.PolicyMatchListResponse{}, newError(ErrValidation, "invalid_match_status",
+			fmt.Sprintf("status %q must be one of suspended, pending, approved, dismissed, dry_run", q.Status), nil)
+	}
+	matches, err := s.repo.ListPolicyMatches(ctx, models.PolicyMatchFilter{
+		PolicyID: q.PolicyID, MerchantID: q.MerchantID, Status: q.Status, Limit: q.Limit,
+	})
+	if err != nil {
+		return dto.PolicyMatchListResponse{}, repositoryError(err)
+	}
+	resp := dto.PolicyMatchListResponse{Matches: []dto.PolicyMatchResponse{}, Total: len(matches)}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, dto.NewPolicyMatchResponse(m))
+	}
+	return resp, nil
+}
+
+// ApprovePolicyMatch suspends the merchant a pending recommendation is for.
+func (s *AdminService) ApprovePolicyMatch(ctx context.Context, id int64, req dto.ReviewPolicyMatchRequest, actor Actor) (dto.PolicyMatchResponse, error) {
+	note, err := reviewNote(req.Note)
+	if err != nil {
+		return dto.PolicyMatchResponse{}, err
+	}
+	m, err := s.repo.GetPolicyMatch(ctx, id)
+	if err != nil {
+		return dto.PolicyMatchResponse{}, policyMatchError(err, id)
+	}
+	if m.Status != models.MatchStatusPending {
+		return dto.PolicyMatchResponse{}, newError(ErrConflict, "policy_match_status_conflict", fmt.Sprintf("policy match %d is %s", id, m.Status), nil)
+	}
+	if _, err := s.suspendMerchant(ctx, m.MerchantID, actor.audit("merchant", "merchant.suspended", map[string]interface{}{
+		"policy_id": m.PolicyID, "policy_match_id": id,
+	})); err != nil {
+		return dto.PolicyMatchResponse{}, err
+	}
+	return s.reviewPolicyMatch(ctx, id, models.MatchStatusApproved, note, actor)
+}
+
+// DismissPolicyMatch declines a pending recommendation.
+func (s *AdminService) DismissPolicyMatch(ctx context.Context, id int64, req dto.ReviewPolicyMatchRequest, actor Actor) (dto.PolicyMatchResponse, error) {
+	note, err := reviewNote(req.Note)
+	if err != nil {
+		return dto.PolicyMatchResponse{}, err
+	}
+	return s.reviewPolicyMatch(ctx, id, models.MatchStatusDismissed, note, actor)
+}
+
+func (s *AdminService) reviewPolicyMatch(ctx context.Context, id int64, status, note string, actor Actor) (dto.PolicyMatchResponse, error) {
+	m, err := s.repo.ReviewPolicyMatch(ctx, id, status, actor.ID, note,
+		actor.audit("suspension_policy_match", "suspension_policy_match."+status, map[string]interface{}{"note": note}))
+	if err != nil {
+		return dto.PolicyMatchResponse{}, policyMatchError(err, id)
+	}
+	slog.InfoContext(ctx, "suspension recommendation reviewed", "policy_match_id", id, "status", status)
+	return dto.NewPolicyMatchResponse(m), nil
+}
+
+func reviewNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len(note) > maxReasonLength {
+		return "", newError(ErrValidation, "note_too_long", fmt.Sprintf("note must be at most %d characters", maxReasonLength), nil)
+	}
+	return note, nil
+}
+
+func policyError(err error, id int64, name string) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return newError(ErrNotFound, "policy_not_found", fmt.Sprintf("suspension policy %d not found", id), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return newError(ErrConflict, "policy_name_taken", fmt.Sprintf("a suspension policy named %q already exists", name), err)
+	default:
+		return repositoryError(err)
+	}
+}
+
+func policyMatchError(err error, id int64) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return newError(ErrNotFound, "policy_match_not_found", fmt.Sprintf("policy match %d not found", id), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return newError(ErrConflict, "policy_match_status_conflict", err.Error(), err)
+	default:
+		return repositoryError(err)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestEvaluatePolicies(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	now := time.Now().UTC()
+	for id := 1; id <= 3; id++ {
+		store.AddMerchant(models.Merchant{ID: id, Status: "active", KYCStatus: "completed", CreatedAt: now.Add(-90 * 24 * time.Hour)})
+	}
+	txID := 0
+	pay := func(merchantID int, status string, n int) {
+		for i := 0; i < n; i++ {
+			txID++
+			store.AddTransaction(models.Transaction{ID: txID, MerchantID: merchantID, Amount: 1000, Status: status, CreatedAt: now.Add(-time.Duration(txID) * time.Minute)})
+		}
+	}
+	pay(1, "successful", 98)
+	pay(1, "chargeback", 2)
+	pay(2, "successful", 70)
+	pay(2, "failed", 30)
+	for _, days := range []int{3, 10} {
+		if err := store.RecordKYCRejection(ctx, 3, now.Add(-time.Duration(days)*24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := newTestService(t, store)
+	analyst := Actor{ID: "analyst-1"}
+	live := false
+	create := func(req dto.PolicyRequest) dto.PolicyResponse {
+		t.Helper()
+		req.Enabled, req.WindowDays = true, 30
+		p, err := svc.CreatePolicy(ctx, req, analyst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	create(dto.PolicyRequest{Name: "chargebacks", Condition: models.PolicyChargebackRatio, Threshold: 0.015, MinTransactions: 20, Action: models.PolicyActionSuspend, DryRun: &live})
+	create(dto.PolicyRequest{Name: "kyc", Condition: models.PolicyKYCRejections, Threshold: 2, Action: models.PolicyActionRecommend, DryRun: &live})
+	trial := create(dto.PolicyRequest{Name: "failures", Condition: models.PolicyFailedRatio, Threshold: 0.25, Action: models.PolicyActionSuspend})
+	if !trial.DryRun {
+		t.Errorf("a new policy is not in dry-run mode by default")
+	}
+
+	result, err := svc.EvaluatePolicies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (dto.PolicyEvaluationResponse{Policies: 3, Suspended: 1, Recommended: 1, DryRun: 1}) {
+		t.Fatalf("evaluation = %+v", result)
+	}
+	if result, err = svc.EvaluatePolicies(ctx); err != nil || result != (dto.PolicyEvaluationResponse{Policies: 3}) {
+		t.Fatalf("second evaluation = %+v, %v", result, err)
+	}
+	status := func(id int) string {
+		t.Helper()
+		m, err := svc.GetMerchant(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m.Status
+	}
+	if status(1) != "suspended" || status(2) != "active" || status(3) != "active" {
+		t.Errorf("statuses after evaluation: %s %s %s", status(1), status(2), status(3))
+	}
+	log := store.AuditLog()
+	if last := log[len(log)-1]; last.Actor != SystemActor || last.Action != "merchant.suspended" || last.TargetID != "1" {
+		t.Errorf("suspension audit entry %+v", last)
+	}
+
+	pending, err := svc.ListPolicyMatches(ctx, dto.PolicyMatchListQuery{Status: models.MatchStatusPending})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Total != 1 || pending.Matches[0].MerchantID != 3 || pending.Matches[0].PolicyName != "kyc" || pending.Matches[0].Value != 2 {
+		t.Fatalf("pending recommendations %+v", pending)
+	}
+	matchID := pending.Matches[0].ID
+	approved, err := svc.ApprovePolicyMatch(ctx, matchID, dto.ReviewPolicyMatchRequest{Note: "confirmed with compliance"}, analyst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != models.MatchStatusApproved || approved.ReviewedBy != "analyst-1" || status(3) != "suspended" {
+		t.Errorf("approved %+v, merchant %s", approved, status(3))
+	}
+	if _, err := svc.DismissPolicyMatch(ctx, matchID, dto.ReviewPolicyMatchRequest{}, analyst); !errors.Is(err, ErrConflict) {
+		t.Errorf("dismissing a reviewed match: err = %v, want ErrConflict", err)
+	}
+	if _, err := svc.ApprovePolicyMatch(ctx, matchID+100, dto.ReviewPolicyMatchRequest{}, analyst); !errors.Is(err, ErrNotFound) {
+		t.Errorf("approving a missing match: err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.ListPolicyMatches(ctx, dto.PolicyMatchListQuery{Status: "open"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown status: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestPolicyValidation(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, repositories.NewMemoryStore())
+	valid := dto.PolicyRequest{Name: "chargebacks", Condition: models.PolicyChargebackRatio, Threshold: 0.015, WindowDays: 30, Action: models.PolicyActionSuspend}
+
+	tests := []struct {
+		name   string
+		change func(r *dto.PolicyRequest)
+		code   string
+	}{
+		{"no name", func(r *dto.PolicyRequest) { r.Name = " " }, "name_required"},
+		{"unknown condition", func(r *dto.PolicyRequest) { r.Condition = "velocity" }, "invalid_condition"},
+		{"unknown action", func(r *dto.PolicyRequest) { r.Action = "block" }, "invalid_action"},
+		{"ratio above 1", func(r *dto.PolicyRequest) { r.Threshold = 1.5 }, "invalid_threshold"},
+		{"no kyc rejections", func(r *dto.PolicyRequest) { r.Condition, r.Threshold = models.PolicyKYCRejections, 0 }, "invalid_threshold"},
+		{"no window", func(r *dto.PolicyRequest) { r.WindowDays = 0 }, "invalid_window"},
+		{"negative minimum", func(r *dto.PolicyRequest) { r.MinTransactions = -1 }, "invalid_min_transactions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.change(&req)
+			_, err := svc.CreatePolicy(ctx, req, Actor{ID: "analyst-1"})
+			var svcErr *Error
+			if !errors.As(err, &svcErr) || svcErr.Code != tt.code {
+				t.Errorf("got %v, want code %s", err, tt.code)
+			}
+		})
+	}
+
+	p, err := svc.CreatePolicy(ctx, valid, Actor{ID: "analyst-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreatePolicy(ctx, valid, Actor{ID: "analyst-1"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("duplicate name: err = %v, want ErrConflict", err)
+	}
+	if _, err := svc.UpdatePolicy(ctx, p.ID+1, valid, Actor{ID: "analyst-1"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("updating a missing policy: err = %v, want ErrNotFound", err)
+	}
+}
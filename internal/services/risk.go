@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/risk"
+)
+
+// maxMerchantListLimit bounds a merchant listing, and is its default.
+const maxMerchantListLimit = 200
+
+// merchantFilter validates a merchant listing query.
+func merchantFilter(q dto.MerchantListQuery) (models.MerchantFilter, error) {
+	f := models.MerchantFilter{RiskLevel: q.RiskLevel, MinRiskScore: q.MinRiskScore, Limit: q.Limit}
+	switch q.Sort {
+	case "", "newest":
+	case "risk":
+		f.SortByRisk = true
+	default:
+		return f, newError(ErrValidation, "invalid_sort", fmt.Sprintf("unknown sort %q, use newest or risk", q.Sort), nil)
+	}
+	if q.RiskLevel != "" && !risk.ValidLevel(q.RiskLevel) {
+		return f, newError(ErrValidation, "invalid_risk_level",
+			fmt.Sprintf("unknown risk level %q, use %s, %s or %s", q.RiskLevel, models.RiskLow, models.RiskMedium, models.RiskHigh), nil)
+	}
+	if q.MinRiskScore < 0 || q.MinRiskScore > 100 {
+		return f, newError(ErrValidation, "invalid_risk_score", "min_risk_score must be between 0 and 100", nil)
+	}
+	if f.Limit <= 0 || f.Limit > maxMerchantListLimit {
+		f.Limit = maxMerchantListLimit
+	}
+	return f, nil
+}
+
+// RecalculateRiskScores scores every merchant from its recent activity,
+// replacing the stored scores.
+func (s *AdminService) RecalculateRiskScores(ctx context.Context) (dto.RiskRecalculationResponse, error) {
+	now := time.Now().UTC()
+	activity, err := s.repo.MerchantActivity(ctx, risk.Activity(now))
+	if err != nil {
+		return dto.RiskRecalculationResponse{}, repositoryError(err)
+	}
+
+	levels := map[string]int{models.RiskLow: 0, models.RiskMedium: 0, models.RiskHigh: 0}
+	scores := make([]models.RiskScore, len(activity))
+	for i, a := range activity {
+		scores[i] = risk.Score(a, now)
+		levels[scores[i].Level]++
+	}
+	if err := s.repo.SaveRiskScores(ctx, scores); err != nil {
+		return dto.RiskRecalculationResponse{}, repositoryError(err)
+	}
+
+	for level, n := range levels {
+		metrics.MerchantRiskLevels.WithLabelValues(level).Set(float64(n))
+	}
+	slog.InfoContext(ctx, "recalculated merchant risk scores", "merchants", len(scores), "high_risk", levels[models.RiskHigh])
+	return dto.RiskRecalculationResponse{Merchants: len(scores), Levels: levels, ComputedAt: now}, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestRecalculateRiskScores(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	now := time.Now().UTC()
+	store.AddMerchant(models.Merchant{ID: 1, Name: "Old", Status: "active", KYCStatus: "completed", CreatedAt: now.Add(-400 * 24 * time.Hour)})
+	store.AddMerchant(models.Merchant{ID: 2, Name: "Risky", Status: "active", KYCStatus: "pending", CreatedAt: now.Add(-24 * time.Hour)})
+	for i := 1; i <= 20; i++ {
+		status := "successful"
+		if i%2 == 0 {
+			status = "chargeback"
+		}
+		store.AddTransaction(models.Transaction{ID: i, MerchantID: 2, Amount: 1000, Status: status, CreatedAt: now.Add(-time.Hour)})
+	}
+	svc := newTestService(t, store)
+
+	// unscored merchants are listed without a risk score
+	merchants, err := svc.ListMerchants(ctx, dto.MerchantListQuery{Sort: "risk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merchants) != 2 || merchants[0].Risk != nil {
+		t.Fatalf("before scoring: %+v", merchants)
+	}
+
+	result, err := svc.RecalculateRiskScores(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Merchants != 2 || result.Levels[models.RiskMedium] != 1 || result.Levels[models.RiskLow] != 1 {
+		t.Errorf("recalculation = %+v", result)
+	}
+
+	merchants, err = svc.ListMerchants(ctx, dto.MerchantListQuery{Sort: "risk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merchants[0].ID != 2 || merchants[0].Risk.Level != models.RiskMedium || merchants[0].Risk.Factors != nil {
+		t.Errorf("riskiest first: %+v", merchants[0])
+	}
+	medium, err := svc.ListMerchants(ctx, dto.MerchantListQuery{RiskLevel: models.RiskMedium})
+	if err != nil || len(medium) != 1 {
+		t.Errorf("medium risk merchants = %+v, %v", medium, err)
+	}
+
+	detail, err := svc.GetMerchant(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Risk == nil || len(detail.Risk.Factors) != 6 || detail.Risk.Factors[0].Score != 30 {
+		t.Errorf("detail risk = %+v", detail.Risk)
+	}
+	if _, err := svc.GetMerchant(ctx, 99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing merchant: err = %v, want ErrNotFound", err)
+	}
+	for _, q := range []dto.MerchantListQuery{{Sort: "oldest"}, {RiskLevel: "severe"}, {MinRiskScore: 101}} {
+		if _, err := svc.ListMerchants(ctx, q); !errors.Is(err, ErrValidation) {
+			t.Errorf("ListMerchants(%+v): err = %v, want ErrValidation", q, err)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+	"github.com/kodra-pay/admin-service/internal/rules"
+)
+
+const (
+	maxRuleNameLength   = 100
+	maxExpressionLength = 2000
+	// maxRuleRange bounds how much history one dry run or evaluation reads.
+	maxRuleRange = 92 * 24 * time.Hour
+	// defaultDryRunMatches and maxDryRunMatches bound the matches a dry run lists.
+	defaultDryRunMatches = 100
+	maxDryRunMatches     = 1000
+	// flagBatchSize is how many flags an evaluation buffers before storing them.
+	flagBatchSize = 500
+)
+
+// compileRule validates a rule expression and compiles it.
+func compileRule(expression string) (*rules.Program, error) {
+	if expression == "" {
+		return nil, newError(ErrValidation, "expression_required", "a rule expression is required", nil)
+	}
+	if len(expression) > maxExpressionLength {
+		return nil, newError(ErrValidation, "expression_too_long", fmt.Sprintf("expression must be at most %d characters", maxExpressionLength), nil)
+	}
+	prog, err := rules.Compile(expression)
+	if err != nil {
+		return nil, newError(ErrValidation, "invalid_expression", "invalid rule expression: "+err.Error(), err)
+	}
+	return prog, nil
+}
+
+// factQuery validates the range of transactions to evaluate rules against,
+// which may span at most maxRange.
+func factQuery(r dto.RuleRangeRequest, maxRange time.Duration, windows []models.VelocityWindow) (models.FactQuery, error) {
+	if r.To.IsZero() {
+		r.To = time.Now()
+	}
+	switch {
+	case r.From.IsZero():
+		return models.FactQuery{}, newError(ErrValidation, "range_required", "from is required", nil)
+	case !r.From.Before(r.To):
+		return models.FactQuery{}, newError(ErrValidation, "invalid_range", "from must be before to", nil)
+	case r.To.Sub(r.From) > maxRange:
+		return models.FactQuery{}, newError(ErrValidation, "range_too_long", fmt.Sprintf("the range may span at most %d days", int(maxRange.Hours()/24)), nil)
+	}
+	return models.FactQuery{From: r.From, To: r.To, Windows: windows}, nil
+}
+
+// ruleError classifies a repository failure acting on a single rule.
+func ruleError(err error, id int64) error {
+	if errors.Is(err, repositories.ErrNotFound) {
+		return newError(ErrNotFound, "rule_not_found", fmt.Sprintf("fraud rule %d not found", id), err)
+	}
+	return repositoryError(err)
+}
+
+// CreateRule defines a fraud rule at version 1.
+func (s *AdminService) CreateRule(ctx context.Context, req dto.CreateRuleRequest, actor Actor) (dto.RuleResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return dto.RuleResponse{}, newError(ErrValidation, "name_required", "a rule name is required", nil)
+	}
+	if len(name) > maxRuleNameLength {
+		return dto.RuleResponse{}, newError(ErrValidation, "name_too_long", fmt.Sprintf("name must be at most %d characters", maxRuleNameLength), nil)
+	}
+	expression := strings.TrimSpace(req.Expression)
+	if _, err := compileRule(expression); err != nil {
+		return dto.RuleResponse{}, err
+	}
+
+	rule, err := s.repo.CreateRule(ctx, models.FraudRule{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Enabled:     req.Enabled,
+		Expression:  expression,
+		CreatedBy:   actor.ID,
+	}, actor.audit("fraud_rule", "fraud_rule.created", map[string]interface{}{
+		"name": name, "expression": expression, "enabled": req.Enabled,
+	}))
+	if errors.Is(err, repositories.ErrConflict) {
+		return dto.RuleResponse{}, newError(ErrConflict, "rule_name_taken", fmt.Sprintf("a rule named %q already exists", name), err)
+	}
+	if err != nil {
+		return dto.RuleResponse{}, repositoryError(err)
+	}
+	slog.InfoContext(ctx, "fraud rule created", "rule_id", rule.ID, "enabled", rule.Enabled)
+	return dto.NewRuleResponse(rule), nil
+}
+
+// ReviseRule saves a new version of a rule's expression. Earlier versions are
+// kept, and flags record the version that raised them.
+func (s *AdminService) ReviseRule(ctx context.Context, id int64, expression string, actor Actor) (dto.RuleResponse, error) {
+	expression = strings.TrimSpace(expression)
+	if _, err := compileRule(expression); err != nil {
+		return dto.RuleResponse{}, err
+	}
+	rule, err := s.repo.ReviseRule(ctx, id, expression, actor.ID,
+		actor.audit("fraud_rule", "fraud_rule.revised", map[string]interface{}{"expression": expression}))
+	if err != nil {
+		return dto.RuleResponse{}, ruleError(err, id)
+	}
+	return dto.NewRuleResponse(rule), nil
+}
+
+// SetRuleEnabled turns a rule on or off for evaluations.
+func (s *AdminService) SetRuleEnabled(ctx context.Context, id int64, enabled bool, actor Actor) (dto.RuleResponse, error) {
+	action := "fraud_rule.disabled"
+	if enabled {
+		action = "fraud_rule.enabled"
+	}
+	rule, err := s.repo.SetRuleEnabled(ctx, id, enabled, actor.audit("fraud_rule", action, nil))
+	if err != nil {
+		return dto.RuleResponse{}, ruleError(err, id)
+	}
+	return dto.NewRuleResponse(rule), nil
+}
+
+// ListRules returns every fraud rule by name.
+func (s *AdminService) ListRules(ctx context.Context) (dto.RuleListResponse, error) {
+	list, err := s.repo.ListRules(ctx, false)
+	if err != nil {
+		return dto.RuleListResponse{}, repositoryError(err)
+	}
+	resp := dto.RuleListResponse{Rules: []dto.RuleResponse{}, Total: len(list)}
+	for _, r := range list {
+		resp.Rules = append(resp.Rules, dto.NewRuleResponse(r))
+	}
+	return resp, nil
+}
+
+// GetRule returns a fraud rule with its versions, newest first.
+func (s *AdminService) GetRule(ctx context.Context, id int64) (dto.RuleDetailResponse, error) {
+	rule, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return dto.RuleDetailResponse{}, ruleError(err, id)
+	}
+	versions, err := s.repo.ListRuleVersions(ctx, id)
+	if err != nil {
+		return dto.RuleDetailResponse{}, repositoryError(err)
+	}
+	resp := dto.RuleDetailResponse{RuleResponse: dto.NewRuleResponse(rule), Versions: []dto.RuleVersionResponse{}}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, dto.RuleVersionResponse{
+			Version: v.Version, Expression: v.Expression, CreatedBy: v.CreatedBy, CreatedAt: v.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// DryRunExpression evaluates an unsaved rule expression against past
+// transactions without raising flags.
+func (s *AdminService) DryRunExpression(ctx context.Context, req dto.DryRunRequest) (dto.DryRunResponse, error) {
+	prog, err := compileRule(strings.TrimSpace(req.Expression))
+	if err != nil {
+		return dto.DryRunResponse{}, err
+	}
+	return s.dryRun(ctx, prog, req)
+}
+
+// DryRunRule evaluates a saved rule's current version against past
+// transactions without raising flags.
+func (s *AdminService) DryRunRule(ctx context.Context, id int64, req dto.DryRunRequest) (dto.DryRunResponse, error) {
+	rule, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return dto.DryRunResponse{}, ruleError(err, id)
+	}
+	prog, err := compileRule(rule.Expression)
+	if err != nil {
+		return dto.DryRunResponse{}, err
+	}
+	return s.dryRun(ctx, prog, req)
+}
+
+func (s *AdminService) dryRun(ctx context.Context, prog *rules.Program, req dto.DryRunRequest) (dto.DryRunResponse, error) {
+	q, err := factQuery(req.RuleRangeRequest, maxRuleRange, prog.Windows())
+	if err != nil {
+		return dto.DryRunResponse{}, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDryRunMatches
+	}
+	limit = min(limit, maxDryRunMatches)
+
+	resp := dto.DryRunResponse{Matches: []dto.RuleMatchResponse{}}
+	err = s.repo.EachTransactionFacts(ctx, q, func(f models.TransactionFacts) error {
+		resp.Evaluated++
+		matched, err := prog.Match(&f)
+		if err != nil {
+			resp.Errors++
+			return nil
+		}
+		if !matched {
+			return nil
+		}
+		resp.Matched++
+		if len(resp.Matches) == limit {
+			resp.Truncated = true
+			return nil
+		}
+		resp.Matches = append(resp.Matches, dto.RuleMatchResponse{
+			TransactionID: f.ID, MerchantID: f.MerchantID, Amount: f.Amount, Currency: f.Currency, CreatedAt: f.CreatedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return dto.DryRunResponse{}, repositoryError(err)
+	}
+	return resp, nil
+}
+
+// EvaluateRules runs every enabled rule over the transactions in a range and
+// flags the matches. Flags already raised by the same rule version are not
+// duplicated, so overlapping ranges can be evaluated again safely.
+func (s *AdminService) EvaluateRules(ctx context.Context, req dto.RuleRangeRequest) (dto.EvaluateRulesResponse, error) {
+	q, err := factQuery(req, maxRuleRange, nil)
+	if err != nil {
+		return dto.EvaluateRulesResponse{}, err
+	}
+	enabled, err := s.repo.ListRules(ctx, true)
+	if err != nil {
+		return dto.EvaluateRulesResponse{}, repositoryError(err)
+	}
+	type compiled struct {
+		rule models.FraudRule
+		prog *rules.Program
+	}
+	var active []compiled
+	var programs []*rules.Program
+	for _, r := range enabled {
+		prog, err := rules.Compile(r.Expression)
+		if err != nil {
+			// Expressions are validated when saved, so this only happens when
+			// the language drops something an old rule relies on.
+			slog.ErrorContext(ctx, "skipping fraud rule that no longer compiles", "rule_id", r.ID, "error", err)
+			continue
+		}
+		active = append(active, compiled{rule: r, prog: prog})
+		programs = append(programs, prog)
+	}
+
+	q.Windows = rules.Windows(programs...)
+	resp := dto.EvaluateRulesResponse{Rules: len(active)}
+	if len(active) == 0 {
+		return resp, nil
+	}
+
+	var pending []models.RuleFlag
+	perRule := map[int64]int{}
+	flush := func() error {
+		n, err := s.repo.RecordRuleFlags(ctx, pending)
+		if err != nil {
+			return err
+		}
+		resp.FlagsCreated += n
+		pending = pending[:0]
+		return nil
+	}
+	err = s.repo.EachTransactionFacts(ctx, q, func(f models.TransactionFacts) error {
+		resp.Evaluated++
+		for _, c := range active {
+			matched, err := c.prog.Match(&f)
+			if err != nil {
+				resp.Errors++
+				continue
+			}
+			if matched {
+				resp.Matched++
+				perRule[c.rule.ID]++
+				pending = append(pending, models.RuleFlag{RuleID: c.rule.ID, RuleVersion: c.rule.Version, TransactionID: f.ID})
+			}
+		}
+		if len(pending) >= flagBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return resp, repositoryError(err)
+	}
+
+	for _, c := range active {
+		if n := perRule[c.rule.ID]; n > 0 {
+			metrics.FraudRuleMatches.WithLabelValues(c.rule.Name).Add(float64(n))
+		}
+	}
+	slog.InfoContext(ctx, "fraud rules evaluated", "rules", resp.Rules, "evaluated", resp.Evaluated,
+		"matched", resp.Matched, "flags_created", resp.FlagsCreated, "errors", resp.Errors)
+	return resp, nil
+}
+
+// ListRuleFlags returns flags raised by rule evaluations, newest first.
+func (s *AdminService) ListRuleFlags(ctx context.Context, q dto.FlagListQuery) (dto.RuleFlagListResponse, error) {
+	flags, err := s.repo.ListRuleFlags(ctx, models.FlagFilter{RuleID: q.RuleID, TransactionID: q.TransactionID, Limit: q.Limit})
+	if err != nil {
+		return dto.RuleFlagListResponse{}, repositoryError(err)
+	}
+	resp := dto.RuleFlagListResponse{Flags: []dto.RuleFlagResponse{}, Total: len(flags)}
+	for _, f := range flags {
+		resp.Flags = append(resp.Flags, dto.RuleFlagResponse{
+			ID: f.ID, RuleID: f.RuleID, RuleName: f.RuleName, RuleVersion: f.RuleVersion, TransactionID: f.TransactionID, CreatedAt: f.CreatedAt,
+		})
+	}
+	return resp, nil
+}
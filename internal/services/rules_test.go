@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestEvaluateRulesFlagsMatchesOnce(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	svc := newTestService(t, store)
+	actor := Actor{ID: "admin-1"}
+	now := time.Now().UTC()
+	store.AddMerchant(models.Merchant{ID: 1, Name: "Acme", CreatedAt: now.Add(-48 * time.Hour)})
+	for i, amount := range []int64{1000, 90000, 120000} {
+		store.AddTransaction(models.Transaction{
+			ID: i + 1, MerchantID: 1, Amount: amount, Currency: "USD",
+			CardFingerprint: "fp-1", CreatedAt: now.Add(time.Duration(i-3) * time.Minute),
+		})
+	}
+
+	big, err := svc.CreateRule(ctx, dto.CreateRuleRequest{Name: "big", Expression: "amount > 800", Enabled: true}, actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateRule(ctx, dto.CreateRuleRequest{Name: "burst", Expression: `velocity("card", "10m") >= 3`, Enabled: true}, actor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateRule(ctx, dto.CreateRuleRequest{Name: "off", Expression: "amount > 0"}, actor); err != nil {
+		t.Fatal(err)
+	}
+
+	rng := dto.RuleRangeRequest{From: now.Add(-time.Hour), To: now.Add(time.Minute)}
+	resp, err := svc.EvaluateRules(ctx, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != (dto.EvaluateRulesResponse{Rules: 2, Evaluated: 3, Matched: 3, FlagsCreated: 3}) {
+		t.Errorf("first evaluation = %+v", resp)
+	}
+	resp, err = svc.EvaluateRules(ctx, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.FlagsCreated != 0 {
+		t.Errorf("re-evaluation created %d flags, want 0", resp.FlagsCreated)
+	}
+
+	// A new version flags its matches afresh.
+	if _, err := svc.ReviseRule(ctx, big.ID, "amount > 1000", actor); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err = svc.EvaluateRules(ctx, rng); err != nil {
+		t.Fatal(err)
+	}
+	if resp.FlagsCreated != 1 {
+		t.Errorf("evaluation after revision created %d flags, want 1", resp.FlagsCreated)
+	}
+
+	flags, err := svc.ListRuleFlags(ctx, dto.FlagListQuery{RuleID: big.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags.Total != 3 || flags.Flags[0].RuleVersion != 2 || flags.Flags[0].RuleName != "big" {
+		t.Errorf("flags for rule big = %+v", flags.Flags)
+	}
+}
+
+func TestDryRunExpression(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	svc := newTestService(t, store)
+	now := time.Now().UTC()
+	store.AddMerchant(models.Merchant{ID: 1, Name: "Acme", CreatedAt: now})
+	for i := 1; i <= 3; i++ {
+		store.AddTransaction(models.Transaction{ID: i, MerchantID: 1, Amount: int64(i) * 100, Currency: "USD", CreatedAt: now.Add(-time.Minute)})
+	}
+
+	resp, err := svc.DryRunExpression(ctx, dto.DryRunRequest{
+		RuleRangeRequest: dto.RuleRangeRequest{From: now.Add(-time.Hour)},
+		Expression:       "amount >= 2",
+		Limit:            1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Evaluated != 3 || resp.Matched != 2 || len(resp.Matches) != 1 || !resp.Truncated {
+		t.Errorf("dry run = %+v", resp)
+	}
+
+	_, err = svc.DryRunExpression(ctx, dto.DryRunRequest{
+		RuleRangeRequest: dto.RuleRangeRequest{From: now.Add(-time.Hour)},
+		Expression:       "amount >",
+	})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != "invalid_expression" {
+		t.Errorf("dry run of a broken expression: err = %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/metrics"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+const (
+	// maxReleaseDays bounds how long a rolling reserve keeps each settlement.
+	maxReleaseDays = 365
+	// maxSettlementHolds bounds how many holds in effect are loaded at once.
+	maxSettlementHolds = 10000
+)
+
+var settlementHoldTypes = map[string]bool{
+	models.SettlementFullHold:       true,
+	models.SettlementRollingReserve: true,
+	models.SettlementFixedReserve:   true,
+}
+
+// settlementHold validates a hold request, returning the hold it describes
+// with only the terms of its type set.
+func settlementHold(merchantID int, req dto.SettlementHoldRequest, actor Actor) (models.SettlementHold, error) {
+	h := models.SettlementHold{MerchantID: merchantID, Type: req.Type, Reason: strings.TrimSpace(req.Reason), CreatedBy: actor.ID}
+	if err := validateReason(h.Reason); err != nil {
+		return models.SettlementHold{}, err
+	}
+	switch req.Type {
+	case models.SettlementFullHold:
+	case models.SettlementRollingReserve:
+		if req.ReservePercent <= 0 || req.ReservePercent > 100 {
+			return models.SettlementHold{}, newError(ErrValidation, "invalid_reserve_percent", "reserve_percent must be above 0 and at most 100", nil)
+		}
+		if req.ReleaseDays < 1 || req.ReleaseDays > maxReleaseDays {
+			return models.SettlementHold{}, newError(ErrValidation, "invalid_release_days",
+				fmt.Sprintf("release_days must be from 1 to %d", maxReleaseDays), nil)
+		}
+		h.ReservePercent, h.ReleaseDays = req.ReservePercent, req.ReleaseDays
+	case models.SettlementFixedReserve:
+		h.ReserveAmount = int64(math.Round(req.ReserveAmount * 100))
+		if h.ReserveAmount <= 0 {
+			return models.SettlementHold{}, newError(ErrValidation, "invalid_reserve_amount", "reserve_amount must be positive", nil)
+		}
+		h.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+		if len(h.Currency) != 3 {
+			return models.SettlementHold{}, newError(ErrValidation, "invalid_currency", "currency must be a three-letter currency code", nil)
+		}
+	default:
+		return models.SettlementHold{}, newError(ErrValidation, "invalid_hold_type",
+			fmt.Sprintf("type %q must be one of full_hold, rolling_reserve, fixed_reserve", req.Type), nil)
+	}
+	return h, nil
+}
+
+// SetSettlementHold puts a merchant's settlement on hold, replacing any hold
+// already in effect. The payout screening holds every pending payout of a
+// merchant under a full hold for review.
+func (s *AdminService) SetSettlementHold(ctx context.Context, merchantID int, req dto.SettlementHoldRequest, actor Actor) (dto.SettlementHoldResponse, error) {
+	h, err := settlementHold(merchantID, req, actor)
+	if err != nil {
+		return dto.SettlementHoldResponse{}, err
+	}
+	if _, err := s.GetMerchant(ctx, merchantID); err != nil {
+		return dto.SettlementHoldResponse{}, err
+	}
+	created, err := s.repo.SetSettlementHold(ctx, h, actor.audit("merchant", "settlement_hold.set", map[string]interface{}{
+		"type": h.Type, "reserve_percent": h.ReservePercent, "release_days": h.ReleaseDays,
+		"reserve_amount": h.ReserveAmount, "currency": h.Currency, "reason": h.Reason,
+	}))
+	if err != nil {
+		return dto.SettlementHoldResponse{}, settlementHoldError(err, merchantID)
+	}
+	metrics.SettlementHoldChanges.WithLabelValues(h.Type, "set").Inc()
+	slog.InfoContext(ctx, "settlement hold set", "settlement_hold_id", created.ID, "type", h.Type)
+	return dto.NewSettlementHoldResponse(created), nil
+}
+
+// ReleaseSettlementHold ends the settlement hold in effect on a merchant.
+// Payouts already held for review stay in the review queue.
+func (s *AdminService) ReleaseSettlementHold(ctx context.Context, merchantID int, req dto.ReleaseSettlementHoldRequest, actor Actor) (dto.SettlementHoldResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if err := validateReason(reason); err != nil {
+		return dto.SettlementHoldResponse{}, err
+	}
+	released, err := s.repo.ReleaseSettlementHold(ctx, merchantID, actor.ID, reason,
+		actor.audit("merchant", "settlement_hold.released", map[string]interface{}{"reason": reason}))
+	if err != nil {
+		return dto.SettlementHoldResponse{}, settlementHoldError(err, merchantID)
+	}
+	metrics.SettlementHoldChanges.WithLabelValues(released.Type, "released").Inc()
+	slog.InfoContext(ctx, "settlement hold released", "settlement_hold_id", released.ID, "type", released.Type)
+	return dto.NewSettlementHoldResponse(released), nil
+}
+
+// MerchantSettlement returns the settlement hold in effect on a merchant and
+// their hold history.
+func (s *AdminService) MerchantSettlement(ctx context.Context, merchantID int) (dto.MerchantSettlementResponse, error) {
+	if _, err := s.GetMerchant(ctx, merchantID); err != nil {
+		return dto.MerchantSettlementResponse{}, err
+	}
+	holds, err := s.repo.ListSettlementHolds(ctx, models.SettlementHoldFilter{MerchantID: merchantID})
+	if err != nil {
+		return dto.MerchantSettlementResponse{}, repositoryError(err)
+	}
+	resp := dto.MerchantSettlementResponse{MerchantID: merchantID, History: []dto.SettlementHoldResponse{}}
+	for _, h := range holds {
+		r := dto.NewSettlementHoldResponse(h)
+		if r.Active {
+			resp.Active = &r
+		}
+		resp.History = append(resp.History, r)
+	}
+	return resp, nil
+}
+
+// ListSettlementHolds lists settlement holds, newest first, for the payout
+// flow to apply.
+func (s *AdminService) ListSettlementHolds(ctx context.Context, q dto.SettlementHoldListQuery) (dto.SettlementHoldListResponse, error) {
+	if q.Type != "" && !settlementHoldTypes[q.Type] {
+		return dto.SettlementHoldListResponse{}, newError(ErrValidation, "invalid_hold_type",
+			fmt.Sprintf("type %q must be one of full_hold, rolling_reserve, fixed_reserve", q.Type), nil)
+	}
+	holds, err := s.repo.ListSettlementHolds(ctx, models.SettlementHoldFilter{
+		MerchantID: q.MerchantID, Type: q.Type, ActiveOnly: !q.IncludeReleased, Limit: q.Limit,
+	})
+	if err != nil {
+		return dto.SettlementHoldListResponse{}, repositoryError(err)
+	}
+	resp := dto.SettlementHoldListResponse{Holds: []dto.SettlementHoldResponse{}, Total: len(holds)}
+	for _, h := range holds {
+		resp.Holds = append(resp.Holds, dto.NewSettlementHoldResponse(h))
+	}
+	return resp, nil
+}
+
+// fullSettlementHolds returns the full holds in effect, by merchant.
+func (s *AdminService) fullSettlementHolds(ctx context.Context) (map[int]models.SettlementHold, error) {
+	holds, err := s.repo.ListSettlementHolds(ctx, models.SettlementHoldFilter{
+		Type: models.SettlementFullHold, ActiveOnly: true, Limit: maxSettlementHolds,
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	byMerchant := make(map[int]models.SettlementHold, len(holds))
+	for _, h := range holds {
+		byMerchant[h.MerchantID] = h
+	}
+	return byMerchant, nil
+}
+
+func settlementHoldError(err error, merchantID int) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return newError(ErrNotFound, "settlement_hold_not_found", fmt.Sprintf("merchant %d has no settlement hold in effect", merchantID), err)
+	case errors.Is(err, repositories.ErrConflict):
+		return newError(ErrConflict, "settlement_hold_conflict", "the merchant's settlement hold was changed concurrently", err)
+	default:
+		return repositoryError(err)
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/dto"
+	"github.com/kodra-pay/admin-service/internal/models"
+	"github.com/kodra-pay/admin-service/internal/repositories"
+)
+
+func TestSettlementHolds(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	now := time.Now().UTC()
+	for id := 1; id <= 2; id++ {
+		store.AddMerchant(models.Merchant{ID: id, Status: "active", CreatedAt: now.Add(-90 * 24 * time.Hour)})
+	}
+	store.AddPayout(models.Payout{ID: 1, MerchantID: 1, Amount: 1000, Currency: "NGN", Status: models.PayoutStatusPending, CreatedAt: now})
+	store.AddPayout(models.Payout{ID: 2, MerchantID: 2, Amount: 1000, Currency: "NGN", Status: models.PayoutStatusPending, CreatedAt: now})
+	svc := newTestService(t, store)
+	txClient := &fakeTransactionClient{}
+	svc.TransactionClient = txClient
+	analyst := Actor{ID: "analyst-1"}
+
+	full, err := svc.SetSettlementHold(ctx, 1, dto.SettlementHoldRequest{Type: models.SettlementFullHold, ReservePercent: 50, Reason: "chargeback spike"}, analyst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !full.Active || full.ReservePercent != 0 || full.CreatedBy != "analyst-1" {
+		t.Errorf("full hold %+v", full)
+	}
+	if _, err := svc.SetSettlementHold(ctx, 2, dto.SettlementHoldRequest{Type: models.SettlementFixedReserve, ReserveAmount: 2500.5, Currency: "ngn", Reason: "new category"}, analyst); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the merchant under a full hold has its payout held
+	result, err := svc.ScreenPayouts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (dto.PayoutScreeningResponse{Screened: 2, Held: 1}) {
+		t.Fatalf("screening = %+v", result)
+	}
+	held, err := svc.GetPayout(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if held.Hold == nil || held.Hold.Rule != models.PayoutRuleSettlementHold || held.Hold.Reason != "merchant's settlement is on full hold: chargeback spike" {
+		t.Errorf("payout hold %+v", held.Hold)
+	}
+
+	reserve, err := svc.SetSettlementHold(ctx, 1, dto.SettlementHoldRequest{
+		Type: models.SettlementRollingReserve, ReservePercent: 10, ReleaseDays: 90, ReserveAmount: 100, Reason: "volume back to normal",
+	}, Actor{ID: "analyst-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reserve.ReservePercent != 10 || reserve.ReleaseDays != 90 || reserve.ReserveAmount != 0 {
+		t.Errorf("rolling reserve %+v", reserve)
+	}
+	settlement, err := svc.MerchantSettlement(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settlement.Active == nil || settlement.Active.ID != reserve.ID || len(settlement.History) != 2 ||
+		settlement.History[1].Active || settlement.History[1].ReleasedBy != "analyst-2" {
+		t.Errorf("merchant settlement %+v", settlement)
+	}
+
+	active, err := svc.ListSettlementHolds(ctx, dto.SettlementHoldListQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active.Total != 2 || active.Holds[1].Type != models.SettlementFixedReserve || active.Holds[1].ReserveAmount != 2500.5 || active.Holds[1].Currency != "NGN" {
+		t.Errorf("active holds %+v", active)
+	}
+
+	released, err := svc.ReleaseSettlementHold(ctx, 1, dto.ReleaseSettlementHoldRequest{Reason: "reserve no longer needed"}, analyst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released.Active || released.ReleaseReason != "reserve no longer needed" {
+		t.Errorf("released %+v", released)
+	}
+	if settlement, err = svc.MerchantSettlement(ctx, 1); err != nil || settlement.Active != nil {
+		t.Errorf("merchant settlement after release %+v, %v", settlement, err)
+	}
+	var svcErr *Error
+	_, err = svc.ReleaseSettlementHold(ctx, 1, dto.ReleaseSettlementHoldRequest{Reason: "again"}, analyst)
+	if !errors.As(err, &svcErr) || svcErr.Code != "settlement_hold_not_found" {
+		t.Errorf("releasing without a hold: err = %v, want settlement_hold_not_found", err)
+	}
+	_, err = svc.SetSettlementHold(ctx, 99, dto.SettlementHoldRequest{Type: models.SettlementFullHold, Reason: "unknown"}, analyst)
+	if !errors.As(err, &svcErr) || svcErr.Code != "merchant_not_found" {
+		t.Errorf("holding a missing merchant: err = %v, want merchant_not_found", err)
+	}
+	if _, err := svc.ListSettlementHolds(ctx, dto.SettlementHoldListQuery{Type: "partial"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown type: err = %v, want ErrValidation", err)
+	}
+
+	log := store.AuditLog()
+	if last := log[len(log)-1]; last.Action != "settlement_hold.released" || last.TargetType != "merchant" || last.TargetID != "1" {
+		t.Errorf("last audit entry %+v", last)
+	}
+}
+
+func TestSettlementHoldValidation(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryStore()
+	store.AddMerchant(models.Merchant{ID: 1, Status: "active"})
+	svc := newTestService(t, store)
+
+	tests := []struct {
+		name string
+		req  dto.SettlementHoldRequest
+		code string
+	}{
+		{"unknown type", dto.SettlementHoldRequest{Type: "partial", Reason: "r"}, "invalid_hold_type"},
+		{"no reason", dto.SettlementHoldRequest{Type: models.SettlementFullHold}, "reason_required"},
+		{"no percent", dto.SettlementHoldRequest{Type: models.SettlementRollingReserve, ReleaseDays: 90, Reason: "r"}, "invalid_reserve_percent"},
+		{"percent above 100", dto.SettlementHoldRequest{Type: models.SettlementRollingReserve, ReservePercent: 120, ReleaseDays: 90, Reason: "r"}, "invalid_reserve_percent"},
+		{"no release period", dto.SettlementHoldRequest{Type: models.SettlementRollingReserve, ReservePercent: 10, Reason: "r"}, "invalid_release_days"},
+		{"release period too long", dto.SettlementHoldRequest{Type: models.SettlementRollingReserve, ReservePercent: 10, ReleaseDays: 400, Reason: "r"}, "invalid_release_days"},
+		{"no amount", dto.SettlementHoldRequest{Type: models.SettlementFixedReserve, Currency: "NGN", Reason: "r"}, "invalid_reserve_amount"},
+		{"no currency", dto.SettlementHoldRequest{Type: models.SettlementFixedReserve, ReserveAmount: 100, Reason: "r"}, "invalid_currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SetSettlementHold(ctx, 1, tt.req, Actor{ID: "analyst-1"})
+			var svcErr *Error
+			if !errors.As(err, &svcErr) || svcErr.Code != tt.code {
+				t.Errorf("got %v, want code %s", err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings are runtime tunables that can change without a restart.
+type Settings struct {
+	// DownstreamTimeout bounds each call to the merchant, compliance and transaction services.
+	DownstreamTimeout Duration `yaml:"downstream_timeout" json:"downstream_timeout"`
+	// StatsCacheTTL is how long /admin/stats results are reused; zero disables caching.
+	StatsCacheTTL         Duration `yaml:"stats_cache_ttl" json:"stats_cache_ttl"`
+	FraudListDefaultLimit int      `yaml:"fraud_list_default_limit" json:"fraud_list_default_limit"`
+	FraudListMaxLimit     int      `yaml:"fraud_list_max_limit" json:"fraud_list_max_limit"`
+	// FraudCaseSLA is how long a fraud case may stay unresolved before it is flagged as breaching.
+	FraudCaseSLA Duration  `yaml:"fraud_case_sla" json:"fraud_case_sla"`
+	RateLimit    RateLimit `yaml:"rate_limit" json:"rate_limit"`
+	// FeatureFlags switch the background jobs on and off, by the names in Flags.
+	FeatureFlags map[string]bool `yaml:"feature_flags" json:"feature_flags"`
+	// PayoutReview selects the pending payouts held for manual review.
+	PayoutReview PayoutReview `yaml:"payout_review" json:"payout_review"`
+}
+
+// Feature flags, each named after the background job it switches. Every
+// flag is on unless the settings file turns it off.
+const (
+	FlagFraudCaseSync      = "fraud_case_sync"
+	FlagBlocklistRefresh   = "blocklist_refresh"
+	FlagRiskScore          = "risk_score"
+	FlagAnomalyDetection   = "anomaly_detection"
+	FlagSuspensionPolicies = "suspension_policies"
+	FlagPayoutReview       = "payout_review"
+)
+
+// Flags are the feature flags a settings file may set.
+var Flags = []string{FlagFraudCaseSync, FlagBlocklistRefresh, FlagRiskScore, FlagAnomalyDetection, FlagSuspensionPolicies, FlagPayoutReview}
+
+// PayoutReview holds a payout for manual review when its amount reaches the
+// threshold for its currency, or its merchant signed up less than
+// NewMerchantAge before it. Currencies without a threshold and a zero age
+// are not checked.
+type PayoutReview struct {
+	// Thresholds are in minor currency units, by currency code
+	Thresholds     map[string]int64 `yaml:"thresholds" json:"thresholds"`
+	NewMerchantAge Duration         `yaml:"new_merchant_age" json:"new_merchant_age"`
+}
+
+// RateLimit allows Requests per Window for each client; zero Requests disables it.
+type RateLimit struct {
+	Requests int      `yaml:"requests" json:"requests"`
+	Window   Duration `yaml:"window" json:"window"`
+}
+
+// Defaults are used for values missing from the settings file.
+func Defaults() Settings {
+	return Settings{
+		DownstreamTimeout:     Duration(10 * time.Second),
+		StatsCacheTTL:         Duration(30 * time.Second),
+		FraudListDefaultLimit: 50,
+		FraudListMaxLimit:     500,
+		FraudCaseSLA:          Duration(24 * time.Hour),
+		RateLimit:             RateLimit{Requests: 0, Window: Duration(time.Minute)},
+		FeatureFlags:          defaultFlags(),
+		PayoutReview:          PayoutReview{Thresholds: map[string]int64{}},
+	}
+}
+
+// Validate rejects settings that would break request handling.
+func (s Settings) Validate() error {
+	var errs []error
+	if s.DownstreamTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("downstream_timeout must be positive"))
+	}
+	if s.StatsCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("stats_cache_ttl must not be negative"))
+	}
+	if s.FraudListDefaultLimit <= 0 {
+		errs = append(errs, fmt.Errorf("fraud_list_default_limit must be positive"))
+	}
+	if s.FraudListMaxLimit < s.FraudListDefaultLimit {
+		errs = append(errs, fmt.Errorf("fraud_list_max_limit must be at least fraud_list_default_limit"))
+	}
+	if s.FraudCaseSLA <= 0 {
+		errs = append(errs, fmt.Errorf("fraud_case_sla must be positive"))
+	}
+	if s.RateLimit.Requests < 0 {
+		errs = append(errs, fmt.Errorf("rate_limit.requests must not be negative"))
+	}
+	if s.RateLimit.Requests > 0 && s.RateLimit.Window <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit.window must be positive when rate limiting is enabled"))
+	}
+	for currency, threshold := range s.PayoutReview.Thresholds {
+		if threshold <= 0 {
+			errs = append(errs, fmt.Errorf("payout_review.thresholds.%s must be positive", currency))
+		}
+	}
+	for flag := range s.FeatureFlags {
+		if !slices.Contains(Flags, flag) {
+			errs = append(errs, fmt.Errorf("feature_flags.%s is not a known flag", flag))
+		}
+	}
+	if s.PayoutReview.NewMerchantAge < 0 {
+		errs = append(errs, fmt.Errorf("payout_review.new_merchant_age must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func defaultFlags() map[string]bool {
+	flags := make(map[string]bool, len(Flags))
+	for _, flag := range Flags {
+		flags[flag] = true
+	}
+	return flags
+}
+
+// Duration is a time.Duration written as "30s" in settings files and JSON.
+type Duration time.Duration
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,116 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Store holds the effective settings and reloads them when the settings file
+// changes. Readers always see a complete, validated snapshot.
+type Store struct {
+	path     string
+	current  atomic.Pointer[Snapshot]
+	lastMod  time.Time
+	lastSize int64
+}
+
+// Snapshot is a set of settings together with where and when they were loaded.
+type Snapshot struct {
+	Settings
+	Source   string    `json:"source"`
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// NewStore loads settings from the YAML or JSON file at path. With an empty
+// path the defaults are used and never change.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if path == "" {
+		s.current.Store(&Snapshot{Settings: Defaults(), Source: "defaults", LoadedAt: time.Now().UTC()})
+		return s, nil
+	}
+	if _, err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the current settings. The result must not be modified.
+func (s *Store) Get() Settings {
+	return s.current.Load().Settings
+}
+
+// Snapshot returns the current settings with their source.
+func (s *Store) Snapshot() Snapshot {
+	return *s.current.Load()
+}
+
+// Enabled reports whether the named feature flag is on.
+func (s *Store) Enabled(flag string) bool {
+	return s.Get().FeatureFlags[flag]
+}
+
+// Watch polls the settings file every interval until ctx is done, applying
+// valid changes. An invalid file is logged and the previous settings kept.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	if s.path == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := s.reload()
+			if err != nil {
+				slog.Error("failed to reload runtime settings, keeping previous values", "path", s.path, "error", err)
+			} else if changed {
+				slog.Info("runtime settings reloaded", "path", s.path)
+			}
+		}
+	}
+}
+
+// reload reads the file if it changed since the last successful load.
+func (s *Store) reload() (bool, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat settings file: %w", err)
+	}
+	if info.ModTime().Equal(s.lastMod) && info.Size() == s.lastSize {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	// Remember this version even if it is invalid so it is reported once.
+	s.lastMod = info.ModTime()
+	s.lastSize = info.Size()
+
+	next := Defaults()
+	if err := yaml.Unmarshal(data, &next); err != nil {
+		return false, fmt.Errorf("failed to parse settings file %s: %w", s.path, err)
+	}
+	if next.FeatureFlags == nil {
+		next.FeatureFlags = defaultFlags()
+	}
+	if next.PayoutReview.Thresholds == nil {
+		next.PayoutReview.Thresholds = map[string]int64{}
+	}
+	if err := next.Validate(); err != nil {
+		return false, fmt.Errorf("invalid settings in %s: %w", s.path, err)
+	}
+
+	s.current.Store(&Snapshot{Settings: next, Source: s.path, LoadedAt: time.Now().UTC()})
+	return true, nil
+}
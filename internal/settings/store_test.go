@@ -0,0 +1,144 @@
+package settings
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSettings replaces the settings file, moving its modification time on
+// so the change is seen even on filesystems with coarse timestamps.
+func writeSettings(t *testing.T, path, content string) {
+	t.Helper()
+	var mod time.Time
+	if info, err := os.Stat(path); err == nil {
+		mod = info.ModTime().Add(time.Second)
+	} else {
+		mod = time.Now()
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestStore(t *testing.T, content string) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeSettings(t, path, content)
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func TestNewStore(t *testing.T) {
+	s, err := NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap := s.Snapshot(); snap.Source != "defaults" || snap.DownstreamTimeout != Defaults().DownstreamTimeout || !s.Enabled(FlagRiskScore) {
+		t.Errorf("defaults snapshot %+v", snap)
+	}
+
+	s, path := newTestStore(t, "downstream_timeout: 3s\nfeature_flags:\n  risk_score: false\n")
+	if got := s.Get(); got.DownstreamTimeout.Std() != 3*time.Second || got.FraudListMaxLimit != Defaults().FraudListMaxLimit {
+		t.Errorf("loaded settings %+v", got)
+	}
+	if s.Snapshot().Source != path {
+		t.Errorf("source %q, want %q", s.Snapshot().Source, path)
+	}
+	// flags the file leaves out stay on
+	if s.Enabled(FlagRiskScore) || !s.Enabled(FlagPayoutReview) {
+		t.Errorf("flags %v", s.Get().FeatureFlags)
+	}
+
+	for name, content := range map[string]string{
+		"invalid value": "downstream_timeout: 0s\n",
+		"unknown flag":  "feature_flags:\n  risk_scores: false\n",
+		"malformed":     "downstream_timeout: [\n",
+	} {
+		path := filepath.Join(t.TempDir(), "settings.yaml")
+		writeSettings(t, path, content)
+		if _, err := NewStore(path); err == nil {
+			t.Errorf("%s: NewStore succeeded", name)
+		}
+	}
+	if _, err := NewStore(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing file: NewStore succeeded")
+	}
+}
+
+func TestStoreReload(t *testing.T) {
+	s, path := newTestStore(t, "fraud_list_default_limit: 20\n")
+
+	if changed, err := s.reload(); err != nil || changed {
+		t.Errorf("unchanged file: changed %v, err %v", changed, err)
+	}
+
+	writeSettings(t, path, "fraud_list_default_limit: 30\nrate_limit:\n  requests: 100\n  window: 30s\n")
+	if changed, err := s.reload(); err != nil || !changed {
+		t.Fatalf("changed file: changed %v, err %v", changed, err)
+	}
+	if got := s.Get(); got.FraudListDefaultLimit != 30 || got.RateLimit.Requests != 100 || got.RateLimit.Window.Std() != 30*time.Second {
+		t.Errorf("reloaded settings %+v", got)
+	}
+
+	// an invalid file is rejected and the last good snapshot kept
+	good := s.Snapshot()
+	for _, content := range []string{"fraud_list_default_limit: 1000\n", "rate_limit: [\n"} {
+		writeSettings(t, path, content)
+		if _, err := s.reload(); err == nil {
+			t.Errorf("reloading %q succeeded", content)
+		}
+		if snap := s.Snapshot(); snap.FraudListDefaultLimit != 30 || !snap.LoadedAt.Equal(good.LoadedAt) {
+			t.Errorf("after rejecting %q: %+v", content, snap)
+		}
+		// the rejected version is reported once, not on every poll
+		if changed, err := s.reload(); err != nil || changed {
+			t.Errorf("rejected file polled again: changed %v, err %v", changed, err)
+		}
+	}
+
+	writeSettings(t, path, "fraud_list_default_limit: 40\n")
+	if _, err := s.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get(); got.FraudListDefaultLimit != 40 || got.RateLimit.Requests != 0 {
+		t.Errorf("settings after fixing the file %+v", got)
+	}
+}
+
+func TestStoreWatch(t *testing.T) {
+	s, path := newTestStore(t, "stats_cache_ttl: 10s\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Watch(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	writeSettings(t, path, "stats_cache_ttl: 1m\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Get().StatsCacheTTL.Std() != time.Minute {
+		if time.Now().After(deadline) {
+			t.Fatalf("settings not reloaded: %+v", s.Get())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	writeSettings(t, path, "stats_cache_ttl: -1s\n")
+	time.Sleep(50 * time.Millisecond)
+	if got := s.Get().StatsCacheTTL.Std(); got != time.Minute {
+		t.Errorf("invalid file applied: stats_cache_ttl %s", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Package suspension evaluates suspension policies against merchants'
+// activity. A policy matches a merchant when the value of its condition over
+// the policy window reaches the threshold: a fraction of the merchant's
+// payments for the ratio conditions, a count for kyc_rejections.
+//
+// Payment outcomes are read from transaction statuses like in package risk.
+package suspension
+
+import (
+	"time"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+// MaxWindowDays bounds how far back a policy looks.
+const MaxWindowDays = 365
+
+// ValidCondition reports whether c is a policy condition.
+func ValidCondition(c string) bool {
+	return IsRatio(c) || c == models.PolicyKYCRejections
+}
+
+// IsRatio reports whether condition c is a fraction of a merchant's payments.
+func IsRatio(c string) bool {
+	switch c {
+	case models.PolicyChargebackRatio, models.PolicyFailedRatio, models.PolicyRefundRatio:
+		return true
+	}
+	return false
+}
+
+// ValidAction reports whether a is a policy action.
+func ValidAction(a string) bool {
+	return a == models.PolicyActionSuspend || a == models.PolicyActionRecommend
+}
+
+// Window returns how far back p looks.
+func Window(p models.SuspensionPolicy) time.Duration {
+	return time.Duration(p.WindowDays) * 24 * time.Hour
+}
+
+// Activity returns the query for p's window, ending at now.
+func Activity(p models.SuspensionPolicy, now time.Time) models.ActivityQuery {
+	return models.ActivityQuery{Now: now, RatioWindow: Window(p)}
+}
+
+// Evaluate returns the value of p's condition for a merchant's activity,
+// measured by Activity, and whether it matches. Ratio conditions never match
+// merchants with fewer than p.MinTransactions payments, or none.
+func Evaluate(p models.SuspensionPolicy, a models.MerchantActivity) (float64, bool) {
+	var count int
+	switch p.Condition {
+	case models.PolicyKYCRejections:
+		value := float64(a.KYCRejections)
+		return value, value >= p.Threshold
+	case models.PolicyChargebackRatio:
+		count = a.Chargebacks
+	case models.PolicyFailedRatio:
+		count = a.Failed
+	case models.PolicyRefundRatio:
+		count = a.Refunded
+	default:
+		return 0, false
+	}
+	if a.Transactions == 0 || a.Transactions < p.MinTransactions {
+		return 0, false
+	}
+	value := float64(count) / float64(a.Transactions)
+	return value, value >= p.Threshold
+}
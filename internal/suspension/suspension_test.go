@@ -0,0 +1,42 @@
+package suspension
+
+import (
+	"testing"
+
+	"github.com/kodra-pay/admin-service/internal/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	activity := models.MerchantActivity{Transactions: 200, Failed: 40, Refunded: 2, Chargebacks: 3, KYCRejections: 2}
+	policy := func(condition string, threshold float64, minTransactions int) models.SuspensionPolicy {
+		return models.SuspensionPolicy{Condition: condition, Threshold: threshold, WindowDays: 30, MinTransactions: minTransactions}
+	}
+
+	tests := []struct {
+		name    string
+		policy  models.SuspensionPolicy
+		value   float64
+		matches bool
+	}{
+		{"chargebacks above threshold", policy(models.PolicyChargebackRatio, 0.015, 20), 0.015, true},
+		{"chargebacks below threshold", policy(models.PolicyChargebackRatio, 0.02, 20), 0.015, false},
+		{"failures", policy(models.PolicyFailedRatio, 0.2, 0), 0.2, true},
+		{"refunds", policy(models.PolicyRefundRatio, 0.05, 0), 0.01, false},
+		{"too few payments", policy(models.PolicyChargebackRatio, 0.01, 500), 0, false},
+		{"kyc rejected twice", policy(models.PolicyKYCRejections, 2, 500), 2, true},
+		{"kyc rejected three times", policy(models.PolicyKYCRejections, 3, 0), 2, false},
+		{"unknown condition", policy("velocity", 0, 0), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, matches := Evaluate(tt.policy, activity)
+			if value != tt.value || matches != tt.matches {
+				t.Errorf("Evaluate = %v, %v, want %v, %v", value, matches, tt.value, tt.matches)
+			}
+		})
+	}
+
+	if _, matches := Evaluate(policy(models.PolicyFailedRatio, 0, 0), models.MerchantActivity{}); matches {
+		t.Error("a ratio matched a merchant without payments")
+	}
+}